@@ -0,0 +1,153 @@
+// links выгружает и загружает ссылки напрямую через базу, минуя HTTP API.
+//
+//	links export [-format csv|jsonl] [-owner id] [-domain host] [-from 2006-01-02] [-to 2006-01-02] [-utm-campaign name] [-o file]
+//	links import [-owner id] [-map column=field,...] [-on-duplicate skip|overwrite|fail] [-dry-run] file.csv
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"leenwood/yandex-http/config"
+	"leenwood/yandex-http/internal/usecase"
+	"leenwood/yandex-http/internal/usecase/dto"
+	"os"
+	"time"
+)
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+	}
+
+	cfg := config.NewConfig()
+	ctx := context.Background()
+
+	// Кэш сервера в другом процессе не сбрасывается, изменённые ссылки он увидит не позже чем через URL_CACHE_TTL_SECONDS
+	urls, _, err := usecase.NewUrlRepository(ctx, cfg)
+	if err != nil {
+		fail(err)
+	}
+	us, err := usecase.NewTransferUseCase(ctx, cfg, urls)
+	if err != nil {
+		fail(err)
+	}
+
+	switch os.Args[1] {
+	case "export":
+		err = runExport(us, os.Args[2:])
+	case "import":
+		err = runImport(us, os.Args[2:])
+	default:
+		usage()
+	}
+	if err != nil {
+		fail(err)
+	}
+}
+
+func runExport(us usecase.TransferUseCaseInterface, args []string) error {
+	var (
+		request        dto.ExportUrlsRequest
+		from, to, path string
+	)
+	flags := flag.NewFlagSet("export", flag.ExitOnError)
+	flags.StringVar(&request.Format, "format", usecase.ExportCsv, "csv or jsonl")
+	flags.StringVar(&request.OwnerId, "owner", "", "export links of this owner only")
+	flags.StringVar(&request.Domain, "domain", "", "export links of this branded domain only")
+	flags.StringVar(&from, "from", "", "first creation date, 2006-01-02")
+	flags.StringVar(&to, "to", "", "last creation date, 2006-01-02")
+	flags.StringVar(&request.UtmSource, "utm-source", "", "filter by utm_source")
+	flags.StringVar(&request.UtmMedium, "utm-medium", "", "filter by utm_medium")
+	flags.StringVar(&request.UtmCampaign, "utm-campaign", "", "filter by utm_campaign")
+	flags.StringVar(&path, "o", "", "output file, stdout by default")
+	_ = flags.Parse(args)
+
+	var err error
+	if request.From, err = parseDate(from); err != nil {
+		return err
+	}
+	if request.To, err = parseDate(to); err != nil {
+		return err
+	}
+
+	var output io.Writer = os.Stdout
+	if path != "" {
+		file, err := os.Create(path)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		output = file
+	}
+
+	return us.ExportUrls(request, output)
+}
+
+func runImport(us usecase.TransferUseCaseInterface, args []string) error {
+	var (
+		request dto.ImportUrlsRequest
+		mapping string
+	)
+	flags := flag.NewFlagSet("import", flag.ExitOnError)
+	flags.StringVar(&request.OwnerId, "owner", "", "owner of imported links")
+	flags.StringVar(&mapping, "map", "", "column mapping, e.g. long_url=url,link=id")
+	flags.StringVar(&request.OnDuplicate, "on-duplicate", usecase.DuplicateSkip, "skip, overwrite or fail")
+	flags.BoolVar(&request.DryRun, "dry-run", false, "validate the file without saving")
+	_ = flags.Parse(args)
+	if flags.NArg() != 1 {
+		usage()
+	}
+
+	var err error
+	if request.Mapping, err = usecase.ParseColumnMapping(mapping); err != nil {
+		return err
+	}
+	request.Progress = func(done, total int) {
+		fmt.Fprintf(os.Stderr, "saved %d/%d\n", done, total)
+	}
+
+	file, err := os.Open(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	report, err := us.ImportUrls(request, file)
+	if err != nil {
+		return err
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err = encoder.Encode(report); err != nil {
+		return err
+	}
+	if report.Aborted || report.Failed > 0 {
+		os.Exit(1)
+	}
+	return nil
+}
+
+func parseDate(value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	date, err := time.Parse(time.DateOnly, value)
+	if err != nil {
+		return nil, err
+	}
+	return &date, nil
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: links export [flags] | links import [flags] file.csv")
+	os.Exit(2)
+}
+
+func fail(err error) {
+	fmt.Fprintln(os.Stderr, err)
+	os.Exit(1)
+}
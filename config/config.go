@@ -1,15 +1,136 @@
 package config
 
-import "os"
+import (
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
 
 type Config struct {
-	App      AppConfig
-	Database DatabaseConfig
+	App       AppConfig
+	Database  DatabaseConfig
+	RateLimit RateLimitConfig
+	Auth      AuthConfig
+	Quota     QuotaConfig
+	GeoIp     GeoIpConfig
+	Fetch     FetchConfig
+	Health    HealthConfig
+	Chain     ChainConfig
+	Cache     CacheConfig
 }
 
 type AppConfig struct {
 	Hostname string
 	Port     string
+	// PublicBaseUrl - внешний адрес сервиса (схема, хост и необязательный префикс пути),
+	// от которого строятся короткие ссылки
+	PublicBaseUrl string
+	// TrustedProxies - адреса и подсети прокси, чьим заголовкам X-Forwarded-* можно доверять
+	TrustedProxies []string
+	// DefaultRedirectType - способ перенаправления для ссылок без собственной настройки
+	DefaultRedirectType string
+	// PermanentRedirectMaxAge - время кэширования постоянных перенаправлений
+	PermanentRedirectMaxAge time.Duration
+	// UserAgentCacheSize - сколько разобранных заголовков User-Agent хранится в памяти
+	UserAgentCacheSize int
+	// QrLogoPath - PNG-логотип, который можно разместить в центре QR-кодов ссылок
+	QrLogoPath string
+}
+
+type RateLimitConfig struct {
+	// Store - хранилище счётчиков: memory или postgres
+	Store    string
+	Create   LimitConfig
+	List     LimitConfig
+	Redirect LimitConfig
+	// Unlock - попытки ввода пароля защищённой ссылки, считаются для каждой ссылки
+	Unlock LimitConfig
+}
+
+// LimitConfig - лимит token bucket, PerMinute равный нулю отключает ограничение
+type LimitConfig struct {
+	PerMinute int
+	Burst     int
+}
+
+type AuthConfig struct {
+	// ApiKeys сопоставляет API-ключ с идентификатором владельца
+	ApiKeys map[string]string
+	// AdminKeys - ключи доступа к административным маршрутам, без ключей маршруты отключены
+	AdminKeys []string
+	// CookieSecret - ключ подписи cookie доступа к защищённым ссылкам,
+	// если не задан, генерируется при запуске
+	CookieSecret string
+	// UnlockTTL - время жизни cookie доступа к защищённой ссылке
+	UnlockTTL time.Duration
+}
+
+// QuotaConfig - тариф по умолчанию для владельцев без собственной квоты.
+// Нулевое значение лимита означает отсутствие ограничения.
+type QuotaConfig struct {
+	MaxLinks         uint64
+	MaxCustomAliases uint64
+	MaxMonthlyClicks uint64
+	AllowCustomId    bool
+	// AnonymousDailyLinks - сколько анонимных ссылок можно создать с одного адреса за сутки, ноль не ограничивает
+	AnonymousDailyLinks uint64
+}
+
+type GeoIpConfig struct {
+	// DatabasePath - путь к базе стран или городов MaxMind (.mmdb), пустой путь отключает определение страны
+	DatabasePath string
+	// AsnDatabasePath - путь к базе ASN MaxMind, необязательный
+	AsnDatabasePath string
+	// ReloadInterval - как часто проверять, не заменён ли файл базы
+	ReloadInterval time.Duration
+}
+
+// FetchConfig - ограничения запросов сервиса к страницам назначения
+type FetchConfig struct {
+	// Timeout - общее время на запрос вместе с перенаправлениями и чтением ответа
+	Timeout time.Duration
+	// MaxBodySize - сколько байт ответа читается, остальное отбрасывается
+	MaxBodySize  int64
+	MaxRedirects int
+	// AllowPrivateNetworks разрешает запросы к локальным и внутренним адресам, нужен только для разработки
+	AllowPrivateNetworks bool
+	// MetadataWorkers и MetadataQueueSize - фоновая загрузка сведений о страницах новых ссылок,
+	// ноль обработчиков отключает загрузку
+	MetadataWorkers   int
+	MetadataQueueSize int
+}
+
+// HealthConfig - периодическая проверка страниц назначения, запросы ограничены настройками Fetch
+type HealthConfig struct {
+	// Interval - как часто проверяется каждая ссылка, ноль отключает проверку
+	Interval time.Duration
+	// Concurrency - сколько страниц проверяется одновременно
+	Concurrency int
+	// HostDelay - наименьший промежуток между запросами к одному хосту
+	HostDelay time.Duration
+}
+
+// ChainConfig - что делать с адресами назначения, которые сами являются короткими ссылками
+type ChainConfig struct {
+	// SelfLinks - resolve заменяет свою короткую ссылку её адресом назначения, reject отклоняет такие адреса
+	SelfLinks string
+	// MaxHops - наибольшая длина цепочки своих ссылок, более длинная цепочка считается петлёй
+	MaxHops int
+	// ExternalHops - сколько перенаправлений внешних сокращателей проходится при создании ссылки, ноль отключает
+	ExternalHops int
+	// ShortenerHosts - хосты внешних сокращателей, по перенаправлениям остальных сайтов не идём
+	ShortenerHosts []string
+}
+
+// CacheConfig - кэш ссылок в памяти для перенаправлений
+type CacheConfig struct {
+	// Size - сколько ссылок хранится, ноль отключает кэш
+	Size int
+	// TTL - сколько живёт найденная ссылка. Изменения из других процессов видны не позже чем через TTL.
+	TTL time.Duration
+	// NegativeTTL - сколько помнится, что ссылки с таким id нет
+	NegativeTTL time.Duration
 }
 
 type DatabaseConfig struct {
@@ -23,8 +144,14 @@ type DatabaseConfig struct {
 func NewConfig() Config {
 	return Config{
 		App: AppConfig{
-			Hostname: getEnv("HOSTNAME", "localhost"),
-			Port:     getEnv("PORT", "9000"),
+			Hostname:                getEnv("HOSTNAME", "localhost"),
+			Port:                    getEnv("PORT", "9000"),
+			PublicBaseUrl:           getEnv("PUBLIC_BASE_URL", ""),
+			TrustedProxies:          getEnvList("TRUSTED_PROXIES", nil),
+			DefaultRedirectType:     getEnv("DEFAULT_REDIRECT_TYPE", "307"),
+			PermanentRedirectMaxAge: time.Duration(getEnvInt("PERMANENT_REDIRECT_MAX_AGE_SECONDS", 86400)) * time.Second,
+			UserAgentCacheSize:      getEnvInt("USER_AGENT_CACHE_SIZE", 10000),
+			QrLogoPath:              getEnv("QR_LOGO_PATH", ""),
 		},
 		Database: DatabaseConfig{
 			Hostname: getEnv("DATABASE_HOST", "localhost"),
@@ -33,6 +160,69 @@ func NewConfig() Config {
 			Password: getEnv("DATABASE_PASS", "postgres"),
 			Database: getEnv("DATABASE_NAME", "app_db"),
 		},
+		RateLimit: RateLimitConfig{
+			Store: getEnv("RATE_LIMIT_STORE", "memory"),
+			Create: LimitConfig{
+				PerMinute: getEnvInt("RATE_LIMIT_CREATE_PER_MINUTE", 30),
+				Burst:     getEnvInt("RATE_LIMIT_CREATE_BURST", 10),
+			},
+			List: LimitConfig{
+				PerMinute: getEnvInt("RATE_LIMIT_LIST_PER_MINUTE", 60),
+				Burst:     getEnvInt("RATE_LIMIT_LIST_BURST", 20),
+			},
+			Redirect: LimitConfig{
+				PerMinute: getEnvInt("RATE_LIMIT_REDIRECT_PER_MINUTE", 600),
+				Burst:     getEnvInt("RATE_LIMIT_REDIRECT_BURST", 100),
+			},
+			Unlock: LimitConfig{
+				PerMinute: getEnvInt("RATE_LIMIT_UNLOCK_PER_MINUTE", 5),
+				Burst:     getEnvInt("RATE_LIMIT_UNLOCK_BURST", 5),
+			},
+		},
+		Auth: AuthConfig{
+			ApiKeys:      getEnvMap("API_KEYS"),
+			AdminKeys:    getEnvList("ADMIN_API_KEYS", nil),
+			CookieSecret: getEnv("COOKIE_SECRET", ""),
+			UnlockTTL:    time.Duration(getEnvInt("UNLOCK_TTL_MINUTES", 10)) * time.Minute,
+		},
+		Quota: QuotaConfig{
+			MaxLinks:            uint64(getEnvInt("QUOTA_MAX_LINKS", 1000)),
+			MaxCustomAliases:    uint64(getEnvInt("QUOTA_MAX_CUSTOM_ALIASES", 100)),
+			MaxMonthlyClicks:    uint64(getEnvInt("QUOTA_MAX_MONTHLY_CLICKS", 0)),
+			AllowCustomId:       getEnvBool("QUOTA_ALLOW_CUSTOM_ID", true),
+			AnonymousDailyLinks: uint64(getEnvInt("QUOTA_ANONYMOUS_DAILY_LINKS", 100)),
+		},
+		GeoIp: GeoIpConfig{
+			DatabasePath:    getEnv("GEOIP_DATABASE_PATH", ""),
+			AsnDatabasePath: getEnv("GEOIP_ASN_DATABASE_PATH", ""),
+			ReloadInterval:  time.Duration(getEnvInt("GEOIP_RELOAD_SECONDS", 60)) * time.Second,
+		},
+		Fetch: FetchConfig{
+			Timeout:              time.Duration(getEnvInt("FETCH_TIMEOUT_SECONDS", 5)) * time.Second,
+			MaxBodySize:          int64(getEnvInt("FETCH_MAX_BODY_BYTES", 1<<20)),
+			MaxRedirects:         getEnvInt("FETCH_MAX_REDIRECTS", 5),
+			AllowPrivateNetworks: getEnvBool("FETCH_ALLOW_PRIVATE_NETWORKS", false),
+			MetadataWorkers:      getEnvInt("METADATA_WORKERS", 2),
+			MetadataQueueSize:    getEnvInt("METADATA_QUEUE_SIZE", 1000),
+		},
+		Chain: ChainConfig{
+			SelfLinks:    getEnv("SELF_LINKS", "resolve"),
+			MaxHops:      getEnvInt("REDIRECT_MAX_HOPS", 5),
+			ExternalHops: getEnvInt("RESOLVE_EXTERNAL_HOPS", 0),
+			ShortenerHosts: getEnvList("SHORTENER_HOSTS", []string{
+				"bit.ly", "t.co", "tinyurl.com", "goo.gl", "ow.ly", "is.gd", "buff.ly", "rebrand.ly", "cutt.ly", "t.ly", "clck.ru",
+			}),
+		},
+		Health: HealthConfig{
+			Interval:    time.Duration(getEnvInt("HEALTH_CHECK_INTERVAL_MINUTES", 1440)) * time.Minute,
+			Concurrency: getEnvInt("HEALTH_CHECK_CONCURRENCY", 8),
+			HostDelay:   time.Duration(getEnvInt("HEALTH_CHECK_HOST_DELAY_MS", 1000)) * time.Millisecond,
+		},
+		Cache: CacheConfig{
+			Size:        getEnvInt("URL_CACHE_SIZE", 10000),
+			TTL:         time.Duration(getEnvInt("URL_CACHE_TTL_SECONDS", 30)) * time.Second,
+			NegativeTTL: time.Duration(getEnvInt("URL_CACHE_NEGATIVE_TTL_SECONDS", 5)) * time.Second,
+		},
 	}
 }
 
@@ -42,3 +232,44 @@ func getEnv(key, defaultValue string) string {
 	}
 	return defaultValue
 }
+
+func getEnvInt(key string, defaultValue int) int {
+	if value, err := strconv.Atoi(os.Getenv(key)); err == nil {
+		return value
+	}
+	return defaultValue
+}
+
+func getEnvBool(key string, defaultValue bool) bool {
+	if value, err := strconv.ParseBool(os.Getenv(key)); err == nil {
+		return value
+	}
+	return defaultValue
+}
+
+// getEnvMap разбирает значение вида "key1:value1,key2:value2"
+func getEnvMap(key string) map[string]string {
+	result := make(map[string]string)
+	for _, item := range getEnvList(key, nil) {
+		k, v, ok := strings.Cut(item, ":")
+		if ok && k != "" && v != "" {
+			result[k] = v
+		}
+	}
+	return result
+}
+
+func getEnvList(key string, defaultValue []string) []string {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+
+	var result []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			result = append(result, item)
+		}
+	}
+	return result
+}
@@ -0,0 +1,79 @@
+package cache
+
+import (
+	"container/list"
+	"sync"
+)
+
+// Lru - потокобезопасный кэш ограниченного размера, при переполнении
+// вытесняется запись, к которой дольше всего не обращались
+type Lru[K comparable, V any] struct {
+	mu    sync.Mutex
+	size  int
+	items map[K]*list.Element
+	order *list.List
+}
+
+type entry[K comparable, V any] struct {
+	key   K
+	value V
+}
+
+// NewLru создаёт кэш на size записей, size меньше единицы приводится к единице
+func NewLru[K comparable, V any](size int) *Lru[K, V] {
+	if size < 1 {
+		size = 1
+	}
+	return &Lru[K, V]{size: size, items: make(map[K]*list.Element), order: list.New()}
+}
+
+func (c *Lru[K, V]) Get(key K) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element, ok := c.items[key]
+	if !ok {
+		var zero V
+		return zero, false
+	}
+	c.order.MoveToFront(element)
+	return element.Value.(*entry[K, V]).value, true
+}
+
+// Add добавляет или обновляет запись и сообщает, была ли вытеснена другая запись
+func (c *Lru[K, V]) Add(key K, value V) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if element, ok := c.items[key]; ok {
+		element.Value.(*entry[K, V]).value = value
+		c.order.MoveToFront(element)
+		return false
+	}
+
+	c.items[key] = c.order.PushFront(&entry[K, V]{key: key, value: value})
+	if c.order.Len() <= c.size {
+		return false
+	}
+
+	oldest := c.order.Back()
+	c.order.Remove(oldest)
+	delete(c.items, oldest.Value.(*entry[K, V]).key)
+	return true
+}
+
+func (c *Lru[K, V]) Remove(key K) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if element, ok := c.items[key]; ok {
+		c.order.Remove(element)
+		delete(c.items, key)
+	}
+}
+
+func (c *Lru[K, V]) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
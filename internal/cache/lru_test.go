@@ -0,0 +1,45 @@
+package cache
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestCache(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Cache Test Suite")
+}
+
+var _ = Describe("Lru", func() {
+	It("should evict the least recently used entry", func() {
+		c := NewLru[string, int](2)
+
+		Expect(c.Add("a", 1)).To(BeFalse())
+		Expect(c.Add("b", 2)).To(BeFalse())
+		_, _ = c.Get("a")
+		Expect(c.Add("c", 3)).To(BeTrue())
+
+		_, ok := c.Get("b")
+		Expect(ok).To(BeFalse())
+		value, ok := c.Get("a")
+		Expect(ok).To(BeTrue())
+		Expect(value).To(Equal(1))
+		Expect(c.Len()).To(Equal(2))
+	})
+
+	It("should update and remove entries", func() {
+		c := NewLru[string, int](2)
+		c.Add("a", 1)
+		c.Add("a", 2)
+
+		value, _ := c.Get("a")
+		Expect(value).To(Equal(2))
+
+		c.Remove("a")
+		_, ok := c.Get("a")
+		Expect(ok).To(BeFalse())
+		Expect(c.Len()).To(BeZero())
+	})
+})
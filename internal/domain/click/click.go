@@ -0,0 +1,58 @@
+package click
+
+import (
+	"leenwood/yandex-http/internal/domain/url"
+	"time"
+)
+
+// Click - учтённый переход по ссылке
+type Click struct {
+	UrlId       string    `db:"url_id"`
+	CreatedDate time.Time `db:"created_date"`
+	Country     string    `db:"country"`
+	Region      string    `db:"region"`
+	Asn         uint32    `db:"asn"`
+	Browser     string    `db:"browser"`
+	Os          string    `db:"os"`
+	// Device - тип устройства: mobile, tablet, desktop или bot
+	Device string `db:"device"`
+	// Source - откуда пришёл переход, например qr для сканирования QR-кода
+	Source string `db:"source"`
+}
+
+// SourceParam - параметр короткой ссылки с источником перехода, не передаётся в адрес перехода
+const SourceParam = "src"
+
+// SourceQr - переход по QR-коду ссылки
+const SourceQr = "qr"
+
+// Dimension - поле перехода, по которому строится разбивка статистики
+type Dimension string
+
+const (
+	DimensionCountry Dimension = "country"
+	DimensionRegion  Dimension = "region"
+	DimensionAsn     Dimension = "asn"
+	DimensionBrowser Dimension = "browser"
+	DimensionOs      Dimension = "os"
+	DimensionDevice  Dimension = "device"
+	DimensionSource  Dimension = "source"
+)
+
+// Filter - условия отбора переходов, пустые поля не учитываются
+type Filter struct {
+	UrlId   string
+	OwnerId string
+	// Utm - метки кампании ссылок, по которым были переходы
+	Utm url.Utm
+	// FolderIds - переходы по ссылкам из этих папок
+	FolderIds []string
+	From      *time.Time
+	To        *time.Time
+}
+
+// Bucket - число переходов с одним значением поля
+type Bucket struct {
+	Value  string
+	Clicks uint64
+}
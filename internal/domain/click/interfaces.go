@@ -0,0 +1,8 @@
+package click
+
+type RepositoryInterface interface {
+	Save(click *Click) error
+	Count(filter Filter) (uint64, error)
+	// CountBy возвращает не более limit значений поля с наибольшим числом переходов
+	CountBy(filter Filter, dimension Dimension, limit int) ([]Bucket, error)
+}
@@ -0,0 +1,74 @@
+package mocks
+
+import (
+	gomock "github.com/golang/mock/gomock"
+	click "leenwood/yandex-http/internal/domain/click"
+	reflect "reflect"
+)
+
+// MockRepositoryInterface is a mock of RepositoryInterface interface
+type MockRepositoryInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockRepositoryInterfaceMockRecorder
+}
+
+// MockRepositoryInterfaceMockRecorder is the mock recorder for MockRepositoryInterface
+type MockRepositoryInterfaceMockRecorder struct {
+	mock *MockRepositoryInterface
+}
+
+// NewMockRepositoryInterface creates a new mock instance
+func NewMockRepositoryInterface(ctrl *gomock.Controller) *MockRepositoryInterface {
+	mock := &MockRepositoryInterface{ctrl: ctrl}
+	mock.recorder = &MockRepositoryInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockRepositoryInterface) EXPECT() *MockRepositoryInterfaceMockRecorder {
+	return m.recorder
+}
+
+// Count mocks base method
+func (m *MockRepositoryInterface) Count(filter click.Filter) (uint64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Count", filter)
+	ret0, _ := ret[0].(uint64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Count indicates an expected call of Count
+func (mr *MockRepositoryInterfaceMockRecorder) Count(filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Count", reflect.TypeOf((*MockRepositoryInterface)(nil).Count), filter)
+}
+
+// CountBy mocks base method
+func (m *MockRepositoryInterface) CountBy(filter click.Filter, dimension click.Dimension, limit int) ([]click.Bucket, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountBy", filter, dimension, limit)
+	ret0, _ := ret[0].([]click.Bucket)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountBy indicates an expected call of CountBy
+func (mr *MockRepositoryInterfaceMockRecorder) CountBy(filter, dimension, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountBy", reflect.TypeOf((*MockRepositoryInterface)(nil).CountBy), filter, dimension, limit)
+}
+
+// Save mocks base method
+func (m *MockRepositoryInterface) Save(click *click.Click) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Save", click)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Save indicates an expected call of Save
+func (mr *MockRepositoryInterfaceMockRecorder) Save(click interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockRepositoryInterface)(nil).Save), click)
+}
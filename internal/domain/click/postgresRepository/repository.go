@@ -0,0 +1,136 @@
+package postgresRepository
+
+import (
+	"context"
+	"fmt"
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"leenwood/yandex-http/config"
+	"leenwood/yandex-http/internal/domain/click"
+)
+
+var columns = []string{"url_id", "created_date", "country", "region", "asn", "browser", "os", "device", "source"}
+
+type Repository struct {
+	db  *pgxpool.Pool
+	sq  sq.StatementBuilderType
+	ctx context.Context
+}
+
+func NewRepository(ctx context.Context, config config.DatabaseConfig) (*Repository, error) {
+	dsn := fmt.Sprintf(
+		"postgres://%s:%s@%s:%s/%s",
+		config.Username,
+		config.Password,
+		config.Hostname,
+		config.Port,
+		config.Database,
+	)
+	dbpool, err := pgxpool.New(ctx, dsn)
+	if err != nil {
+		return nil, err
+	}
+	return &Repository{
+		db:  dbpool,
+		sq:  sq.StatementBuilder.PlaceholderFormat(sq.Dollar),
+		ctx: ctx,
+	}, nil
+}
+
+func (r *Repository) Save(model *click.Click) error {
+	query, args, err := r.sq.
+		Insert("clicks").
+		Columns(columns...).
+		Values(model.UrlId, model.CreatedDate, model.Country, model.Region, model.Asn, model.Browser, model.Os, model.Device, model.Source).
+		ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = r.db.Exec(r.ctx, query, args...)
+	return err
+}
+
+func (r *Repository) Count(filter click.Filter) (uint64, error) {
+	query, args, err := r.sq.
+		Select("COUNT(*)").
+		From("clicks").
+		Join("urls ON urls.id = clicks.url_id").
+		Where(filterConditions(filter)).
+		ToSql()
+	if err != nil {
+		return 0, err
+	}
+
+	var count uint64
+	if err = r.db.QueryRow(r.ctx, query, args...).Scan(&count); err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+func (r *Repository) CountBy(filter click.Filter, dimension click.Dimension, limit int) ([]click.Bucket, error) {
+	// dimension - одна из констант click.Dimension, а не пользовательский ввод
+	column := "clicks." + string(dimension)
+	query, args, err := r.sq.
+		Select("CAST("+column+" AS TEXT)", "COUNT(*)").
+		From("clicks").
+		Join("urls ON urls.id = clicks.url_id").
+		Where(filterConditions(filter)).
+		GroupBy(column).
+		OrderBy("COUNT(*) DESC", column).
+		Limit(uint64(limit)).
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.db.Query(r.ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	buckets := []click.Bucket{}
+	for rows.Next() {
+		var bucket click.Bucket
+		if err := rows.Scan(&bucket.Value, &bucket.Clicks); err != nil {
+			return nil, err
+		}
+		buckets = append(buckets, bucket)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return buckets, nil
+}
+
+// filterConditions возвращает условия отбора по заданным полям фильтра
+func filterConditions(filter click.Filter) sq.And {
+	conditions := sq.And{}
+	for column, value := range map[string]string{
+		"clicks.url_id":     filter.UrlId,
+		"urls.owner_id":     filter.OwnerId,
+		"urls.utm_source":   filter.Utm.Source,
+		"urls.utm_medium":   filter.Utm.Medium,
+		"urls.utm_campaign": filter.Utm.Campaign,
+		"urls.utm_term":     filter.Utm.Term,
+		"urls.utm_content":  filter.Utm.Content,
+	} {
+		if value != "" {
+			conditions = append(conditions, sq.Eq{column: value})
+		}
+	}
+	if len(filter.FolderIds) > 0 {
+		conditions = append(conditions, sq.Eq{"urls.folder_id": filter.FolderIds})
+	}
+	if filter.From != nil {
+		conditions = append(conditions, sq.GtOrEq{"clicks.created_date": *filter.From})
+	}
+	if filter.To != nil {
+		conditions = append(conditions, sq.Lt{"clicks.created_date": *filter.To})
+	}
+	return conditions
+}
@@ -0,0 +1,40 @@
+package customDomain
+
+import (
+	"errors"
+	"time"
+)
+
+const (
+	// VerificationRecordPrefix - поддомен, на котором ищется TXT-запись подтверждения
+	VerificationRecordPrefix = "_yandex-http"
+	// VerificationValuePrefix - префикс значения TXT-записи подтверждения
+	VerificationValuePrefix = "yandex-http-verification="
+)
+
+var (
+	ErrNotFound           = errors.New("domain not found")
+	ErrNotVerified        = errors.New("domain is not verified")
+	ErrExists             = errors.New("domain already exists")
+	ErrInvalidHostname    = errors.New("invalid domain hostname")
+	ErrVerificationFailed = errors.New("domain verification record not found")
+)
+
+type Domain struct {
+	Hostname          string     `db:"hostname"`
+	Scheme            string     `db:"scheme"`
+	VerificationToken string     `db:"verification_token"`
+	Verified          bool       `db:"verified"`
+	VerifiedDate      *time.Time `db:"verified_date"`
+	CreatedDate       time.Time  `db:"created_date"`
+}
+
+// VerificationRecordName возвращает имя DNS-записи, в которой ожидается токен подтверждения
+func (d *Domain) VerificationRecordName() string {
+	return VerificationRecordPrefix + "." + d.Hostname
+}
+
+// VerificationRecordValue возвращает ожидаемое значение TXT-записи
+func (d *Domain) VerificationRecordValue() string {
+	return VerificationValuePrefix + d.VerificationToken
+}
@@ -0,0 +1,16 @@
+package customDomain
+
+import "context"
+
+type RepositoryInterface interface {
+	FindByHostname(hostname string) (*Domain, error)
+	FindAll() ([]*Domain, error)
+	Save(domain *Domain) (*Domain, error)
+	Update(domain *Domain) (*Domain, error)
+}
+
+// ResolverInterface - подмножество net.Resolver, достаточное для проверки домена.
+// В тестах подменяется заглушкой.
+type ResolverInterface interface {
+	LookupTXT(ctx context.Context, name string) ([]string, error)
+}
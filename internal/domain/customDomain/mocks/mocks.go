@@ -0,0 +1,129 @@
+package mocks
+
+import (
+	context "context"
+	gomock "github.com/golang/mock/gomock"
+	customDomain "leenwood/yandex-http/internal/domain/customDomain"
+	reflect "reflect"
+)
+
+// MockRepositoryInterface is a mock of RepositoryInterface interface
+type MockRepositoryInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockRepositoryInterfaceMockRecorder
+}
+
+// MockRepositoryInterfaceMockRecorder is the mock recorder for MockRepositoryInterface
+type MockRepositoryInterfaceMockRecorder struct {
+	mock *MockRepositoryInterface
+}
+
+// NewMockRepositoryInterface creates a new mock instance
+func NewMockRepositoryInterface(ctrl *gomock.Controller) *MockRepositoryInterface {
+	mock := &MockRepositoryInterface{ctrl: ctrl}
+	mock.recorder = &MockRepositoryInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockRepositoryInterface) EXPECT() *MockRepositoryInterfaceMockRecorder {
+	return m.recorder
+}
+
+// FindByHostname mocks base method
+func (m *MockRepositoryInterface) FindByHostname(hostname string) (*customDomain.Domain, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByHostname", hostname)
+	ret0, _ := ret[0].(*customDomain.Domain)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByHostname indicates an expected call of FindByHostname
+func (mr *MockRepositoryInterfaceMockRecorder) FindByHostname(hostname interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByHostname", reflect.TypeOf((*MockRepositoryInterface)(nil).FindByHostname), hostname)
+}
+
+// FindAll mocks base method
+func (m *MockRepositoryInterface) FindAll() ([]*customDomain.Domain, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindAll")
+	ret0, _ := ret[0].([]*customDomain.Domain)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindAll indicates an expected call of FindAll
+func (mr *MockRepositoryInterfaceMockRecorder) FindAll() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAll", reflect.TypeOf((*MockRepositoryInterface)(nil).FindAll))
+}
+
+// Save mocks base method
+func (m *MockRepositoryInterface) Save(domain *customDomain.Domain) (*customDomain.Domain, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Save", domain)
+	ret0, _ := ret[0].(*customDomain.Domain)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Save indicates an expected call of Save
+func (mr *MockRepositoryInterfaceMockRecorder) Save(domain interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockRepositoryInterface)(nil).Save), domain)
+}
+
+// Update mocks base method
+func (m *MockRepositoryInterface) Update(domain *customDomain.Domain) (*customDomain.Domain, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", domain)
+	ret0, _ := ret[0].(*customDomain.Domain)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Update indicates an expected call of Update
+func (mr *MockRepositoryInterfaceMockRecorder) Update(domain interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockRepositoryInterface)(nil).Update), domain)
+}
+
+// MockResolverInterface is a mock of ResolverInterface interface
+type MockResolverInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockResolverInterfaceMockRecorder
+}
+
+// MockResolverInterfaceMockRecorder is the mock recorder for MockResolverInterface
+type MockResolverInterfaceMockRecorder struct {
+	mock *MockResolverInterface
+}
+
+// NewMockResolverInterface creates a new mock instance
+func NewMockResolverInterface(ctrl *gomock.Controller) *MockResolverInterface {
+	mock := &MockResolverInterface{ctrl: ctrl}
+	mock.recorder = &MockResolverInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockResolverInterface) EXPECT() *MockResolverInterfaceMockRecorder {
+	return m.recorder
+}
+
+// LookupTXT mocks base method
+func (m *MockResolverInterface) LookupTXT(ctx context.Context, name string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LookupTXT", ctx, name)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LookupTXT indicates an expected call of LookupTXT
+func (mr *MockResolverInterfaceMockRecorder) LookupTXT(ctx, name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LookupTXT", reflect.TypeOf((*MockResolverInterface)(nil).LookupTXT), ctx, name)
+}
@@ -0,0 +1,155 @@
+package postgresRepository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"leenwood/yandex-http/config"
+	"leenwood/yandex-http/internal/domain/customDomain"
+	"time"
+)
+
+var columns = []string{"hostname", "scheme", "verification_token", "verified", "verified_date", "created_date"}
+
+type Repository struct {
+	db  *pgxpool.Pool
+	sq  sq.StatementBuilderType
+	ctx context.Context
+}
+
+func NewRepository(ctx context.Context, config config.DatabaseConfig) (*Repository, error) {
+	dsn := fmt.Sprintf(
+		"postgres://%s:%s@%s:%s/%s",
+		config.Username,
+		config.Password,
+		config.Hostname,
+		config.Port,
+		config.Database,
+	)
+	dbpool, err := pgxpool.New(ctx, dsn)
+	if err != nil {
+		return nil, err
+	}
+	return &Repository{
+		db:  dbpool,
+		sq:  sq.StatementBuilder.PlaceholderFormat(sq.Dollar),
+		ctx: ctx,
+	}, nil
+}
+
+func (r *Repository) FindByHostname(hostname string) (*customDomain.Domain, error) {
+	query, args, err := r.sq.
+		Select(columns...).
+		From("domains").
+		Where(sq.Eq{"hostname": hostname}).
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	model, err := scanDomain(r.db.QueryRow(r.ctx, query, args...))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, customDomain.ErrNotFound
+		}
+		return nil, err
+	}
+
+	return model, nil
+}
+
+func (r *Repository) FindAll() ([]*customDomain.Domain, error) {
+	query, args, err := r.sq.
+		Select(columns...).
+		From("domains").
+		OrderBy("hostname").
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.db.Query(r.ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	domains := []*customDomain.Domain{}
+	for rows.Next() {
+		d, err := scanDomain(rows)
+		if err != nil {
+			return nil, err
+		}
+		domains = append(domains, d)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return domains, nil
+}
+
+func (r *Repository) Save(domain *customDomain.Domain) (*customDomain.Domain, error) {
+	if domain == nil {
+		return nil, errors.New("input domain cannot be nil")
+	}
+
+	domain.CreatedDate = time.Now()
+	query, args, err := r.sq.
+		Insert("domains").
+		Columns(columns...).
+		Values(domain.Hostname, domain.Scheme, domain.VerificationToken, domain.Verified, domain.VerifiedDate, domain.CreatedDate).
+		Suffix("ON CONFLICT (hostname) DO NOTHING").
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	tag, err := r.db.Exec(r.ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	if tag.RowsAffected() == 0 {
+		return nil, customDomain.ErrExists
+	}
+
+	return domain, nil
+}
+
+func (r *Repository) Update(domain *customDomain.Domain) (*customDomain.Domain, error) {
+	if domain == nil {
+		return nil, errors.New("input domain cannot be nil")
+	}
+
+	query, args, err := r.sq.
+		Update("domains").
+		Set("scheme", domain.Scheme).
+		Set("verification_token", domain.VerificationToken).
+		Set("verified", domain.Verified).
+		Set("verified_date", domain.VerifiedDate).
+		Where(sq.Eq{"hostname": domain.Hostname}).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build update query: %w", err)
+	}
+
+	_, err = r.db.Exec(r.ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute update query: %w", err)
+	}
+
+	return domain, nil
+}
+
+func scanDomain(row pgx.Row) (*customDomain.Domain, error) {
+	model := &customDomain.Domain{}
+	err := row.Scan(&model.Hostname, &model.Scheme, &model.VerificationToken, &model.Verified, &model.VerifiedDate, &model.CreatedDate)
+	if err != nil {
+		return nil, err
+	}
+	return model, nil
+}
@@ -0,0 +1,159 @@
+package sqliteRepository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	sq "github.com/Masterminds/squirrel"
+	"leenwood/yandex-http/config"
+	"leenwood/yandex-http/internal/domain/customDomain"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+var columns = []string{"hostname", "scheme", "verification_token", "verified", "verified_date", "created_date"}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+type Repository struct {
+	db  *sql.DB
+	sq  sq.StatementBuilderType
+	ctx context.Context
+}
+
+func NewRepository(ctx context.Context, _ config.DatabaseConfig) (*Repository, error) {
+	dsn := "database.sqlite"
+	db, err := sql.Open("sqlite3", dsn)
+	if err != nil {
+		return nil, err
+	}
+	return &Repository{
+		db:  db,
+		sq:  sq.StatementBuilder.PlaceholderFormat(sq.Question),
+		ctx: ctx,
+	}, nil
+}
+
+func (r *Repository) FindByHostname(hostname string) (*customDomain.Domain, error) {
+	query, args, err := r.sq.
+		Select(columns...).
+		From("domains").
+		Where(sq.Eq{"hostname": hostname}).
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	model, err := scanDomain(r.db.QueryRowContext(r.ctx, query, args...))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, customDomain.ErrNotFound
+		}
+		return nil, err
+	}
+
+	return model, nil
+}
+
+func (r *Repository) FindAll() ([]*customDomain.Domain, error) {
+	query, args, err := r.sq.
+		Select(columns...).
+		From("domains").
+		OrderBy("hostname").
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.db.QueryContext(r.ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	domains := []*customDomain.Domain{}
+	for rows.Next() {
+		d, err := scanDomain(rows)
+		if err != nil {
+			return nil, err
+		}
+		domains = append(domains, d)
+	}
+
+	// Проверяем наличие ошибок при итерации по строкам
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return domains, nil
+}
+
+func (r *Repository) Save(domain *customDomain.Domain) (*customDomain.Domain, error) {
+	if domain == nil {
+		return nil, errors.New("input domain cannot be nil")
+	}
+
+	domain.CreatedDate = time.Now()
+	query, args, err := r.sq.
+		Insert("domains").
+		Columns(columns...).
+		Values(domain.Hostname, domain.Scheme, domain.VerificationToken, domain.Verified, domain.VerifiedDate, domain.CreatedDate).
+		Suffix("ON CONFLICT (hostname) DO NOTHING").
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := r.db.ExecContext(r.ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+	if affected == 0 {
+		return nil, customDomain.ErrExists
+	}
+
+	return domain, nil
+}
+
+func (r *Repository) Update(domain *customDomain.Domain) (*customDomain.Domain, error) {
+	if domain == nil {
+		return nil, errors.New("input domain cannot be nil")
+	}
+
+	// Формируем SQL-запрос для обновления сущности
+	query, args, err := r.sq.
+		Update("domains").
+		Set("scheme", domain.Scheme).
+		Set("verification_token", domain.VerificationToken).
+		Set("verified", domain.Verified).
+		Set("verified_date", domain.VerifiedDate).
+		Where(sq.Eq{"hostname": domain.Hostname}).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build update query: %w", err)
+	}
+
+	_, err = r.db.ExecContext(r.ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute update query: %w", err)
+	}
+
+	return domain, nil
+}
+
+func scanDomain(row rowScanner) (*customDomain.Domain, error) {
+	model := &customDomain.Domain{}
+	err := row.Scan(&model.Hostname, &model.Scheme, &model.VerificationToken, &model.Verified, &model.VerifiedDate, &model.CreatedDate)
+	if err != nil {
+		return nil, err
+	}
+	return model, nil
+}
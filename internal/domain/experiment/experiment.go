@@ -0,0 +1,114 @@
+package experiment
+
+import (
+	"errors"
+	"fmt"
+	neturl "net/url"
+	"strings"
+)
+
+var (
+	ErrNotFound        = errors.New("experiment not found")
+	ErrExists          = errors.New("experiment already exists")
+	ErrInvalid         = errors.New("invalid experiment")
+	ErrVariantNotFound = errors.New("variant not found")
+)
+
+const (
+	MinVariants    = 2
+	MaxVariants    = 10
+	MaxWeight      = 1000
+	maxNameLength  = 32
+	nameCharacters = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789-_"
+)
+
+// Variant - вариант адреса перехода в эксперименте ссылки
+type Variant struct {
+	UrlId      string `db:"url_id"`
+	Name       string `db:"name"`
+	TargetUrl  string `db:"target_url"`
+	Weight     int    `db:"weight"`
+	ClickCount uint64 `db:"click_count"`
+}
+
+// Pick выбирает вариант по числу roll пропорционально весам.
+// Для одного и того же roll выбор не меняется, пока не изменились веса.
+func Pick(variants []*Variant, roll uint64) *Variant {
+	total := uint64(0)
+	for _, v := range variants {
+		total += uint64(v.Weight)
+	}
+	if total == 0 {
+		return nil
+	}
+
+	point := roll % total
+	for _, v := range variants {
+		if point < uint64(v.Weight) {
+			return v
+		}
+		point -= uint64(v.Weight)
+	}
+	return nil
+}
+
+// Find возвращает вариант по имени или nil
+func Find(variants []*Variant, name string) *Variant {
+	for _, v := range variants {
+		if v.Name == name {
+			return v
+		}
+	}
+	return nil
+}
+
+// Validate проверяет варианты нового эксперимента
+func Validate(variants []*Variant) error {
+	if len(variants) < MinVariants || len(variants) > MaxVariants {
+		return fmt.Errorf("%w: from %d to %d variants are required", ErrInvalid, MinVariants, MaxVariants)
+	}
+
+	names := make(map[string]bool, len(variants))
+	for _, v := range variants {
+		if err := validateName(v.Name); err != nil {
+			return err
+		}
+		if names[v.Name] {
+			return fmt.Errorf("%w: duplicate variant %q", ErrInvalid, v.Name)
+		}
+		names[v.Name] = true
+
+		target, err := neturl.Parse(v.TargetUrl)
+		if err != nil || (target.Scheme != "http" && target.Scheme != "https") || target.Host == "" {
+			return fmt.Errorf("%w: variant %q: target_url must be an absolute http or https URL", ErrInvalid, v.Name)
+		}
+	}
+	return ValidateWeights(variants)
+}
+
+// ValidateWeights проверяет, что веса неотрицательны и хотя бы один вариант получает переходы
+func ValidateWeights(variants []*Variant) error {
+	total := 0
+	for _, v := range variants {
+		if v.Weight < 0 || v.Weight > MaxWeight {
+			return fmt.Errorf("%w: variant %q: weight must be between 0 and %d", ErrInvalid, v.Name, MaxWeight)
+		}
+		total += v.Weight
+	}
+	if total == 0 {
+		return fmt.Errorf("%w: at least one variant must have a positive weight", ErrInvalid)
+	}
+	return nil
+}
+
+func validateName(name string) error {
+	if name == "" || len(name) > maxNameLength {
+		return fmt.Errorf("%w: variant name must be 1 to %d characters long", ErrInvalid, maxNameLength)
+	}
+	for _, r := range name {
+		if !strings.ContainsRune(nameCharacters, r) {
+			return fmt.Errorf("%w: variant name %q may contain only letters, digits, '-' and '_'", ErrInvalid, name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,10 @@
+package experiment
+
+type RepositoryInterface interface {
+	FindByUrl(urlId string) ([]*Variant, error)
+	// Save заменяет варианты ссылки, счётчики переходов начинаются с нуля
+	Save(urlId string, variants []*Variant) error
+	UpdateWeights(urlId string, variants []*Variant) error
+	IncrementClicks(urlId, name string) error
+	DeleteByUrl(urlId string) error
+}
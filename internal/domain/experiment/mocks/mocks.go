@@ -0,0 +1,101 @@
+package mocks
+
+import (
+	gomock "github.com/golang/mock/gomock"
+	experiment "leenwood/yandex-http/internal/domain/experiment"
+	reflect "reflect"
+)
+
+// MockRepositoryInterface is a mock of RepositoryInterface interface
+type MockRepositoryInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockRepositoryInterfaceMockRecorder
+}
+
+// MockRepositoryInterfaceMockRecorder is the mock recorder for MockRepositoryInterface
+type MockRepositoryInterfaceMockRecorder struct {
+	mock *MockRepositoryInterface
+}
+
+// NewMockRepositoryInterface creates a new mock instance
+func NewMockRepositoryInterface(ctrl *gomock.Controller) *MockRepositoryInterface {
+	mock := &MockRepositoryInterface{ctrl: ctrl}
+	mock.recorder = &MockRepositoryInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockRepositoryInterface) EXPECT() *MockRepositoryInterfaceMockRecorder {
+	return m.recorder
+}
+
+// DeleteByUrl mocks base method
+func (m *MockRepositoryInterface) DeleteByUrl(urlId string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteByUrl", urlId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteByUrl indicates an expected call of DeleteByUrl
+func (mr *MockRepositoryInterfaceMockRecorder) DeleteByUrl(urlId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteByUrl", reflect.TypeOf((*MockRepositoryInterface)(nil).DeleteByUrl), urlId)
+}
+
+// FindByUrl mocks base method
+func (m *MockRepositoryInterface) FindByUrl(urlId string) ([]*experiment.Variant, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByUrl", urlId)
+	ret0, _ := ret[0].([]*experiment.Variant)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByUrl indicates an expected call of FindByUrl
+func (mr *MockRepositoryInterfaceMockRecorder) FindByUrl(urlId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByUrl", reflect.TypeOf((*MockRepositoryInterface)(nil).FindByUrl), urlId)
+}
+
+// IncrementClicks mocks base method
+func (m *MockRepositoryInterface) IncrementClicks(urlId, name string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IncrementClicks", urlId, name)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// IncrementClicks indicates an expected call of IncrementClicks
+func (mr *MockRepositoryInterfaceMockRecorder) IncrementClicks(urlId, name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IncrementClicks", reflect.TypeOf((*MockRepositoryInterface)(nil).IncrementClicks), urlId, name)
+}
+
+// Save mocks base method
+func (m *MockRepositoryInterface) Save(urlId string, variants []*experiment.Variant) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Save", urlId, variants)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Save indicates an expected call of Save
+func (mr *MockRepositoryInterfaceMockRecorder) Save(urlId, variants interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockRepositoryInterface)(nil).Save), urlId, variants)
+}
+
+// UpdateWeights mocks base method
+func (m *MockRepositoryInterface) UpdateWeights(urlId string, variants []*experiment.Variant) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateWeights", urlId, variants)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateWeights indicates an expected call of UpdateWeights
+func (mr *MockRepositoryInterfaceMockRecorder) UpdateWeights(urlId, variants interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWeights", reflect.TypeOf((*MockRepositoryInterface)(nil).UpdateWeights), urlId, variants)
+}
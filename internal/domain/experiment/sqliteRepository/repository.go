@@ -0,0 +1,141 @@
+package sqliteRepository
+
+import (
+	"context"
+	"database/sql"
+	sq "github.com/Masterminds/squirrel"
+	"leenwood/yandex-http/config"
+	"leenwood/yandex-http/internal/domain/experiment"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+type Repository struct {
+	db  *sql.DB
+	sq  sq.StatementBuilderType
+	ctx context.Context
+}
+
+func NewRepository(ctx context.Context, _ config.DatabaseConfig) (*Repository, error) {
+	dsn := "database.sqlite"
+	db, err := sql.Open("sqlite3", dsn)
+	if err != nil {
+		return nil, err
+	}
+	return &Repository{
+		db:  db,
+		sq:  sq.StatementBuilder.PlaceholderFormat(sq.Question),
+		ctx: ctx,
+	}, nil
+}
+
+func (r *Repository) FindByUrl(urlId string) ([]*experiment.Variant, error) {
+	query, args, err := r.sq.
+		Select("url_id", "name", "target_url", "weight", "click_count").
+		From("url_variants").
+		Where(sq.Eq{"url_id": urlId}).
+		OrderBy("position").
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.db.QueryContext(r.ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var variants []*experiment.Variant
+	for rows.Next() {
+		model := &experiment.Variant{}
+		if err := rows.Scan(&model.UrlId, &model.Name, &model.TargetUrl, &model.Weight, &model.ClickCount); err != nil {
+			return nil, err
+		}
+		variants = append(variants, model)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return variants, nil
+}
+
+func (r *Repository) Save(urlId string, variants []*experiment.Variant) error {
+	tx, err := r.db.BeginTx(r.ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query, args, err := r.sq.Delete("url_variants").Where(sq.Eq{"url_id": urlId}).ToSql()
+	if err != nil {
+		return err
+	}
+	if _, err = tx.ExecContext(r.ctx, query, args...); err != nil {
+		return err
+	}
+
+	insert := r.sq.Insert("url_variants").Columns("url_id", "name", "target_url", "weight", "click_count", "position")
+	for i, model := range variants {
+		insert = insert.Values(urlId, model.Name, model.TargetUrl, model.Weight, 0, i)
+	}
+	query, args, err = insert.ToSql()
+	if err != nil {
+		return err
+	}
+	if _, err = tx.ExecContext(r.ctx, query, args...); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *Repository) UpdateWeights(urlId string, variants []*experiment.Variant) error {
+	tx, err := r.db.BeginTx(r.ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, model := range variants {
+		query, args, err := r.sq.
+			Update("url_variants").
+			Set("weight", model.Weight).
+			Where(sq.Eq{"url_id": urlId, "name": model.Name}).
+			ToSql()
+		if err != nil {
+			return err
+		}
+		if _, err = tx.ExecContext(r.ctx, query, args...); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (r *Repository) IncrementClicks(urlId, name string) error {
+	query, args, err := r.sq.
+		Update("url_variants").
+		Set("click_count", sq.Expr("click_count + 1")).
+		Where(sq.Eq{"url_id": urlId, "name": name}).
+		ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = r.db.ExecContext(r.ctx, query, args...)
+	return err
+}
+
+func (r *Repository) DeleteByUrl(urlId string) error {
+	query, args, err := r.sq.Delete("url_variants").Where(sq.Eq{"url_id": urlId}).ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = r.db.ExecContext(r.ctx, query, args...)
+	return err
+}
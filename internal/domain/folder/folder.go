@@ -0,0 +1,122 @@
+package folder
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+var (
+	ErrNotFound    = errors.New("folder not found")
+	ErrExists      = errors.New("folder with this name already exists")
+	ErrInvalidName = errors.New("invalid folder name")
+	// ErrInvalidMove - папку нельзя переместить в неё саму или во вложенную папку
+	ErrInvalidMove = errors.New("folder cannot be moved into itself or its subfolder")
+	ErrTooDeep     = errors.New("folders are nested too deep")
+)
+
+const (
+	// MaxNameLength - наибольшая длина названия папки в символах
+	MaxNameLength = 100
+	// MaxDepth - наибольшая вложенность папок, папки верхнего уровня имеют глубину 1
+	MaxDepth = 10
+)
+
+// Folder - папка ссылок владельца. Папки образуют дерево, ссылка лежит не более чем в одной папке.
+// Права на папку и её ссылки определяются владельцем папки.
+type Folder struct {
+	Id      string `db:"id"`
+	OwnerId string `db:"owner_id"`
+	// ParentId - родительская папка, пустая строка для папок верхнего уровня
+	ParentId    string    `db:"parent_id"`
+	Name        string    `db:"name"`
+	CreatedDate time.Time `db:"created_date"`
+}
+
+// NormalizeName убирает лишние пробелы и проверяет длину названия
+func NormalizeName(name string) (string, error) {
+	name = strings.Join(strings.Fields(name), " ")
+	if name == "" || utf8.RuneCountInString(name) > MaxNameLength {
+		return "", fmt.Errorf("%w: must be 1 to %d characters", ErrInvalidName, MaxNameLength)
+	}
+	if strings.ContainsRune(name, '/') {
+		return "", fmt.Errorf("%w: must not contain \"/\"", ErrInvalidName)
+	}
+	return name, nil
+}
+
+// Tree - папки одного владельца по id
+type Tree map[string]*Folder
+
+func NewTree(folders []*Folder) Tree {
+	tree := make(Tree, len(folders))
+	for _, f := range folders {
+		tree[f.Id] = f
+	}
+	return tree
+}
+
+// Depth возвращает уровень папки, для папок верхнего уровня - 1
+func (t Tree) Depth(id string) int {
+	depth := 0
+	for f, ok := t[id]; ok && depth <= len(t); f, ok = t[f.ParentId] {
+		depth++
+	}
+	return depth
+}
+
+// Height возвращает число уровней поддерева папки вместе с ней самой
+func (t Tree) Height(id string) int {
+	height := 0
+	for _, child := range t.Children(id) {
+		height = max(height, t.Height(child.Id))
+	}
+	return height + 1
+}
+
+// IsInside сообщает, что папка id совпадает с ancestorId или вложена в неё на любом уровне
+func (t Tree) IsInside(id, ancestorId string) bool {
+	for steps := 0; id != "" && steps <= len(t); steps++ {
+		if id == ancestorId {
+			return true
+		}
+		f, ok := t[id]
+		if !ok {
+			return false
+		}
+		id = f.ParentId
+	}
+	return false
+}
+
+// Subtree возвращает id папки и всех вложенных в неё папок
+func (t Tree) Subtree(id string) []string {
+	ids := []string{id}
+	for _, child := range t.Children(id) {
+		ids = append(ids, t.Subtree(child.Id)...)
+	}
+	return ids
+}
+
+// HasName сообщает, что в родительской папке уже есть другая папка с таким названием без учёта регистра
+func (t Tree) HasName(parentId, name, exceptId string) bool {
+	for _, child := range t.Children(parentId) {
+		if child.Id != exceptId && strings.EqualFold(child.Name, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// Children возвращает папки, непосредственно вложенные в parentId
+func (t Tree) Children(parentId string) []*Folder {
+	var children []*Folder
+	for _, f := range t {
+		if f.ParentId == parentId {
+			children = append(children, f)
+		}
+	}
+	return children
+}
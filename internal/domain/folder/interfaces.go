@@ -0,0 +1,11 @@
+package folder
+
+type RepositoryInterface interface {
+	FindById(id string) (*Folder, error)
+	FindByOwner(ownerId string) ([]*Folder, error)
+	// Save создаёт папку, id и дата создания заполняются при сохранении
+	Save(folder *Folder) (*Folder, error)
+	Update(folder *Folder) (*Folder, error)
+	// Delete удаляет папку одной транзакцией, перенося её ссылки и вложенные папки в родительскую
+	Delete(folder *Folder) error
+}
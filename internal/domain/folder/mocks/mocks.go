@@ -0,0 +1,104 @@
+package mocks
+
+import (
+	gomock "github.com/golang/mock/gomock"
+	folder "leenwood/yandex-http/internal/domain/folder"
+	reflect "reflect"
+)
+
+// MockRepositoryInterface is a mock of RepositoryInterface interface
+type MockRepositoryInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockRepositoryInterfaceMockRecorder
+}
+
+// MockRepositoryInterfaceMockRecorder is the mock recorder for MockRepositoryInterface
+type MockRepositoryInterfaceMockRecorder struct {
+	mock *MockRepositoryInterface
+}
+
+// NewMockRepositoryInterface creates a new mock instance
+func NewMockRepositoryInterface(ctrl *gomock.Controller) *MockRepositoryInterface {
+	mock := &MockRepositoryInterface{ctrl: ctrl}
+	mock.recorder = &MockRepositoryInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockRepositoryInterface) EXPECT() *MockRepositoryInterfaceMockRecorder {
+	return m.recorder
+}
+
+// FindById mocks base method
+func (m *MockRepositoryInterface) FindById(id string) (*folder.Folder, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindById", id)
+	ret0, _ := ret[0].(*folder.Folder)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindById indicates an expected call of FindById
+func (mr *MockRepositoryInterfaceMockRecorder) FindById(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindById", reflect.TypeOf((*MockRepositoryInterface)(nil).FindById), id)
+}
+
+// FindByOwner mocks base method
+func (m *MockRepositoryInterface) FindByOwner(ownerId string) ([]*folder.Folder, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByOwner", ownerId)
+	ret0, _ := ret[0].([]*folder.Folder)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByOwner indicates an expected call of FindByOwner
+func (mr *MockRepositoryInterfaceMockRecorder) FindByOwner(ownerId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByOwner", reflect.TypeOf((*MockRepositoryInterface)(nil).FindByOwner), ownerId)
+}
+
+// Save mocks base method
+func (m *MockRepositoryInterface) Save(model *folder.Folder) (*folder.Folder, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Save", model)
+	ret0, _ := ret[0].(*folder.Folder)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Save indicates an expected call of Save
+func (mr *MockRepositoryInterfaceMockRecorder) Save(model interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockRepositoryInterface)(nil).Save), model)
+}
+
+// Update mocks base method
+func (m *MockRepositoryInterface) Update(model *folder.Folder) (*folder.Folder, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", model)
+	ret0, _ := ret[0].(*folder.Folder)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Update indicates an expected call of Update
+func (mr *MockRepositoryInterfaceMockRecorder) Update(model interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockRepositoryInterface)(nil).Update), model)
+}
+
+// Delete mocks base method
+func (m *MockRepositoryInterface) Delete(model *folder.Folder) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", model)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete
+func (mr *MockRepositoryInterfaceMockRecorder) Delete(model interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockRepositoryInterface)(nil).Delete), model)
+}
@@ -0,0 +1,150 @@
+package sqliteRepository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	sq "github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"leenwood/yandex-http/config"
+	"leenwood/yandex-http/internal/domain/folder"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+var columns = []string{"id", "owner_id", "parent_id", "name", "created_date"}
+
+type Repository struct {
+	db  *sql.DB
+	sq  sq.StatementBuilderType
+	ctx context.Context
+}
+
+func NewRepository(ctx context.Context, _ config.DatabaseConfig) (*Repository, error) {
+	dsn := "database.sqlite"
+	db, err := sql.Open("sqlite3", dsn)
+	if err != nil {
+		return nil, err
+	}
+	return &Repository{
+		db:  db,
+		sq:  sq.StatementBuilder.PlaceholderFormat(sq.Question),
+		ctx: ctx,
+	}, nil
+}
+
+func (r *Repository) FindById(id string) (*folder.Folder, error) {
+	query, args, err := r.sq.
+		Select(columns...).
+		From("folders").
+		Where(sq.Eq{"id": id}).
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	model := &folder.Folder{}
+	err = r.db.QueryRowContext(r.ctx, query, args...).Scan(&model.Id, &model.OwnerId, &model.ParentId, &model.Name, &model.CreatedDate)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, folder.ErrNotFound
+		}
+		return nil, err
+	}
+
+	return model, nil
+}
+
+func (r *Repository) FindByOwner(ownerId string) ([]*folder.Folder, error) {
+	query, args, err := r.sq.
+		Select(columns...).
+		From("folders").
+		Where(sq.Eq{"owner_id": ownerId}).
+		OrderBy("name", "id").
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.db.QueryContext(r.ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	folders := []*folder.Folder{}
+	for rows.Next() {
+		model := &folder.Folder{}
+		if err := rows.Scan(&model.Id, &model.OwnerId, &model.ParentId, &model.Name, &model.CreatedDate); err != nil {
+			return nil, err
+		}
+		folders = append(folders, model)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return folders, nil
+}
+
+func (r *Repository) Save(model *folder.Folder) (*folder.Folder, error) {
+	model.Id = uuid.New().String()
+	model.CreatedDate = time.Now()
+
+	query, args, err := r.sq.
+		Insert("folders").
+		Columns(columns...).
+		Values(model.Id, model.OwnerId, model.ParentId, model.Name, model.CreatedDate).
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err = r.db.ExecContext(r.ctx, query, args...); err != nil {
+		return nil, err
+	}
+	return model, nil
+}
+
+func (r *Repository) Update(model *folder.Folder) (*folder.Folder, error) {
+	query, args, err := r.sq.
+		Update("folders").
+		Set("parent_id", model.ParentId).
+		Set("name", model.Name).
+		Where(sq.Eq{"id": model.Id}).
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err = r.db.ExecContext(r.ctx, query, args...); err != nil {
+		return nil, err
+	}
+	return model, nil
+}
+
+func (r *Repository) Delete(model *folder.Folder) error {
+	tx, err := r.db.BeginTx(r.ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, builder := range []sq.Sqlizer{
+		r.sq.Update("urls").Set("folder_id", model.ParentId).Where(sq.Eq{"folder_id": model.Id}),
+		r.sq.Update("folders").Set("parent_id", model.ParentId).Where(sq.Eq{"parent_id": model.Id}),
+		r.sq.Delete("folders").Where(sq.Eq{"id": model.Id}),
+	} {
+		query, args, err := builder.ToSql()
+		if err != nil {
+			return err
+		}
+		if _, err = tx.ExecContext(r.ctx, query, args...); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
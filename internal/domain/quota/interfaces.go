@@ -0,0 +1,7 @@
+package quota
+
+type RepositoryInterface interface {
+	FindByOwner(ownerId string) (*Quota, error)
+	FindMonthlyClicks(ownerId, period string) (uint64, error)
+	IncrementMonthlyClicks(ownerId, period string) (uint64, error)
+}
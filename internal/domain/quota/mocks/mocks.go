@@ -0,0 +1,75 @@
+package mocks
+
+import (
+	gomock "github.com/golang/mock/gomock"
+	quota "leenwood/yandex-http/internal/domain/quota"
+	reflect "reflect"
+)
+
+// MockRepositoryInterface is a mock of RepositoryInterface interface
+type MockRepositoryInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockRepositoryInterfaceMockRecorder
+}
+
+// MockRepositoryInterfaceMockRecorder is the mock recorder for MockRepositoryInterface
+type MockRepositoryInterfaceMockRecorder struct {
+	mock *MockRepositoryInterface
+}
+
+// NewMockRepositoryInterface creates a new mock instance
+func NewMockRepositoryInterface(ctrl *gomock.Controller) *MockRepositoryInterface {
+	mock := &MockRepositoryInterface{ctrl: ctrl}
+	mock.recorder = &MockRepositoryInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockRepositoryInterface) EXPECT() *MockRepositoryInterfaceMockRecorder {
+	return m.recorder
+}
+
+// FindByOwner mocks base method
+func (m *MockRepositoryInterface) FindByOwner(ownerId string) (*quota.Quota, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByOwner", ownerId)
+	ret0, _ := ret[0].(*quota.Quota)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByOwner indicates an expected call of FindByOwner
+func (mr *MockRepositoryInterfaceMockRecorder) FindByOwner(ownerId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByOwner", reflect.TypeOf((*MockRepositoryInterface)(nil).FindByOwner), ownerId)
+}
+
+// FindMonthlyClicks mocks base method
+func (m *MockRepositoryInterface) FindMonthlyClicks(ownerId, period string) (uint64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindMonthlyClicks", ownerId, period)
+	ret0, _ := ret[0].(uint64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindMonthlyClicks indicates an expected call of FindMonthlyClicks
+func (mr *MockRepositoryInterfaceMockRecorder) FindMonthlyClicks(ownerId, period interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindMonthlyClicks", reflect.TypeOf((*MockRepositoryInterface)(nil).FindMonthlyClicks), ownerId, period)
+}
+
+// IncrementMonthlyClicks mocks base method
+func (m *MockRepositoryInterface) IncrementMonthlyClicks(ownerId, period string) (uint64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IncrementMonthlyClicks", ownerId, period)
+	ret0, _ := ret[0].(uint64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IncrementMonthlyClicks indicates an expected call of IncrementMonthlyClicks
+func (mr *MockRepositoryInterfaceMockRecorder) IncrementMonthlyClicks(ownerId, period interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IncrementMonthlyClicks", reflect.TypeOf((*MockRepositoryInterface)(nil).IncrementMonthlyClicks), ownerId, period)
+}
@@ -0,0 +1,97 @@
+package postgresRepository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"leenwood/yandex-http/config"
+	"leenwood/yandex-http/internal/domain/quota"
+)
+
+type Repository struct {
+	db  *pgxpool.Pool
+	sq  sq.StatementBuilderType
+	ctx context.Context
+}
+
+func NewRepository(ctx context.Context, config config.DatabaseConfig) (*Repository, error) {
+	dsn := fmt.Sprintf(
+		"postgres://%s:%s@%s:%s/%s",
+		config.Username,
+		config.Password,
+		config.Hostname,
+		config.Port,
+		config.Database,
+	)
+	dbpool, err := pgxpool.New(ctx, dsn)
+	if err != nil {
+		return nil, err
+	}
+	return &Repository{
+		db:  dbpool,
+		sq:  sq.StatementBuilder.PlaceholderFormat(sq.Dollar),
+		ctx: ctx,
+	}, nil
+}
+
+func (r *Repository) FindByOwner(ownerId string) (*quota.Quota, error) {
+	query, args, err := r.sq.
+		Select("owner_id", "max_links", "max_custom_aliases", "max_monthly_clicks", "allow_custom_id").
+		From("quotas").
+		Where(sq.Eq{"owner_id": ownerId}).
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	model := &quota.Quota{}
+	err = r.db.QueryRow(r.ctx, query, args...).
+		Scan(&model.OwnerId, &model.MaxLinks, &model.MaxCustomAliases, &model.MaxMonthlyClicks, &model.AllowCustomId)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, quota.ErrNotFound
+		}
+		return nil, err
+	}
+
+	return model, nil
+}
+
+func (r *Repository) FindMonthlyClicks(ownerId, period string) (uint64, error) {
+	query, args, err := r.sq.
+		Select("clicks").
+		From("monthly_clicks").
+		Where(sq.Eq{"owner_id": ownerId, "period": period}).
+		ToSql()
+	if err != nil {
+		return 0, err
+	}
+
+	var clicks uint64
+	err = r.db.QueryRow(r.ctx, query, args...).Scan(&clicks)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return 0, err
+	}
+	return clicks, nil
+}
+
+func (r *Repository) IncrementMonthlyClicks(ownerId, period string) (uint64, error) {
+	query, args, err := r.sq.
+		Insert("monthly_clicks").
+		Columns("owner_id", "period", "clicks").
+		Values(ownerId, period, 1).
+		Suffix("ON CONFLICT (owner_id, period) DO UPDATE SET clicks = monthly_clicks.clicks + 1 RETURNING clicks").
+		ToSql()
+	if err != nil {
+		return 0, err
+	}
+
+	var clicks uint64
+	if err = r.db.QueryRow(r.ctx, query, args...).Scan(&clicks); err != nil {
+		return 0, err
+	}
+	return clicks, nil
+}
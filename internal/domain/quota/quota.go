@@ -0,0 +1,35 @@
+package quota
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrNotFound           = errors.New("quota not found")
+	ErrExceeded           = errors.New("quota exceeded")
+	ErrCustomIdNotAllowed = errors.New("custom ids are not allowed by the plan")
+)
+
+// Quota - лимиты тарифа владельца. Нулевое значение лимита означает отсутствие ограничения.
+type Quota struct {
+	OwnerId          string `db:"owner_id"`
+	MaxLinks         uint64 `db:"max_links"`
+	MaxCustomAliases uint64 `db:"max_custom_aliases"`
+	MaxMonthlyClicks uint64 `db:"max_monthly_clicks"`
+	AllowCustomId    bool   `db:"allow_custom_id"`
+}
+
+// ExceededError сообщает, какой именно лимит исчерпан
+type ExceededError struct {
+	Limit string
+	Max   uint64
+}
+
+func (e *ExceededError) Error() string {
+	return fmt.Sprintf("quota exceeded: %s limit is %d", e.Limit, e.Max)
+}
+
+func (e *ExceededError) Is(target error) bool {
+	return target == ErrExceeded
+}
@@ -0,0 +1,90 @@
+package sqliteRepository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	sq "github.com/Masterminds/squirrel"
+	"leenwood/yandex-http/config"
+	"leenwood/yandex-http/internal/domain/quota"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+type Repository struct {
+	db  *sql.DB
+	sq  sq.StatementBuilderType
+	ctx context.Context
+}
+
+func NewRepository(ctx context.Context, _ config.DatabaseConfig) (*Repository, error) {
+	dsn := "database.sqlite"
+	db, err := sql.Open("sqlite3", dsn)
+	if err != nil {
+		return nil, err
+	}
+	return &Repository{
+		db:  db,
+		sq:  sq.StatementBuilder.PlaceholderFormat(sq.Question),
+		ctx: ctx,
+	}, nil
+}
+
+func (r *Repository) FindByOwner(ownerId string) (*quota.Quota, error) {
+	query, args, err := r.sq.
+		Select("owner_id", "max_links", "max_custom_aliases", "max_monthly_clicks", "allow_custom_id").
+		From("quotas").
+		Where(sq.Eq{"owner_id": ownerId}).
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	model := &quota.Quota{}
+	err = r.db.QueryRowContext(r.ctx, query, args...).
+		Scan(&model.OwnerId, &model.MaxLinks, &model.MaxCustomAliases, &model.MaxMonthlyClicks, &model.AllowCustomId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, quota.ErrNotFound
+		}
+		return nil, err
+	}
+
+	return model, nil
+}
+
+func (r *Repository) FindMonthlyClicks(ownerId, period string) (uint64, error) {
+	query, args, err := r.sq.
+		Select("clicks").
+		From("monthly_clicks").
+		Where(sq.Eq{"owner_id": ownerId, "period": period}).
+		ToSql()
+	if err != nil {
+		return 0, err
+	}
+
+	var clicks uint64
+	err = r.db.QueryRowContext(r.ctx, query, args...).Scan(&clicks)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return 0, err
+	}
+	return clicks, nil
+}
+
+func (r *Repository) IncrementMonthlyClicks(ownerId, period string) (uint64, error) {
+	query, args, err := r.sq.
+		Insert("monthly_clicks").
+		Columns("owner_id", "period", "clicks").
+		Values(ownerId, period, 1).
+		Suffix("ON CONFLICT (owner_id, period) DO UPDATE SET clicks = monthly_clicks.clicks + 1 RETURNING clicks").
+		ToSql()
+	if err != nil {
+		return 0, err
+	}
+
+	var clicks uint64
+	if err = r.db.QueryRowContext(r.ctx, query, args...).Scan(&clicks); err != nil {
+		return 0, err
+	}
+	return clicks, nil
+}
@@ -0,0 +1,7 @@
+package rule
+
+type RepositoryInterface interface {
+	FindByUrl(urlId string) ([]*Rule, error)
+	// Replace заменяет все правила ссылки одной транзакцией
+	Replace(urlId string, rules []*Rule) error
+}
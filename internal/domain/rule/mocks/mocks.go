@@ -0,0 +1,59 @@
+package mocks
+
+import (
+	gomock "github.com/golang/mock/gomock"
+	rule "leenwood/yandex-http/internal/domain/rule"
+	reflect "reflect"
+)
+
+// MockRepositoryInterface is a mock of RepositoryInterface interface
+type MockRepositoryInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockRepositoryInterfaceMockRecorder
+}
+
+// MockRepositoryInterfaceMockRecorder is the mock recorder for MockRepositoryInterface
+type MockRepositoryInterfaceMockRecorder struct {
+	mock *MockRepositoryInterface
+}
+
+// NewMockRepositoryInterface creates a new mock instance
+func NewMockRepositoryInterface(ctrl *gomock.Controller) *MockRepositoryInterface {
+	mock := &MockRepositoryInterface{ctrl: ctrl}
+	mock.recorder = &MockRepositoryInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockRepositoryInterface) EXPECT() *MockRepositoryInterfaceMockRecorder {
+	return m.recorder
+}
+
+// FindByUrl mocks base method
+func (m *MockRepositoryInterface) FindByUrl(urlId string) ([]*rule.Rule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByUrl", urlId)
+	ret0, _ := ret[0].([]*rule.Rule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByUrl indicates an expected call of FindByUrl
+func (mr *MockRepositoryInterfaceMockRecorder) FindByUrl(urlId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByUrl", reflect.TypeOf((*MockRepositoryInterface)(nil).FindByUrl), urlId)
+}
+
+// Replace mocks base method
+func (m *MockRepositoryInterface) Replace(urlId string, rules []*rule.Rule) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Replace", urlId, rules)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Replace indicates an expected call of Replace
+func (mr *MockRepositoryInterfaceMockRecorder) Replace(urlId, rules interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Replace", reflect.TypeOf((*MockRepositoryInterface)(nil).Replace), urlId, rules)
+}
@@ -0,0 +1,110 @@
+package postgresRepository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"leenwood/yandex-http/config"
+	"leenwood/yandex-http/internal/domain/rule"
+)
+
+type Repository struct {
+	db  *pgxpool.Pool
+	sq  sq.StatementBuilderType
+	ctx context.Context
+}
+
+func NewRepository(ctx context.Context, config config.DatabaseConfig) (*Repository, error) {
+	dsn := fmt.Sprintf(
+		"postgres://%s:%s@%s:%s/%s",
+		config.Username,
+		config.Password,
+		config.Hostname,
+		config.Port,
+		config.Database,
+	)
+	dbpool, err := pgxpool.New(ctx, dsn)
+	if err != nil {
+		return nil, err
+	}
+	return &Repository{
+		db:  dbpool,
+		sq:  sq.StatementBuilder.PlaceholderFormat(sq.Dollar),
+		ctx: ctx,
+	}, nil
+}
+
+func (r *Repository) FindByUrl(urlId string) ([]*rule.Rule, error) {
+	query, args, err := r.sq.
+		Select("url_id", "position", "condition", "target_url").
+		From("url_rules").
+		Where(sq.Eq{"url_id": urlId}).
+		OrderBy("position").
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.db.Query(r.ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var rules []*rule.Rule
+	for rows.Next() {
+		model := &rule.Rule{}
+		var condition []byte
+		if err := rows.Scan(&model.UrlId, &model.Position, &condition, &model.TargetUrl); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(condition, &model.Condition); err != nil {
+			return nil, err
+		}
+		rules = append(rules, model)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return rules, nil
+}
+
+func (r *Repository) Replace(urlId string, rules []*rule.Rule) error {
+	tx, err := r.db.Begin(r.ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(r.ctx)
+
+	query, args, err := r.sq.Delete("url_rules").Where(sq.Eq{"url_id": urlId}).ToSql()
+	if err != nil {
+		return err
+	}
+	if _, err = tx.Exec(r.ctx, query, args...); err != nil {
+		return err
+	}
+
+	if len(rules) > 0 {
+		insert := r.sq.Insert("url_rules").Columns("url_id", "position", "condition", "target_url")
+		for _, model := range rules {
+			condition, err := json.Marshal(model.Condition)
+			if err != nil {
+				return err
+			}
+			insert = insert.Values(urlId, model.Position, string(condition), model.TargetUrl)
+		}
+		query, args, err = insert.ToSql()
+		if err != nil {
+			return err
+		}
+		if _, err = tx.Exec(r.ctx, query, args...); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit(r.ctx)
+}
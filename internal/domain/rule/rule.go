@@ -0,0 +1,168 @@
+package rule
+
+import (
+	"errors"
+	"fmt"
+	neturl "net/url"
+	"slices"
+	"strings"
+	"time"
+)
+
+var ErrInvalidRule = errors.New("invalid routing rule")
+
+// MaxRules - максимальное число правил у одной ссылки
+const MaxRules = 20
+
+// Типы устройств, которые можно указать в условии
+const (
+	DeviceMobile  = "mobile"
+	DeviceTablet  = "tablet"
+	DeviceDesktop = "desktop"
+	DeviceBot     = "bot"
+)
+
+// Rule - правило перенаправления. Правила проверяются по порядку Position,
+// переход выполняется на TargetUrl первого подошедшего правила.
+type Rule struct {
+	UrlId     string    `db:"url_id"`
+	Position  int       `db:"position"`
+	Condition Condition `db:"condition"`
+	TargetUrl string    `db:"target_url"`
+}
+
+// Condition - условия правила, все заданные условия должны выполняться одновременно
+type Condition struct {
+	Devices []string `json:"devices,omitempty"`
+	Os      []string `json:"os,omitempty"`
+	// Languages - языковые теги из Accept-Language, "en" подходит и для "en-US"
+	Languages []string `json:"languages,omitempty"`
+	// Countries - коды стран ISO 3166-1 alpha-2
+	Countries  []string   `json:"countries,omitempty"`
+	NotBefore  *time.Time `json:"not_before,omitempty"`
+	NotAfter   *time.Time `json:"not_after,omitempty"`
+	QueryParam string     `json:"query_param,omitempty"`
+	// QueryValue - ожидаемое значение параметра, пустое значение означает любое
+	QueryValue string `json:"query_value,omitempty"`
+}
+
+// Client - атрибуты запроса, по которым проверяются условия
+type Client struct {
+	Device   string
+	Os       string
+	Language string
+	Country  string
+	Query    map[string][]string
+	Time     time.Time
+}
+
+// Match возвращает первое подошедшее правило или nil
+func Match(rules []*Rule, client Client) *Rule {
+	for _, r := range rules {
+		if r.Condition.Matches(client) {
+			return r
+		}
+	}
+	return nil
+}
+
+// Matches проверяет условия для клиента
+func (c Condition) Matches(client Client) bool {
+	if len(c.Devices) > 0 && !slices.Contains(c.Devices, client.Device) {
+		return false
+	}
+	if len(c.Os) > 0 && !slices.Contains(c.Os, client.Os) {
+		return false
+	}
+	if len(c.Countries) > 0 && !slices.Contains(c.Countries, client.Country) {
+		return false
+	}
+	if len(c.Languages) > 0 && !matchesLanguage(c.Languages, client.Language) {
+		return false
+	}
+	if c.NotBefore != nil && client.Time.Before(*c.NotBefore) {
+		return false
+	}
+	if c.NotAfter != nil && !client.Time.Before(*c.NotAfter) {
+		return false
+	}
+	if c.QueryParam != "" {
+		values, ok := client.Query[c.QueryParam]
+		if !ok || (c.QueryValue != "" && !slices.Contains(values, c.QueryValue)) {
+			return false
+		}
+	}
+	return true
+}
+
+// Validate приводит значения условий к единому регистру и проверяет правила ссылки
+func Validate(rules []*Rule) error {
+	if len(rules) > MaxRules {
+		return fmt.Errorf("%w: at most %d rules are allowed", ErrInvalidRule, MaxRules)
+	}
+	for i, r := range rules {
+		if err := r.normalize(); err != nil {
+			return fmt.Errorf("%w: rule %d: %s", ErrInvalidRule, i+1, err.Error())
+		}
+		r.Position = i
+	}
+	return nil
+}
+
+func (r *Rule) normalize() error {
+	target, err := neturl.Parse(r.TargetUrl)
+	if err != nil || (target.Scheme != "http" && target.Scheme != "https") || target.Host == "" {
+		return errors.New("target_url must be an absolute http or https URL")
+	}
+
+	c := &r.Condition
+	c.Devices = mapValues(c.Devices, strings.ToLower)
+	for _, device := range c.Devices {
+		switch device {
+		case DeviceMobile, DeviceTablet, DeviceDesktop, DeviceBot:
+		default:
+			return fmt.Errorf("unknown device %q", device)
+		}
+	}
+	c.Os = mapValues(c.Os, strings.ToLower)
+	c.Languages = mapValues(c.Languages, strings.ToLower)
+	c.Countries = mapValues(c.Countries, strings.ToUpper)
+	for _, country := range c.Countries {
+		if len(country) != 2 {
+			return fmt.Errorf("invalid country code %q", country)
+		}
+	}
+	if c.NotBefore != nil && c.NotAfter != nil && !c.NotBefore.Before(*c.NotAfter) {
+		return errors.New("not_before must be earlier than not_after")
+	}
+	if c.QueryValue != "" && c.QueryParam == "" {
+		return errors.New("query_value requires query_param")
+	}
+
+	if len(c.Devices) == 0 && len(c.Os) == 0 && len(c.Languages) == 0 && len(c.Countries) == 0 &&
+		c.NotBefore == nil && c.NotAfter == nil && c.QueryParam == "" {
+		return errors.New("at least one condition is required")
+	}
+	return nil
+}
+
+// matchesLanguage сравнивает язык клиента с тегами условия, тег без региона подходит для любого региона
+func matchesLanguage(languages []string, language string) bool {
+	base, _, _ := strings.Cut(language, "-")
+	for _, l := range languages {
+		if l == language || l == base {
+			return true
+		}
+	}
+	return false
+}
+
+func mapValues(values []string, f func(string) string) []string {
+	var result []string
+	for _, v := range values {
+		if v = strings.TrimSpace(v); v != "" {
+			result = append(result, f(v))
+		}
+	}
+	return result
+}
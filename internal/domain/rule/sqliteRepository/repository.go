@@ -0,0 +1,104 @@
+package sqliteRepository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	sq "github.com/Masterminds/squirrel"
+	"leenwood/yandex-http/config"
+	"leenwood/yandex-http/internal/domain/rule"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+type Repository struct {
+	db  *sql.DB
+	sq  sq.StatementBuilderType
+	ctx context.Context
+}
+
+func NewRepository(ctx context.Context, _ config.DatabaseConfig) (*Repository, error) {
+	dsn := "database.sqlite"
+	db, err := sql.Open("sqlite3", dsn)
+	if err != nil {
+		return nil, err
+	}
+	return &Repository{
+		db:  db,
+		sq:  sq.StatementBuilder.PlaceholderFormat(sq.Question),
+		ctx: ctx,
+	}, nil
+}
+
+func (r *Repository) FindByUrl(urlId string) ([]*rule.Rule, error) {
+	query, args, err := r.sq.
+		Select("url_id", "position", "condition", "target_url").
+		From("url_rules").
+		Where(sq.Eq{"url_id": urlId}).
+		OrderBy("position").
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.db.QueryContext(r.ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var rules []*rule.Rule
+	for rows.Next() {
+		model := &rule.Rule{}
+		var condition []byte
+		if err := rows.Scan(&model.UrlId, &model.Position, &condition, &model.TargetUrl); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(condition, &model.Condition); err != nil {
+			return nil, err
+		}
+		rules = append(rules, model)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return rules, nil
+}
+
+func (r *Repository) Replace(urlId string, rules []*rule.Rule) error {
+	tx, err := r.db.BeginTx(r.ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query, args, err := r.sq.Delete("url_rules").Where(sq.Eq{"url_id": urlId}).ToSql()
+	if err != nil {
+		return err
+	}
+	if _, err = tx.ExecContext(r.ctx, query, args...); err != nil {
+		return err
+	}
+
+	if len(rules) > 0 {
+		insert := r.sq.Insert("url_rules").Columns("url_id", "position", "condition", "target_url")
+		for _, model := range rules {
+			condition, err := json.Marshal(model.Condition)
+			if err != nil {
+				return err
+			}
+			insert = insert.Values(urlId, model.Position, string(condition), model.TargetUrl)
+		}
+		query, args, err = insert.ToSql()
+		if err != nil {
+			return err
+		}
+		if _, err = tx.ExecContext(r.ctx, query, args...); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
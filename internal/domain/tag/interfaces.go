@@ -0,0 +1,13 @@
+package tag
+
+type RepositoryInterface interface {
+	// FindByUrls возвращает метки каждой из ссылок по алфавиту, ссылки без меток в ответ не попадают
+	FindByUrls(urlIds []string) (map[string][]string, error)
+	// Replace заменяет все метки ссылки одной транзакцией
+	Replace(urlId string, tags []string) error
+	// FindByOwner возвращает метки ссылок владельца с числом ссылок по каждой
+	FindByOwner(ownerId string) ([]*Tag, error)
+	// Rename переименовывает метку во всех ссылках владельца. Если новая метка уже есть,
+	// метки сливаются. Возвращает число ссылок с новой меткой.
+	Rename(ownerId, from, to string) (uint64, error)
+}
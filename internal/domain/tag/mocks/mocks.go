@@ -0,0 +1,89 @@
+package mocks
+
+import (
+	gomock "github.com/golang/mock/gomock"
+	tag "leenwood/yandex-http/internal/domain/tag"
+	reflect "reflect"
+)
+
+// MockRepositoryInterface is a mock of RepositoryInterface interface
+type MockRepositoryInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockRepositoryInterfaceMockRecorder
+}
+
+// MockRepositoryInterfaceMockRecorder is the mock recorder for MockRepositoryInterface
+type MockRepositoryInterfaceMockRecorder struct {
+	mock *MockRepositoryInterface
+}
+
+// NewMockRepositoryInterface creates a new mock instance
+func NewMockRepositoryInterface(ctrl *gomock.Controller) *MockRepositoryInterface {
+	mock := &MockRepositoryInterface{ctrl: ctrl}
+	mock.recorder = &MockRepositoryInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockRepositoryInterface) EXPECT() *MockRepositoryInterfaceMockRecorder {
+	return m.recorder
+}
+
+// FindByUrls mocks base method
+func (m *MockRepositoryInterface) FindByUrls(urlIds []string) (map[string][]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByUrls", urlIds)
+	ret0, _ := ret[0].(map[string][]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByUrls indicates an expected call of FindByUrls
+func (mr *MockRepositoryInterfaceMockRecorder) FindByUrls(urlIds interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByUrls", reflect.TypeOf((*MockRepositoryInterface)(nil).FindByUrls), urlIds)
+}
+
+// Replace mocks base method
+func (m *MockRepositoryInterface) Replace(urlId string, tags []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Replace", urlId, tags)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Replace indicates an expected call of Replace
+func (mr *MockRepositoryInterfaceMockRecorder) Replace(urlId, tags interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Replace", reflect.TypeOf((*MockRepositoryInterface)(nil).Replace), urlId, tags)
+}
+
+// FindByOwner mocks base method
+func (m *MockRepositoryInterface) FindByOwner(ownerId string) ([]*tag.Tag, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByOwner", ownerId)
+	ret0, _ := ret[0].([]*tag.Tag)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByOwner indicates an expected call of FindByOwner
+func (mr *MockRepositoryInterfaceMockRecorder) FindByOwner(ownerId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByOwner", reflect.TypeOf((*MockRepositoryInterface)(nil).FindByOwner), ownerId)
+}
+
+// Rename mocks base method
+func (m *MockRepositoryInterface) Rename(ownerId, from, to string) (uint64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Rename", ownerId, from, to)
+	ret0, _ := ret[0].(uint64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Rename indicates an expected call of Rename
+func (mr *MockRepositoryInterfaceMockRecorder) Rename(ownerId, from, to interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Rename", reflect.TypeOf((*MockRepositoryInterface)(nil).Rename), ownerId, from, to)
+}
@@ -0,0 +1,203 @@
+package sqliteRepository
+
+import (
+	"context"
+	"database/sql"
+	sq "github.com/Masterminds/squirrel"
+	"leenwood/yandex-http/config"
+	"leenwood/yandex-http/internal/domain/tag"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+type Repository struct {
+	db  *sql.DB
+	sq  sq.StatementBuilderType
+	ctx context.Context
+}
+
+func NewRepository(ctx context.Context, _ config.DatabaseConfig) (*Repository, error) {
+	dsn := "database.sqlite"
+	db, err := sql.Open("sqlite3", dsn)
+	if err != nil {
+		return nil, err
+	}
+	return &Repository{
+		db:  db,
+		sq:  sq.StatementBuilder.PlaceholderFormat(sq.Question),
+		ctx: ctx,
+	}, nil
+}
+
+func (r *Repository) FindByUrls(urlIds []string) (map[string][]string, error) {
+	tags := make(map[string][]string)
+	if len(urlIds) == 0 {
+		return tags, nil
+	}
+
+	query, args, err := r.sq.
+		Select("url_id", "tag").
+		From("url_tags").
+		Where(sq.Eq{"url_id": urlIds}).
+		OrderBy("url_id", "tag").
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.db.QueryContext(r.ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var urlId, name string
+		if err := rows.Scan(&urlId, &name); err != nil {
+			return nil, err
+		}
+		tags[urlId] = append(tags[urlId], name)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return tags, nil
+}
+
+func (r *Repository) Replace(urlId string, tags []string) error {
+	tx, err := r.db.BeginTx(r.ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query, args, err := r.sq.Delete("url_tags").Where(sq.Eq{"url_id": urlId}).ToSql()
+	if err != nil {
+		return err
+	}
+	if _, err = tx.ExecContext(r.ctx, query, args...); err != nil {
+		return err
+	}
+
+	if len(tags) > 0 {
+		insert := r.sq.Insert("url_tags").Columns("url_id", "tag")
+		for _, name := range tags {
+			insert = insert.Values(urlId, name)
+		}
+		query, args, err = insert.ToSql()
+		if err != nil {
+			return err
+		}
+		if _, err = tx.ExecContext(r.ctx, query, args...); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (r *Repository) FindByOwner(ownerId string) ([]*tag.Tag, error) {
+	query, args, err := r.sq.
+		Select("t.tag", "COUNT(*)").
+		From("url_tags t").
+		Join("urls u ON u.id = t.url_id").
+		Where(sq.Eq{"u.owner_id": ownerId}).
+		GroupBy("t.tag").
+		OrderBy("COUNT(*) DESC", "t.tag").
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.db.QueryContext(r.ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tags := []*tag.Tag{}
+	for rows.Next() {
+		model := &tag.Tag{}
+		if err := rows.Scan(&model.Name, &model.Links); err != nil {
+			return nil, err
+		}
+		tags = append(tags, model)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return tags, nil
+}
+
+func (r *Repository) Rename(ownerId, from, to string) (uint64, error) {
+	tx, err := r.db.BeginTx(r.ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	owned := sq.Expr("url_id IN (SELECT id FROM urls WHERE owner_id = ?)", ownerId)
+
+	// Ссылки, у которых уже есть новая метка, теряют старую, иначе переименование нарушит первичный ключ
+	query, args, err := r.sq.
+		Delete("url_tags").
+		Where(sq.Eq{"tag": from}).
+		Where(owned).
+		Where(sq.Expr("url_id IN (SELECT url_id FROM url_tags WHERE tag = ?)", to)).
+		ToSql()
+	if err != nil {
+		return 0, err
+	}
+	merged, err := tx.ExecContext(r.ctx, query, args...)
+	if err != nil {
+		return 0, err
+	}
+
+	query, args, err = r.sq.
+		Update("url_tags").
+		Set("tag", to).
+		Where(sq.Eq{"tag": from}).
+		Where(owned).
+		ToSql()
+	if err != nil {
+		return 0, err
+	}
+	renamed, err := tx.ExecContext(r.ctx, query, args...)
+	if err != nil {
+		return 0, err
+	}
+	mergedRows, err := merged.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+	renamedRows, err := renamed.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+	if mergedRows+renamedRows == 0 {
+		return 0, tag.ErrNotFound
+	}
+
+	query, args, err = r.sq.
+		Select("COUNT(*)").
+		From("url_tags").
+		Where(sq.Eq{"tag": to}).
+		Where(owned).
+		ToSql()
+	if err != nil {
+		return 0, err
+	}
+	var links uint64
+	if err = tx.QueryRowContext(r.ctx, query, args...).Scan(&links); err != nil {
+		return 0, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return 0, err
+	}
+	return links, nil
+}
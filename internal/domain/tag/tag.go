@@ -0,0 +1,62 @@
+package tag
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+var (
+	ErrInvalidTag = errors.New("invalid tag")
+	ErrNotFound   = errors.New("tag not found")
+)
+
+const (
+	// MaxTags - наибольшее число меток у одной ссылки
+	MaxTags = 20
+	// MaxLength - наибольшая длина метки в символах
+	MaxLength = 50
+)
+
+// Tag - метка владельца с числом отмеченных ссылок
+type Tag struct {
+	Name  string `db:"tag"`
+	Links uint64 `db:"links"`
+}
+
+// Normalize приводит метку к нижнему регистру и проверяет её: допускаются буквы,
+// цифры, пробел, "-", "_", "." и "/"
+func Normalize(name string) (string, error) {
+	name = strings.ToLower(strings.Join(strings.Fields(name), " "))
+	if name == "" || utf8.RuneCountInString(name) > MaxLength {
+		return "", fmt.Errorf("%w: %q must be 1 to %d characters", ErrInvalidTag, name, MaxLength)
+	}
+	for _, r := range name {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && !strings.ContainsRune(" -_./", r) {
+			return "", fmt.Errorf("%w: %q contains %q", ErrInvalidTag, name, r)
+		}
+	}
+	return name, nil
+}
+
+// NormalizeAll нормализует метки ссылки, убирая повторы с сохранением порядка
+func NormalizeAll(names []string) ([]string, error) {
+	tags := make([]string, 0, len(names))
+	seen := make(map[string]bool, len(names))
+	for _, name := range names {
+		normalized, err := Normalize(name)
+		if err != nil {
+			return nil, err
+		}
+		if !seen[normalized] {
+			seen[normalized] = true
+			tags = append(tags, normalized)
+		}
+	}
+	if len(tags) > MaxTags {
+		return nil, fmt.Errorf("%w: at most %d tags per link", ErrInvalidTag, MaxTags)
+	}
+	return tags, nil
+}
@@ -0,0 +1,237 @@
+package cachedRepository
+
+import (
+	"errors"
+	"leenwood/yandex-http/config"
+	"leenwood/yandex-http/internal/cache"
+	"leenwood/yandex-http/internal/domain/url"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// Stats - счётчики кэша с момента запуска
+type Stats struct {
+	Hits      uint64
+	Misses    uint64
+	Evictions uint64
+	// Size - сколько записей сейчас в кэше, включая отметки об отсутствующих ссылках
+	Size int
+}
+
+// Cache хранит ссылки по id. Один кэш разделяют все репозитории процесса,
+// чтобы запись сбрасывалась при изменении ссылки через любой из них.
+// Изменения в обход Repository становятся видны не позже чем через TTL.
+type Cache struct {
+	lru         *cache.Lru[string, entry]
+	group       singleflight.Group
+	ttl         time.Duration
+	negativeTTL time.Duration
+	now         func() time.Time
+
+	// mu и loading не дают загрузке, начатой до сброса, вернуть в кэш устаревшую ссылку
+	mu      sync.Mutex
+	loading map[string]*load
+
+	hits      atomic.Uint64
+	misses    atomic.Uint64
+	evictions atomic.Uint64
+}
+
+// load - загрузки ссылки, которые сейчас идут в базу.
+// generation растёт при каждом сбросе записи, пока загрузки не закончились.
+type load struct {
+	generation uint64
+	count      int
+}
+
+// entry - найденная ссылка или отметка, что ссылки нет
+type entry struct {
+	url     url.Url
+	found   bool
+	expires time.Time
+}
+
+func NewCache(cfg config.CacheConfig) *Cache {
+	return &Cache{
+		lru:         cache.NewLru[string, entry](cfg.Size),
+		ttl:         cfg.TTL,
+		negativeTTL: cfg.NegativeTTL,
+		now:         time.Now,
+		loading:     make(map[string]*load),
+	}
+}
+
+func (c *Cache) Stats() Stats {
+	return Stats{
+		Hits:      c.hits.Load(),
+		Misses:    c.misses.Load(),
+		Evictions: c.evictions.Load(),
+		Size:      c.lru.Len(),
+	}
+}
+
+// Invalidate сбрасывает записи ссылок, в том числе отметки об их отсутствии
+func (c *Cache) Invalidate(ids ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, id := range ids {
+		if l, ok := c.loading[id]; ok {
+			l.generation++
+		}
+		c.lru.Remove(id)
+	}
+}
+
+// get возвращает копию ссылки из кэша, а при промахе загружает её через load.
+// Одновременные промахи по одному id загружают ссылку одним запросом.
+func (c *Cache) get(id string, load func() (*url.Url, error)) (*url.Url, error) {
+	if e, ok := c.lru.Get(id); ok && c.now().Before(e.expires) {
+		c.hits.Add(1)
+		if !e.found {
+			return nil, url.ErrNotFound
+		}
+		return &e.url, nil
+	}
+	c.misses.Add(1)
+
+	c.mu.Lock()
+	var generation uint64
+	if l, ok := c.loading[id]; ok {
+		generation = l.generation
+	}
+	c.mu.Unlock()
+
+	// Запросы после сброса не присоединяются к загрузке, начатой до него
+	key := id + " " + strconv.FormatUint(generation, 10)
+	value, err, _ := c.group.Do(key, func() (any, error) {
+		generation := c.begin(id)
+		model, err := load()
+		var e *entry
+		switch {
+		case err == nil:
+			e = &entry{url: *model, found: true, expires: c.now().Add(c.ttl)}
+		case errors.Is(err, url.ErrNotFound) && c.negativeTTL > 0:
+			e = &entry{expires: c.now().Add(c.negativeTTL)}
+		}
+		if !c.finish(id, generation, e) {
+			// Устаревший результат получают только те, кто ждал его до сброса
+			c.group.Forget(key)
+		}
+		return model, err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Каждый вызывающий получает свою копию: ссылку меняют перед сохранением
+	model := *value.(*url.Url)
+	return &model, nil
+}
+
+// begin отмечает начало загрузки ссылки и возвращает поколение её записи
+func (c *Cache) begin(id string) uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	l, ok := c.loading[id]
+	if !ok {
+		l = &load{}
+		c.loading[id] = l
+	}
+	l.count++
+	return l.generation
+}
+
+// finish сохраняет загруженную запись e, если ссылку не сбросили во время загрузки.
+// Возвращает false, если запись устарела.
+func (c *Cache) finish(id string, generation uint64, e *entry) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	l := c.loading[id]
+	if l.count--; l.count == 0 {
+		delete(c.loading, id)
+	}
+	if l.generation != generation {
+		return false
+	}
+	if e != nil && c.lru.Add(id, *e) {
+		c.evictions.Add(1)
+	}
+	return true
+}
+
+// Repository читает ссылки по id через кэш, остальные запросы передаёт репозиторию без изменений.
+// Любое изменение ссылки через Repository сбрасывает её запись в кэше.
+type Repository struct {
+	url.RepositoryInterface
+	cache *Cache
+}
+
+func NewRepository(r url.RepositoryInterface, cache *Cache) *Repository {
+	return &Repository{RepositoryInterface: r, cache: cache}
+}
+
+func (r *Repository) FindById(id string) (*url.Url, error) {
+	return r.cache.get(id, func() (*url.Url, error) {
+		return r.RepositoryInterface.FindById(id)
+	})
+}
+
+// Save сбрасывает отметку об отсутствии ссылки с тем же id
+func (r *Repository) Save(model *url.Url) (*url.Url, error) {
+	saved, err := r.RepositoryInterface.Save(model)
+	if err == nil {
+		r.cache.Invalidate(saved.Id)
+	}
+	return saved, err
+}
+
+func (r *Repository) SaveMany(models []*url.Url) ([]*url.Url, error) {
+	return r.invalidateSaved(r.RepositoryInterface.SaveMany(models))
+}
+
+func (r *Repository) SaveWithin(models []*url.Url, limit url.Limit) ([]*url.Url, error) {
+	return r.invalidateSaved(r.RepositoryInterface.SaveWithin(models, limit))
+}
+
+// invalidateSaved сбрасывает отметки об отсутствии сохранённых ссылок
+func (r *Repository) invalidateSaved(saved []*url.Url, err error) ([]*url.Url, error) {
+	if err == nil {
+		ids := make([]string, 0, len(saved))
+		for _, model := range saved {
+			ids = append(ids, model.Id)
+		}
+		r.cache.Invalidate(ids...)
+	}
+	return saved, err
+}
+
+// Update сбрасывает запись и при ошибке: неизвестно, изменилась ли ссылка в базе
+func (r *Repository) Update(model *url.Url) (*url.Url, error) {
+	if model != nil {
+		defer r.cache.Invalidate(model.Id)
+	}
+	return r.RepositoryInterface.Update(model)
+}
+
+func (r *Repository) UpdateMetadata(id string, metadata url.Metadata) error {
+	defer r.cache.Invalidate(id)
+	return r.RepositoryInterface.UpdateMetadata(id, metadata)
+}
+
+func (r *Repository) UpdateHealth(id string, health url.Health) error {
+	defer r.cache.Invalidate(id)
+	return r.RepositoryInterface.UpdateHealth(id, health)
+}
+
+// IncrementClicks не сбрасывает запись, иначе каждый переход вытеснял бы самые посещаемые ссылки.
+// Счётчик переходов в кэше отстаёт от базы не дольше TTL.
+func (r *Repository) IncrementClicks(id string) error {
+	return r.RepositoryInterface.IncrementClicks(id)
+}
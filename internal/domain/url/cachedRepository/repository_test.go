@@ -0,0 +1,218 @@
+package cachedRepository
+
+import (
+	"errors"
+	"leenwood/yandex-http/config"
+	"leenwood/yandex-http/internal/domain/url"
+	"leenwood/yandex-http/internal/domain/url/mocks"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestCachedRepository(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Cached Repository Test Suite")
+}
+
+var _ = Describe("Repository", func() {
+	var (
+		ctrl     *gomock.Controller
+		mockRepo *mocks.MockRepositoryInterface
+		c        *Cache
+		now      time.Time
+		r        *Repository
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		mockRepo = mocks.NewMockRepositoryInterface(ctrl)
+		now = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+		c = NewCache(config.CacheConfig{Size: 2, TTL: time.Minute, NegativeTTL: 5 * time.Second})
+		c.now = func() time.Time { return now }
+		r = NewRepository(mockRepo, c)
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	It("should read a link from the database once until it expires", func() {
+		mockRepo.EXPECT().FindById("abc").Return(&url.Url{Id: "abc", OriginalUrl: "https://example.com"}, nil).Times(2)
+
+		for range 3 {
+			found, err := r.FindById("abc")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(found.OriginalUrl).To(Equal("https://example.com"))
+		}
+		now = now.Add(time.Minute)
+		_, err := r.FindById("abc")
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(c.Stats()).To(Equal(Stats{Hits: 2, Misses: 2, Size: 1}))
+	})
+
+	It("should return a copy that callers can change", func() {
+		mockRepo.EXPECT().FindById("abc").Return(&url.Url{Id: "abc", ClickCount: 1}, nil)
+
+		found, _ := r.FindById("abc")
+		found.ClickCount = 100
+		found, _ = r.FindById("abc")
+
+		Expect(found.ClickCount).To(Equal(uint64(1)))
+	})
+
+	It("should remember unknown ids for the negative TTL", func() {
+		mockRepo.EXPECT().FindById("missing").Return(nil, url.ErrNotFound).Times(2)
+
+		_, err := r.FindById("missing")
+		Expect(err).To(MatchError(url.ErrNotFound))
+		_, err = r.FindById("missing")
+		Expect(err).To(MatchError(url.ErrNotFound))
+
+		now = now.Add(5 * time.Second)
+		_, err = r.FindById("missing")
+		Expect(err).To(MatchError(url.ErrNotFound))
+	})
+
+	It("should not cache database errors", func() {
+		mockRepo.EXPECT().FindById("abc").Return(nil, errors.New("connection refused")).Times(2)
+
+		_, err := r.FindById("abc")
+		Expect(err).To(HaveOccurred())
+		_, err = r.FindById("abc")
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should forget an unknown id once a link with it is saved", func() {
+		gomock.InOrder(
+			mockRepo.EXPECT().FindById("bio").Return(nil, url.ErrNotFound),
+			mockRepo.EXPECT().Save(gomock.Any()).Return(&url.Url{Id: "bio"}, nil),
+			mockRepo.EXPECT().FindById("bio").Return(&url.Url{Id: "bio"}, nil),
+		)
+
+		_, _ = r.FindById("bio")
+		_, err := r.Save(&url.Url{Id: "bio"})
+		Expect(err).NotTo(HaveOccurred())
+
+		found, err := r.FindById("bio")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(found.Id).To(Equal("bio"))
+	})
+
+	It("should reload a link after it is updated", func() {
+		gomock.InOrder(
+			mockRepo.EXPECT().FindById("abc").Return(&url.Url{Id: "abc", OriginalUrl: "https://old.example"}, nil),
+			mockRepo.EXPECT().Update(gomock.Any()).Return(&url.Url{Id: "abc"}, nil),
+			mockRepo.EXPECT().FindById("abc").Return(&url.Url{Id: "abc", OriginalUrl: "https://new.example"}, nil),
+		)
+
+		_, _ = r.FindById("abc")
+		_, err := r.Update(&url.Url{Id: "abc", OriginalUrl: "https://new.example"})
+		Expect(err).NotTo(HaveOccurred())
+
+		found, _ := r.FindById("abc")
+		Expect(found.OriginalUrl).To(Equal("https://new.example"))
+	})
+
+	It("should keep the link cached when a click is counted", func() {
+		mockRepo.EXPECT().FindById("abc").Return(&url.Url{Id: "abc"}, nil)
+		mockRepo.EXPECT().IncrementClicks("abc").Return(nil)
+
+		_, _ = r.FindById("abc")
+		Expect(r.IncrementClicks("abc")).To(Succeed())
+		_, _ = r.FindById("abc")
+
+		Expect(c.Stats().Hits).To(Equal(uint64(1)))
+	})
+
+	It("should count evictions of the least recently used links", func() {
+		mockRepo.EXPECT().FindById(gomock.Any()).DoAndReturn(func(id string) (*url.Url, error) {
+			return &url.Url{Id: id}, nil
+		}).Times(3)
+
+		for _, id := range []string{"a", "b", "c"} {
+			_, _ = r.FindById(id)
+		}
+
+		Expect(c.Stats().Evictions).To(Equal(uint64(1)))
+		Expect(c.Stats().Size).To(Equal(2))
+	})
+
+	It("should load a link once for concurrent misses", func() {
+		release := make(chan struct{})
+		mockRepo.EXPECT().FindById("hot").DoAndReturn(func(string) (*url.Url, error) {
+			<-release
+			return &url.Url{Id: "hot"}, nil
+		}).Times(1)
+
+		var wg sync.WaitGroup
+		for range 10 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				defer GinkgoRecover()
+				found, err := r.FindById("hot")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(found.Id).To(Equal("hot"))
+			}()
+		}
+		Eventually(func() uint64 { return c.Stats().Misses }).Should(Equal(uint64(10)))
+		close(release)
+		wg.Wait()
+	})
+
+	It("should not cache a link loaded before it was changed", func() {
+		loaded := make(chan struct{})
+		release := make(chan struct{})
+		gomock.InOrder(
+			mockRepo.EXPECT().FindById("abc").DoAndReturn(func(string) (*url.Url, error) {
+				close(loaded)
+				<-release
+				return &url.Url{Id: "abc", OriginalUrl: "https://old.example"}, nil
+			}),
+			mockRepo.EXPECT().FindById("abc").Return(&url.Url{Id: "abc", OriginalUrl: "https://new.example"}, nil),
+		)
+
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			_, _ = r.FindById("abc")
+		}()
+		<-loaded
+		c.Invalidate("abc")
+		close(release)
+		<-done
+
+		found, _ := r.FindById("abc")
+		Expect(found.OriginalUrl).To(Equal("https://new.example"))
+	})
+
+	It("should keep caching a link while another one is changed", func() {
+		loaded := make(chan struct{})
+		release := make(chan struct{})
+		mockRepo.EXPECT().FindById("abc").DoAndReturn(func(string) (*url.Url, error) {
+			close(loaded)
+			<-release
+			return &url.Url{Id: "abc"}, nil
+		})
+
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			_, _ = r.FindById("abc")
+		}()
+		<-loaded
+		c.Invalidate("other")
+		close(release)
+		<-done
+
+		_, err := r.FindById("abc")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(c.Stats().Hits).To(Equal(uint64(1)))
+	})
+})
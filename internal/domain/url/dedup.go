@@ -0,0 +1,73 @@
+package url
+
+import (
+	"errors"
+	neturl "net/url"
+	"strings"
+)
+
+var ErrInvalidDedup = errors.New("invalid dedup policy")
+
+// DedupPolicy - что делать, если ссылка на такой же адрес уже есть
+type DedupPolicy string
+
+const (
+	// DedupReuse - выдать существующую ссылку любого владельца
+	DedupReuse DedupPolicy = "reuse"
+	// DedupNew - всегда создавать новую ссылку
+	DedupNew DedupPolicy = "new"
+	// DedupOwner - выдать существующую ссылку, только если она того же владельца
+	DedupOwner DedupPolicy = "owner"
+)
+
+// ParseDedupPolicy проверяет значение, пустая строка означает DedupReuse
+func ParseDedupPolicy(value string) (DedupPolicy, error) {
+	switch p := DedupPolicy(strings.ToLower(value)); p {
+	case "":
+		return DedupReuse, nil
+	case DedupReuse, DedupNew, DedupOwner:
+		return p, nil
+	default:
+		return "", ErrInvalidDedup
+	}
+}
+
+// Normalize приводит адрес к виду, по которому ищутся одинаковые ссылки: схема по умолчанию https,
+// схема и хост в нижнем регистре, без порта по умолчанию, пустой путь заменяется на "/"
+func Normalize(destination string) string {
+	destination = strings.TrimSpace(destination)
+	if !strings.Contains(destination, "://") {
+		destination = "https://" + destination
+	}
+	parsed, err := neturl.Parse(destination)
+	if err != nil || parsed.Host == "" {
+		return destination
+	}
+
+	parsed.Scheme = strings.ToLower(parsed.Scheme)
+	parsed.Host = strings.ToLower(parsed.Host)
+	if port := parsed.Port(); (parsed.Scheme == "https" && port == "443") || (parsed.Scheme == "http" && port == "80") {
+		parsed.Host = strings.TrimSuffix(parsed.Host, ":"+port)
+	}
+	if parsed.Path == "" {
+		parsed.Path = "/"
+	}
+	return parsed.String()
+}
+
+// DedupKey - ключ, по которому ссылку можно выдать повторно. Ключ включает домен ссылки,
+// а при DedupOwner - и владельца. Для DedupNew ключа нет.
+func DedupKey(policy DedupPolicy, domain, ownerId, destination string) string {
+	key := Normalize(destination)
+	if domain != "" {
+		key = domain + " " + key
+	}
+	switch policy {
+	case DedupNew:
+		return ""
+	case DedupOwner:
+		return "owner:" + ownerId + " " + key
+	default:
+		return key
+	}
+}
@@ -0,0 +1,67 @@
+package url
+
+import "time"
+
+// Status - состояние ссылки для отбора в списке
+type Status string
+
+const (
+	// StatusPublic - ссылка открывается без пароля
+	StatusPublic Status = "public"
+	// StatusProtected - перед переходом нужно ввести пароль
+	StatusProtected Status = "protected"
+)
+
+// SortField - поле, по которому упорядочен список ссылок. При равных значениях ссылки упорядочены по id.
+type SortField string
+
+const (
+	SortCreatedDate SortField = "created_date"
+	SortClickCount  SortField = "click_count"
+)
+
+// Sort - порядок списка, нулевое значение - по возрастанию даты создания
+type Sort struct {
+	Field SortField
+	Desc  bool
+}
+
+// Filter - условия отбора ссылок, пустые поля не учитываются
+type Filter struct {
+	Utm     Utm
+	OwnerId string
+	// Domain - хост брендированного домена
+	Domain string
+	// CreatedFrom и CreatedTo ограничивают дату создания: включая начало и не включая конец
+	CreatedFrom *time.Time
+	CreatedTo   *time.Time
+	Status      Status
+	// MinClicks и MaxClicks ограничивают число переходов включительно
+	MinClicks *uint64
+	MaxClicks *uint64
+	// Search - подстрока адреса перехода, id или названия без учёта регистра
+	Search string
+	// Tags - у ссылки должны быть все эти метки
+	Tags []string
+	// FolderIds - ссылка лежит в одной из этих папок
+	FolderIds []string
+	// Broken - только ссылки, страница назначения которых при последней проверке не ответила или вернула ошибку
+	Broken bool
+	// Sort - порядок списка, Count его не учитывает
+	Sort Sort
+}
+
+// Cursor - позиция ссылки в списке: значение поля сортировки и id
+type Cursor struct {
+	CreatedDate time.Time
+	ClickCount  uint64
+	Id          string
+}
+
+// Page - окно списка после курсора или перед ним. Без курсора окно начинается с начала списка.
+type Page struct {
+	Cursor *Cursor
+	// Backward - окно перед курсором, ссылки всё равно возвращаются в порядке списка
+	Backward bool
+	Limit    int
+}
@@ -1,9 +1,35 @@
 package url
 
+import "time"
+
 type RepositoryInterface interface {
 	FindById(id string) (*Url, error)
-	FindByUrl(url string) (*Url, error)
-	Save(originalUrl string, shortUuid string) (*Url, error)
-	FindAll(page, limit int) ([]*Url, error)
+	FindByUrl(url string, domain string) (*Url, error)
+	// FindByDedupKey возвращает ссылку, которую можно выдать повторно, или nil
+	FindByDedupKey(key string) (*Url, error)
+	// Save возвращает ErrUrlExists, если ссылка с таким DedupKey уже есть
+	Save(url *Url) (*Url, error)
+	// SaveMany сохраняет ссылки одной транзакцией: либо все, либо ни одной
+	SaveMany(urls []*Url) ([]*Url, error)
+	// SaveWithin сохраняет ссылки как SaveMany, если после этого не будет превышен limit, иначе возвращает LimitError.
+	// Ссылки считаются в той же транзакции, поэтому параллельные сохранения не обходят лимит.
+	SaveWithin(urls []*Url, limit Limit) ([]*Url, error)
+	FindAll(page, limit int, filter Filter) ([]*Url, error)
+	// FindPage читает окно списка по курсору в порядке filter.Sort, не пропуская и не повторяя ссылки между окнами
+	FindPage(filter Filter, page Page) ([]*Url, error)
+	Count(filter Filter) (uint64, error)
+	// ForEach читает ссылки одним запросом по мере обработки, не загружая их все в память.
+	// Ошибка fn прекращает чтение и возвращается.
+	ForEach(filter Filter, fn func(*Url) error) error
+	// Update сохраняет настройки ссылки. Счётчик переходов и дата создания не перезаписываются:
+	// модель могла быть прочитана до последних переходов.
 	Update(url *Url) (*Url, error)
+	// UpdateMetadata сохраняет только сведения о странице назначения, не затрагивая остальные поля ссылки
+	UpdateMetadata(id string, metadata Metadata) error
+	// FindCheckDue возвращает ссылки, не проверявшиеся с checkedBefore: сначала непроверенные, затем давно проверенные
+	FindCheckDue(checkedBefore time.Time, limit int) ([]*Url, error)
+	UpdateHealth(id string, health Health) error
+	// IncrementClicks увеличивает счётчик переходов в базе, не перезаписывая остальные поля ссылки
+	IncrementClicks(id string) error
+	CountByOwner(ownerId string) (Counts, error)
 }
@@ -0,0 +1,57 @@
+package url
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrLimitReached - сохранение превысило бы число ссылок, разрешённое Limit
+var ErrLimitReached = errors.New("link limit reached")
+
+// Limit - сколько ссылок может быть у владельца или у анонимного клиента.
+// Репозиторий проверяет его в транзакции сохранения. Нулевое значение лимита не ограничивает.
+type Limit struct {
+	// OwnerId - чьи ссылки считаются, пустая строка - анонимные ссылки с адреса CreatorIp
+	OwnerId   string
+	CreatorIp string
+	// Since - считаются только ссылки, созданные не раньше, нулевое значение - все ссылки
+	Since            time.Time
+	MaxLinks         uint64
+	MaxCustomAliases uint64
+}
+
+func (l Limit) Enabled() bool {
+	return l.MaxLinks > 0 || l.MaxCustomAliases > 0
+}
+
+// Check возвращает LimitError, если к уже созданным ссылкам counts нельзя добавить models
+func (l Limit) Check(counts Counts, models []*Url) error {
+	var customAliases uint64
+	for _, model := range models {
+		if model.CustomId {
+			customAliases++
+		}
+	}
+	if l.MaxLinks > 0 && counts.Links+uint64(len(models)) > l.MaxLinks {
+		return &LimitError{Limit: "links", Max: l.MaxLinks}
+	}
+	if customAliases > 0 && l.MaxCustomAliases > 0 && counts.CustomAliases+customAliases > l.MaxCustomAliases {
+		return &LimitError{Limit: "custom aliases", Max: l.MaxCustomAliases}
+	}
+	return nil
+}
+
+// LimitError сообщает, какой лимит превысило бы сохранение
+type LimitError struct {
+	Limit string
+	Max   uint64
+}
+
+func (e *LimitError) Error() string {
+	return fmt.Sprintf("%s: %s limit is %d", ErrLimitReached, e.Limit, e.Max)
+}
+
+func (e *LimitError) Is(target error) bool {
+	return target == ErrLimitReached
+}
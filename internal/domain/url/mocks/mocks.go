@@ -4,6 +4,7 @@ import (
 	gomock "github.com/golang/mock/gomock"
 	url "leenwood/yandex-http/internal/domain/url"
 	reflect "reflect"
+	time "time"
 )
 
 // MockRepositoryInterface is a mock of RepositoryInterface interface
@@ -45,45 +46,134 @@ func (mr *MockRepositoryInterfaceMockRecorder) FindById(id interface{}) *gomock.
 }
 
 // FindByUrl mocks base method
-func (m *MockRepositoryInterface) FindByUrl(originalUrl string) (*url.Url, error) {
+func (m *MockRepositoryInterface) FindByUrl(originalUrl string, domain string) (*url.Url, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "FindByUrl", originalUrl)
+	ret := m.ctrl.Call(m, "FindByUrl", originalUrl, domain)
 	ret0, _ := ret[0].(*url.Url)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // FindByUrl indicates an expected call of FindByUrl
-func (mr *MockRepositoryInterfaceMockRecorder) FindByUrl(url interface{}) *gomock.Call {
+func (mr *MockRepositoryInterfaceMockRecorder) FindByUrl(url, domain interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByUrl", reflect.TypeOf((*MockRepositoryInterface)(nil).FindByUrl), url)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByUrl", reflect.TypeOf((*MockRepositoryInterface)(nil).FindByUrl), url, domain)
+}
+
+// FindByDedupKey mocks base method
+func (m *MockRepositoryInterface) FindByDedupKey(key string) (*url.Url, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByDedupKey", key)
+	ret0, _ := ret[0].(*url.Url)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByDedupKey indicates an expected call of FindByDedupKey
+func (mr *MockRepositoryInterfaceMockRecorder) FindByDedupKey(key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByDedupKey", reflect.TypeOf((*MockRepositoryInterface)(nil).FindByDedupKey), key)
 }
 
 // Save mocks base method
-func (m *MockRepositoryInterface) Save(originalUrl string, shortUuid string) (*url.Url, error) {
+func (m *MockRepositoryInterface) Save(model *url.Url) (*url.Url, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Save", originalUrl, shortUuid)
+	ret := m.ctrl.Call(m, "Save", model)
 	ret0, _ := ret[0].(*url.Url)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Save indicates an expected call of Save
-func (mr *MockRepositoryInterfaceMockRecorder) Save(originalUrl, shortUuid interface{}) *gomock.Call {
+func (mr *MockRepositoryInterfaceMockRecorder) Save(model interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockRepositoryInterface)(nil).Save), model)
+}
+
+// SaveMany mocks base method
+func (m *MockRepositoryInterface) SaveMany(models []*url.Url) ([]*url.Url, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveMany", models)
+	ret0, _ := ret[0].([]*url.Url)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SaveMany indicates an expected call of SaveMany
+func (mr *MockRepositoryInterfaceMockRecorder) SaveMany(models interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockRepositoryInterface)(nil).Save), originalUrl, shortUuid)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveMany", reflect.TypeOf((*MockRepositoryInterface)(nil).SaveMany), models)
 }
 
-func (m *MockRepositoryInterface) FindAll(page, limit int) ([]*url.Url, error) {
-	ret := m.ctrl.Call(m, "FindAll", page, limit)
+// SaveWithin mocks base method
+func (m *MockRepositoryInterface) SaveWithin(models []*url.Url, limit url.Limit) ([]*url.Url, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveWithin", models, limit)
 	ret0, _ := ret[0].([]*url.Url)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-func (mr *MockRepositoryInterfaceMockRecorder) FindAll(page, limit interface{}) *gomock.Call {
+// SaveWithin indicates an expected call of SaveWithin
+func (mr *MockRepositoryInterfaceMockRecorder) SaveWithin(models, limit interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAll", reflect.TypeOf((*MockRepositoryInterface)(nil).FindAll), page, limit)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveWithin", reflect.TypeOf((*MockRepositoryInterface)(nil).SaveWithin), models, limit)
+}
+
+// ForEach mocks base method
+func (m *MockRepositoryInterface) ForEach(filter url.Filter, fn func(*url.Url) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ForEach", filter, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ForEach indicates an expected call of ForEach
+func (mr *MockRepositoryInterfaceMockRecorder) ForEach(filter, fn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ForEach", reflect.TypeOf((*MockRepositoryInterface)(nil).ForEach), filter, fn)
+}
+
+// FindPage mocks base method
+func (m *MockRepositoryInterface) FindPage(filter url.Filter, page url.Page) ([]*url.Url, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindPage", filter, page)
+	ret0, _ := ret[0].([]*url.Url)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindPage indicates an expected call of FindPage
+func (mr *MockRepositoryInterfaceMockRecorder) FindPage(filter, page interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindPage", reflect.TypeOf((*MockRepositoryInterface)(nil).FindPage), filter, page)
+}
+
+// Count mocks base method
+func (m *MockRepositoryInterface) Count(filter url.Filter) (uint64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Count", filter)
+	ret0, _ := ret[0].(uint64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Count indicates an expected call of Count
+func (mr *MockRepositoryInterfaceMockRecorder) Count(filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Count", reflect.TypeOf((*MockRepositoryInterface)(nil).Count), filter)
+}
+
+func (m *MockRepositoryInterface) FindAll(page, limit int, filter url.Filter) ([]*url.Url, error) {
+	ret := m.ctrl.Call(m, "FindAll", page, limit, filter)
+	ret0, _ := ret[0].([]*url.Url)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (mr *MockRepositoryInterfaceMockRecorder) FindAll(page, limit, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAll", reflect.TypeOf((*MockRepositoryInterface)(nil).FindAll), page, limit, filter)
 }
 
 func (m *MockRepositoryInterface) Update(originalUrl *url.Url) (*url.Url, error) {
@@ -98,3 +188,75 @@ func (mr *MockRepositoryInterfaceMockRecorder) Update(originalUrl interface{}) *
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockRepositoryInterface)(nil).Update), originalUrl)
 }
+
+// UpdateMetadata mocks base method
+func (m *MockRepositoryInterface) UpdateMetadata(id string, metadata url.Metadata) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateMetadata", id, metadata)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateMetadata indicates an expected call of UpdateMetadata
+func (mr *MockRepositoryInterfaceMockRecorder) UpdateMetadata(id, metadata interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateMetadata", reflect.TypeOf((*MockRepositoryInterface)(nil).UpdateMetadata), id, metadata)
+}
+
+// FindCheckDue mocks base method
+func (m *MockRepositoryInterface) FindCheckDue(checkedBefore time.Time, limit int) ([]*url.Url, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindCheckDue", checkedBefore, limit)
+	ret0, _ := ret[0].([]*url.Url)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindCheckDue indicates an expected call of FindCheckDue
+func (mr *MockRepositoryInterfaceMockRecorder) FindCheckDue(checkedBefore, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindCheckDue", reflect.TypeOf((*MockRepositoryInterface)(nil).FindCheckDue), checkedBefore, limit)
+}
+
+// UpdateHealth mocks base method
+func (m *MockRepositoryInterface) UpdateHealth(id string, health url.Health) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateHealth", id, health)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateHealth indicates an expected call of UpdateHealth
+func (mr *MockRepositoryInterfaceMockRecorder) UpdateHealth(id, health interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateHealth", reflect.TypeOf((*MockRepositoryInterface)(nil).UpdateHealth), id, health)
+}
+
+// IncrementClicks mocks base method
+func (m *MockRepositoryInterface) IncrementClicks(id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IncrementClicks", id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// IncrementClicks indicates an expected call of IncrementClicks
+func (mr *MockRepositoryInterfaceMockRecorder) IncrementClicks(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IncrementClicks", reflect.TypeOf((*MockRepositoryInterface)(nil).IncrementClicks), id)
+}
+
+// CountByOwner mocks base method
+func (m *MockRepositoryInterface) CountByOwner(ownerId string) (url.Counts, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountByOwner", ownerId)
+	ret0, _ := ret[0].(url.Counts)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountByOwner indicates an expected call of CountByOwner
+func (mr *MockRepositoryInterfaceMockRecorder) CountByOwner(ownerId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountByOwner", reflect.TypeOf((*MockRepositoryInterface)(nil).CountByOwner), ownerId)
+}
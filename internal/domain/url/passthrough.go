@@ -0,0 +1,29 @@
+package url
+
+import "errors"
+
+var ErrInvalidQueryMode = errors.New("invalid query mode")
+
+// QueryMode - правило переноса параметров запроса короткой ссылки в адрес перехода
+type QueryMode string
+
+const (
+	// QueryDrop - параметры запроса не переносятся
+	QueryDrop QueryMode = ""
+	// QueryMerge - переносятся параметры, которых нет в адресе ссылки
+	QueryMerge QueryMode = "merge"
+	// QueryOverride - параметры запроса заменяют одноимённые параметры адреса ссылки
+	QueryOverride QueryMode = "override"
+	// QueryAppend - сохраняются значения и из адреса ссылки, и из запроса
+	QueryAppend QueryMode = "append"
+)
+
+// ParseQueryMode проверяет значение, пустая строка означает, что параметры не переносятся
+func ParseQueryMode(value string) (QueryMode, error) {
+	switch m := QueryMode(value); m {
+	case QueryDrop, QueryMerge, QueryOverride, QueryAppend:
+		return m, nil
+	default:
+		return "", ErrInvalidQueryMode
+	}
+}
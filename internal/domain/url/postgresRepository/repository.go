@@ -7,12 +7,26 @@ import (
 	sq "github.com/Masterminds/squirrel"
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"leenwood/yandex-http/config"
 	"leenwood/yandex-http/internal/domain/url"
+	"slices"
+	"strings"
 	"time"
 )
 
+var columns = []string{"id", "original_url", "click_count", "created_date", "domain", "owner_id", "creator_ip", "custom_id", "password_hash", "interstitial", "redirect_type", "query_mode", "forward_path",
+	"utm_source", "utm_medium", "utm_campaign", "utm_term", "utm_content", "has_rules", "split_mode", "title", "notes", "folder_id",
+	"meta_title", "meta_description", "meta_image", "meta_site_name", "meta_favicon",
+	"health_status", "health_latency_ms", "health_final_url", "health_error", "health_checked_date", "resolved_url", "dedup_key"}
+
+// likeEscaper экранирует спецсимволы LIKE в строке поиска
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// saveBatchSize - сколько строк вставляется одним запросом SaveMany
+const saveBatchSize = 500
+
 type Repository struct {
 	db  *pgxpool.Pool
 	sq  sq.StatementBuilderType
@@ -41,7 +55,7 @@ func NewRepository(ctx context.Context, config config.DatabaseConfig) (*Reposito
 
 func (r *Repository) FindById(id string) (*url.Url, error) {
 	query, args, err := r.sq.
-		Select("id", "original_url", "click_count", "created_date").
+		Select(columns...).
 		From("urls").
 		Where(sq.Eq{"id": id}).
 		ToSql()
@@ -49,29 +63,28 @@ func (r *Repository) FindById(id string) (*url.Url, error) {
 		return nil, err
 	}
 
-	model := &url.Url{}
-	row := r.db.QueryRow(r.ctx, query, args...)
-	err = row.Scan(&model.Id, &model.OriginalUrl, &model.ClickCount, &model.CreatedDate)
+	model, err := scanUrl(r.db.QueryRow(r.ctx, query, args...))
 	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, url.ErrNotFound
+		}
 		return nil, err
 	}
 
 	return model, nil
 }
 
-func (r *Repository) FindByUrl(originalUrl string) (*url.Url, error) {
+func (r *Repository) FindByUrl(originalUrl string, domain string) (*url.Url, error) {
 	query, args, err := r.sq.
-		Select("id", "original_url", "click_count", "created_date").
+		Select(columns...).
 		From("urls").
-		Where(sq.Eq{"original_url": originalUrl}).
+		Where(sq.Eq{"original_url": originalUrl, "domain": domain}).
 		ToSql()
 	if err != nil {
 		return nil, err
 	}
 
-	model := &url.Url{}
-	row := r.db.QueryRow(r.ctx, query, args...)
-	err = row.Scan(&model.Id, &model.OriginalUrl, &model.ClickCount, &model.CreatedDate)
+	model, err := scanUrl(r.db.QueryRow(r.ctx, query, args...))
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, nil
@@ -82,47 +95,196 @@ func (r *Repository) FindByUrl(originalUrl string) (*url.Url, error) {
 	return model, nil
 }
 
-func (r *Repository) Save(originalUrl, shortUuid string) (*url.Url, error) {
+func (r *Repository) FindByDedupKey(key string) (*url.Url, error) {
+	query, args, err := r.sq.
+		Select(columns...).
+		From("urls").
+		Where(sq.Eq{"dedup_key": key}).
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	model, err := scanUrl(r.db.QueryRow(r.ctx, query, args...))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	return model, err
+}
+
+func (r *Repository) Save(model *url.Url) (*url.Url, error) {
+	if model == nil {
+		return nil, errors.New("input URL cannot be nil")
+	}
+
 	var err error
-	if shortUuid == "" {
-		shortUuid, err = r.GenerateUuid()
+	if model.Id == "" {
+		model.Id, err = r.GenerateUuid()
 		if err != nil {
 			return nil, err
 		}
 	} else {
-		isExists, err := r.IsIdExists(shortUuid)
+		isExists, err := r.IsIdExists(model.Id)
 		if err != nil {
 			return nil, err
 		}
 		if isExists {
-			return nil, fmt.Errorf("short uuid already exists")
+			return nil, url.ErrIdExists
 		}
 	}
 
-	createdDate := time.Now()
+	model.ClickCount = 0
+	model.CreatedDate = time.Now()
 	query, args, err := r.sq.
 		Insert("urls").
-		Columns("id", "original_url", "click_count", "created_date").
-		Values(shortUuid, originalUrl, 0, createdDate).
+		Columns(columns...).
+		Values(model.Id, model.OriginalUrl, model.ClickCount, model.CreatedDate, model.Domain, model.OwnerId, model.CreatorIp, model.CustomId, model.PasswordHash, model.Interstitial, model.RedirectType, model.QueryMode, model.ForwardPath,
+			model.Utm.Source, model.Utm.Medium, model.Utm.Campaign, model.Utm.Term, model.Utm.Content, model.HasRules, model.SplitMode, model.Title, model.Notes, model.FolderId,
+			model.Metadata.Title, model.Metadata.Description, model.Metadata.Image, model.Metadata.SiteName, model.Metadata.Favicon,
+			model.Health.Status, model.Health.Latency.Milliseconds(), model.Health.FinalUrl, model.Health.Error, model.Health.CheckedDate, model.ResolvedUrl, nullable(model.DedupKey)).
 		ToSql()
 	if err != nil {
 		return nil, err
 	}
 
 	_, err = r.db.Exec(r.ctx, query, args...)
+	if isDedupConflict(err) {
+		return nil, url.ErrUrlExists
+	}
+	if isIdConflict(err) {
+		return nil, url.ErrIdExists
+	}
 	if err != nil {
 		return nil, err
 	}
 
-	model := &url.Url{
-		Id:          shortUuid,
-		OriginalUrl: originalUrl,
-		ClickCount:  0,
-		CreatedDate: createdDate,
-	}
 	return model, nil
 }
 
+// SaveMany сохраняет ссылки одной транзакцией, вставляя их пачками по saveBatchSize строк
+func (r *Repository) SaveMany(models []*url.Url) ([]*url.Url, error) {
+	return r.saveMany(models, url.Limit{})
+}
+
+func (r *Repository) SaveWithin(models []*url.Url, limit url.Limit) ([]*url.Url, error) {
+	return r.saveMany(models, limit)
+}
+
+func (r *Repository) saveMany(models []*url.Url, limit url.Limit) ([]*url.Url, error) {
+	if err := r.assignIds(models); err != nil {
+		return nil, err
+	}
+
+	tx, err := r.db.Begin(r.ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(r.ctx)
+
+	if limit.Enabled() {
+		if err = r.checkLimit(tx, models, limit); err != nil {
+			return nil, err
+		}
+	}
+
+	now := time.Now()
+	for start := 0; start < len(models); start += saveBatchSize {
+		insert := r.sq.Insert("urls").Columns(columns...)
+		for _, model := range models[start:min(start+saveBatchSize, len(models))] {
+			model.ClickCount = 0
+			model.CreatedDate = now
+			insert = insert.Values(model.Id, model.OriginalUrl, model.ClickCount, model.CreatedDate, model.Domain, model.OwnerId, model.CreatorIp, model.CustomId, model.PasswordHash, model.Interstitial, model.RedirectType, model.QueryMode, model.ForwardPath,
+				model.Utm.Source, model.Utm.Medium, model.Utm.Campaign, model.Utm.Term, model.Utm.Content, model.HasRules, model.SplitMode, model.Title, model.Notes, model.FolderId,
+				model.Metadata.Title, model.Metadata.Description, model.Metadata.Image, model.Metadata.SiteName, model.Metadata.Favicon,
+				model.Health.Status, model.Health.Latency.Milliseconds(), model.Health.FinalUrl, model.Health.Error, model.Health.CheckedDate, model.ResolvedUrl, nullable(model.DedupKey))
+		}
+
+		query, args, err := insert.ToSql()
+		if err != nil {
+			return nil, err
+		}
+		if _, err = tx.Exec(r.ctx, query, args...); err != nil {
+			if isDedupConflict(err) {
+				return nil, url.ErrUrlExists
+			}
+			if isIdConflict(err) {
+				return nil, url.ErrIdExists
+			}
+			return nil, err
+		}
+	}
+
+	if err = tx.Commit(r.ctx); err != nil {
+		return nil, err
+	}
+	return models, nil
+}
+
+// checkLimit считает ссылки владельца или адреса под блокировкой до конца транзакции,
+// чтобы параллельные сохранения для них выполнялись по очереди и видели ссылки друг друга
+func (r *Repository) checkLimit(tx pgx.Tx, models []*url.Url, limit url.Limit) error {
+	lockKey := "urls:owner:" + limit.OwnerId
+	conditions := sq.And{sq.Eq{"owner_id": limit.OwnerId}}
+	if limit.OwnerId == "" {
+		lockKey = "urls:ip:" + limit.CreatorIp
+		conditions = append(conditions, sq.Eq{"creator_ip": limit.CreatorIp})
+	}
+	if !limit.Since.IsZero() {
+		conditions = append(conditions, sq.GtOrEq{"created_date": limit.Since})
+	}
+
+	if _, err := tx.Exec(r.ctx, "SELECT pg_advisory_xact_lock(hashtext($1))", lockKey); err != nil {
+		return err
+	}
+
+	query, args, err := r.sq.
+		Select("COUNT(*)", "COUNT(*) FILTER (WHERE custom_id)").
+		From("urls").
+		Where(conditions).
+		ToSql()
+	if err != nil {
+		return err
+	}
+
+	var counts url.Counts
+	if err = tx.QueryRow(r.ctx, query, args...).Scan(&counts.Links, &counts.CustomAliases); err != nil {
+		return err
+	}
+	return limit.Check(counts, models)
+}
+
+// assignIds генерирует недостающие id и проверяет, что заданные id свободны и не повторяются
+func (r *Repository) assignIds(models []*url.Url) error {
+	used := make(map[string]bool, len(models))
+	for _, model := range models {
+		if model.Id == "" {
+			continue
+		}
+		exists, err := r.IsIdExists(model.Id)
+		if err != nil {
+			return err
+		}
+		if exists || used[model.Id] {
+			return fmt.Errorf("%w: %s", url.ErrIdExists, model.Id)
+		}
+		used[model.Id] = true
+	}
+
+	for _, model := range models {
+		for model.Id == "" {
+			id, err := r.GenerateUuid()
+			if err != nil {
+				return err
+			}
+			if !used[id] {
+				model.Id = id
+				used[id] = true
+			}
+		}
+	}
+	return nil
+}
+
 func (r *Repository) GenerateUuid() (string, error) {
 	for {
 		select {
@@ -152,12 +314,14 @@ func (r *Repository) IsIdExists(id string) (bool, error) {
 	return exists, nil
 }
 
-func (r *Repository) FindAll(page, limit int) ([]*url.Url, error) {
+func (r *Repository) FindAll(page, limit int, filter url.Filter) ([]*url.Url, error) {
 	offset := (page - 1) * limit
 
 	query, args, err := r.sq.
-		Select("id", "original_url", "click_count", "created_date").
+		Select(columns...).
 		From("urls").
+		Where(filterConditions(filter)).
+		OrderBy(sortOrder(filter.Sort)...).
 		Limit(uint64(limit)).
 		Offset(uint64(offset)).
 		ToSql()
@@ -173,20 +337,97 @@ func (r *Repository) FindAll(page, limit int) ([]*url.Url, error) {
 
 	var urls []*url.Url
 	for rows.Next() {
-		var u url.Url
-		if err := rows.Scan(&u.Id, &u.OriginalUrl, &u.ClickCount, &u.CreatedDate); err != nil {
+		u, err := scanUrl(rows)
+		if err != nil {
 			return nil, err
 		}
-		urls = append(urls, &u)
+		urls = append(urls, u)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return urls, nil
+}
+
+func (r *Repository) FindPage(filter url.Filter, page url.Page) ([]*url.Url, error) {
+	query, args, err := pageQuery(r.sq.Select(columns...).From("urls").Where(filterConditions(filter)), filter.Sort, page).ToSql()
+	if err != nil {
+		return nil, err
 	}
 
+	rows, err := r.db.Query(r.ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	urls := []*url.Url{}
+	for rows.Next() {
+		u, err := scanUrl(rows)
+		if err != nil {
+			return nil, err
+		}
+		urls = append(urls, u)
+	}
 	if err := rows.Err(); err != nil {
 		return nil, err
 	}
 
+	if page.Backward {
+		slices.Reverse(urls)
+	}
 	return urls, nil
 }
 
+func (r *Repository) Count(filter url.Filter) (uint64, error) {
+	query, args, err := r.sq.
+		Select("COUNT(*)").
+		From("urls").
+		Where(filterConditions(filter)).
+		ToSql()
+	if err != nil {
+		return 0, err
+	}
+
+	var count uint64
+	if err = r.db.QueryRow(r.ctx, query, args...).Scan(&count); err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+func (r *Repository) ForEach(filter url.Filter, fn func(*url.Url) error) error {
+	query, args, err := r.sq.
+		Select(columns...).
+		From("urls").
+		Where(filterConditions(filter)).
+		OrderBy(sortOrder(filter.Sort)...).
+		ToSql()
+	if err != nil {
+		return err
+	}
+
+	rows, err := r.db.Query(r.ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		u, err := scanUrl(rows)
+		if err != nil {
+			return err
+		}
+		if err = fn(u); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
 func (r *Repository) Update(shortUrl *url.Url) (*url.Url, error) {
 	if shortUrl == nil {
 		return nil, errors.New("input URL cannot be nil")
@@ -195,8 +436,26 @@ func (r *Repository) Update(shortUrl *url.Url) (*url.Url, error) {
 	query, args, err := r.sq.
 		Update("urls").
 		Set("original_url", shortUrl.OriginalUrl).
-		Set("click_count", shortUrl.ClickCount).
-		Set("created_date", shortUrl.CreatedDate).
+		Set("resolved_url", shortUrl.ResolvedUrl).
+		Set("dedup_key", nullable(shortUrl.DedupKey)).
+		Set("domain", shortUrl.Domain).
+		Set("owner_id", shortUrl.OwnerId).
+		Set("custom_id", shortUrl.CustomId).
+		Set("password_hash", shortUrl.PasswordHash).
+		Set("interstitial", shortUrl.Interstitial).
+		Set("redirect_type", shortUrl.RedirectType).
+		Set("query_mode", shortUrl.QueryMode).
+		Set("forward_path", shortUrl.ForwardPath).
+		Set("utm_source", shortUrl.Utm.Source).
+		Set("utm_medium", shortUrl.Utm.Medium).
+		Set("utm_campaign", shortUrl.Utm.Campaign).
+		Set("utm_term", shortUrl.Utm.Term).
+		Set("utm_content", shortUrl.Utm.Content).
+		Set("has_rules", shortUrl.HasRules).
+		Set("split_mode", shortUrl.SplitMode).
+		Set("title", shortUrl.Title).
+		Set("notes", shortUrl.Notes).
+		Set("folder_id", shortUrl.FolderId).
 		Where(sq.Eq{"id": shortUrl.Id}).
 		ToSql()
 	if err != nil {
@@ -210,3 +469,225 @@ func (r *Repository) Update(shortUrl *url.Url) (*url.Url, error) {
 
 	return shortUrl, nil
 }
+
+func (r *Repository) UpdateMetadata(id string, metadata url.Metadata) error {
+	query, args, err := r.sq.
+		Update("urls").
+		Set("meta_title", metadata.Title).
+		Set("meta_description", metadata.Description).
+		Set("meta_image", metadata.Image).
+		Set("meta_site_name", metadata.SiteName).
+		Set("meta_favicon", metadata.Favicon).
+		Where(sq.Eq{"id": id}).
+		ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = r.db.Exec(r.ctx, query, args...)
+	return err
+}
+
+func (r *Repository) FindCheckDue(checkedBefore time.Time, limit int) ([]*url.Url, error) {
+	query, args, err := r.sq.
+		Select(columns...).
+		From("urls").
+		Where(sq.Or{sq.Eq{"health_checked_date": nil}, sq.Lt{"health_checked_date": checkedBefore}}).
+		OrderBy("health_checked_date IS NOT NULL", "health_checked_date", "id").
+		Limit(uint64(limit)).
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.db.Query(r.ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var urls []*url.Url
+	for rows.Next() {
+		u, err := scanUrl(rows)
+		if err != nil {
+			return nil, err
+		}
+		urls = append(urls, u)
+	}
+	return urls, rows.Err()
+}
+
+func (r *Repository) UpdateHealth(id string, health url.Health) error {
+	query, args, err := r.sq.
+		Update("urls").
+		Set("health_status", health.Status).
+		Set("health_latency_ms", health.Latency.Milliseconds()).
+		Set("health_final_url", health.FinalUrl).
+		Set("health_error", health.Error).
+		Set("health_checked_date", health.CheckedDate).
+		Where(sq.Eq{"id": id}).
+		ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = r.db.Exec(r.ctx, query, args...)
+	return err
+}
+
+func (r *Repository) IncrementClicks(id string) error {
+	query, args, err := r.sq.
+		Update("urls").
+		Set("click_count", sq.Expr("click_count + 1")).
+		Where(sq.Eq{"id": id}).
+		ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = r.db.Exec(r.ctx, query, args...)
+	return err
+}
+
+func (r *Repository) CountByOwner(ownerId string) (url.Counts, error) {
+	query, args, err := r.sq.
+		Select("COUNT(*)", "COUNT(*) FILTER (WHERE custom_id)").
+		From("urls").
+		Where(sq.Eq{"owner_id": ownerId}).
+		ToSql()
+	if err != nil {
+		return url.Counts{}, err
+	}
+
+	var counts url.Counts
+	err = r.db.QueryRow(r.ctx, query, args...).Scan(&counts.Links, &counts.CustomAliases)
+	if err != nil {
+		return url.Counts{}, err
+	}
+	return counts, nil
+}
+
+// filterConditions возвращает условия отбора по заданным полям фильтра
+func filterConditions(filter url.Filter) sq.And {
+	conditions := sq.And{}
+	for column, value := range map[string]string{
+		"owner_id":     filter.OwnerId,
+		"domain":       filter.Domain,
+		"utm_source":   filter.Utm.Source,
+		"utm_medium":   filter.Utm.Medium,
+		"utm_campaign": filter.Utm.Campaign,
+		"utm_term":     filter.Utm.Term,
+		"utm_content":  filter.Utm.Content,
+	} {
+		if value != "" {
+			conditions = append(conditions, sq.Eq{column: value})
+		}
+	}
+	if filter.CreatedFrom != nil {
+		conditions = append(conditions, sq.GtOrEq{"created_date": *filter.CreatedFrom})
+	}
+	if filter.CreatedTo != nil {
+		conditions = append(conditions, sq.Lt{"created_date": *filter.CreatedTo})
+	}
+	switch filter.Status {
+	case url.StatusPublic:
+		conditions = append(conditions, sq.Eq{"password_hash": ""})
+	case url.StatusProtected:
+		conditions = append(conditions, sq.NotEq{"password_hash": ""})
+	}
+	if filter.MinClicks != nil {
+		conditions = append(conditions, sq.GtOrEq{"click_count": *filter.MinClicks})
+	}
+	if filter.MaxClicks != nil {
+		conditions = append(conditions, sq.LtOrEq{"click_count": *filter.MaxClicks})
+	}
+	if len(filter.FolderIds) > 0 {
+		conditions = append(conditions, sq.Eq{"folder_id": filter.FolderIds})
+	}
+	if filter.Broken {
+		conditions = append(conditions, sq.NotEq{"health_checked_date": nil}, sq.Or{sq.Eq{"health_status": 0}, sq.GtOrEq{"health_status": 400}})
+	}
+	for _, name := range filter.Tags {
+		conditions = append(conditions, sq.Expr("id IN (SELECT url_id FROM url_tags WHERE tag = ?)", name))
+	}
+	if filter.Search != "" {
+		pattern := "%" + likeEscaper.Replace(filter.Search) + "%"
+		conditions = append(conditions, sq.Or{sq.ILike{"original_url": pattern}, sq.ILike{"id": pattern}, sq.ILike{"title": pattern}})
+	}
+	return conditions
+}
+
+// pageQuery ограничивает запрос окном по курсору. Окно перед курсором читается
+// в обратном порядке, чтобы LIMIT отсекал дальние от курсора ссылки.
+func pageQuery(query sq.SelectBuilder, sort url.Sort, page url.Page) sq.SelectBuilder {
+	desc := sort.Desc != page.Backward
+	if page.Cursor != nil {
+		compare := ">"
+		if desc {
+			compare = "<"
+		}
+		var value any = page.Cursor.CreatedDate
+		if sort.Field == url.SortClickCount {
+			value = page.Cursor.ClickCount
+		}
+		query = query.Where(sq.Expr("("+sortColumn(sort.Field)+", id) "+compare+" (?, ?)", value, page.Cursor.Id))
+	}
+	return query.OrderBy(sortOrder(url.Sort{Field: sort.Field, Desc: desc})...).Limit(uint64(page.Limit))
+}
+
+// sortOrder возвращает ORDER BY списка, id делает порядок однозначным
+func sortOrder(sort url.Sort) []string {
+	order := "ASC"
+	if sort.Desc {
+		order = "DESC"
+	}
+	column := sortColumn(sort.Field)
+	return []string{column + " " + order, "id " + order}
+}
+
+func sortColumn(field url.SortField) string {
+	if field == url.SortClickCount {
+		return "click_count"
+	}
+	return "created_date"
+}
+
+func scanUrl(row pgx.Row) (*url.Url, error) {
+	model := &url.Url{}
+	var (
+		latency  int64
+		dedupKey *string
+	)
+	err := row.Scan(&model.Id, &model.OriginalUrl, &model.ClickCount, &model.CreatedDate, &model.Domain, &model.OwnerId, &model.CreatorIp, &model.CustomId, &model.PasswordHash, &model.Interstitial, &model.RedirectType, &model.QueryMode, &model.ForwardPath,
+		&model.Utm.Source, &model.Utm.Medium, &model.Utm.Campaign, &model.Utm.Term, &model.Utm.Content, &model.HasRules, &model.SplitMode, &model.Title, &model.Notes, &model.FolderId,
+		&model.Metadata.Title, &model.Metadata.Description, &model.Metadata.Image, &model.Metadata.SiteName, &model.Metadata.Favicon,
+		&model.Health.Status, &latency, &model.Health.FinalUrl, &model.Health.Error, &model.Health.CheckedDate, &model.ResolvedUrl, &dedupKey)
+	if err != nil {
+		return nil, err
+	}
+	model.Health.Latency = time.Duration(latency) * time.Millisecond
+	if dedupKey != nil {
+		model.DedupKey = *dedupKey
+	}
+	return model, nil
+}
+
+// nullable сохраняет пустую строку как NULL, чтобы уникальный индекс не учитывал такие строки
+func nullable(value string) any {
+	if value == "" {
+		return nil
+	}
+	return value
+}
+
+// isDedupConflict сообщает, что ссылку с таким же ключом повторной выдачи успел сохранить параллельный запрос
+func isDedupConflict(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23505" && pgErr.ConstraintName == "urls_dedup_key_idx"
+}
+
+// isIdConflict сообщает, что ссылку с таким же id успел сохранить параллельный запрос
+func isIdConflict(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23505" && pgErr.ConstraintName == "urls_pkey"
+}
@@ -0,0 +1,58 @@
+package url
+
+import (
+	"errors"
+	"net/http"
+)
+
+var ErrInvalidRedirectType = errors.New("invalid redirect type")
+
+// RedirectType - способ перенаправления на адрес ссылки
+type RedirectType string
+
+const (
+	RedirectMovedPermanently RedirectType = "301"
+	RedirectFound            RedirectType = "302"
+	RedirectTemporary        RedirectType = "307"
+	RedirectPermanent        RedirectType = "308"
+	RedirectMetaRefresh      RedirectType = "meta-refresh"
+	RedirectJavaScript       RedirectType = "javascript"
+	DefaultRedirectType                   = RedirectTemporary
+)
+
+// ParseRedirectType проверяет значение, пустая строка допустима и означает тип по умолчанию
+func ParseRedirectType(value string) (RedirectType, error) {
+	switch t := RedirectType(value); t {
+	case "", RedirectMovedPermanently, RedirectFound, RedirectTemporary, RedirectPermanent,
+		RedirectMetaRefresh, RedirectJavaScript:
+		return t, nil
+	default:
+		return "", ErrInvalidRedirectType
+	}
+}
+
+// StatusCode возвращает HTTP-статус перенаправления, для страниц с переходом - 200
+func (t RedirectType) StatusCode() int {
+	switch t {
+	case RedirectMovedPermanently:
+		return http.StatusMovedPermanently
+	case RedirectFound:
+		return http.StatusFound
+	case RedirectPermanent:
+		return http.StatusPermanentRedirect
+	case RedirectMetaRefresh, RedirectJavaScript:
+		return http.StatusOK
+	default:
+		return http.StatusTemporaryRedirect
+	}
+}
+
+// IsPermanent сообщает, что ответ может кэшироваться клиентами и прокси
+func (t RedirectType) IsPermanent() bool {
+	return t == RedirectMovedPermanently || t == RedirectPermanent
+}
+
+// IsPage сообщает, что переход выполняется HTML-страницей, а не заголовком Location
+func (t RedirectType) IsPage() bool {
+	return t == RedirectMetaRefresh || t == RedirectJavaScript
+}
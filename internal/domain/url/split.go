@@ -0,0 +1,29 @@
+package url
+
+import "errors"
+
+var ErrInvalidSplitMode = errors.New("invalid split mode")
+
+// SplitMode - способ распределения переходов между вариантами эксперимента
+type SplitMode string
+
+const (
+	// SplitNone - у ссылки нет эксперимента
+	SplitNone SplitMode = ""
+	// SplitRandom - вариант выбирается случайно при каждом переходе
+	SplitRandom SplitMode = "random"
+	// SplitCookie - выбранный вариант запоминается в cookie посетителя
+	SplitCookie SplitMode = "cookie"
+	// SplitFingerprint - вариант определяется хэшем IP-адреса и User-Agent посетителя
+	SplitFingerprint SplitMode = "fingerprint"
+)
+
+// ParseSplitMode проверяет способ распределения запущенного эксперимента
+func ParseSplitMode(value string) (SplitMode, error) {
+	switch m := SplitMode(value); m {
+	case SplitRandom, SplitCookie, SplitFingerprint:
+		return m, nil
+	default:
+		return "", ErrInvalidSplitMode
+	}
+}
@@ -1,10 +1,117 @@
 package url
 
-import "time"
+import (
+	"errors"
+	"time"
+)
+
+var (
+	ErrNotFound         = errors.New("URL not found")
+	ErrIdExists         = errors.New("short uuid already exists")
+	ErrInvalidUrl       = errors.New("invalid URL")
+	ErrUrlExists        = errors.New("link to this URL already exists")
+	ErrPasswordRequired = errors.New("URL is password protected")
+	ErrInvalidPassword  = errors.New("invalid password")
+	ErrTooManyAttempts  = errors.New("too many password attempts")
+	// ErrInterstitialRequired - перед переходом нужно показать промежуточную страницу
+	ErrInterstitialRequired = errors.New("URL requires confirmation")
+	// ErrTooManyItems - в запросе массового создания больше ссылок, чем разрешено
+	ErrTooManyItems = errors.New("too many items")
+	// ErrBulkAborted - ссылка не создана, потому что в атомарном запросе не удалось создать другую
+	ErrBulkAborted = errors.New("not created because another item failed")
+	// ErrUnsupportedFormat - неизвестный формат файла выгрузки или загрузки ссылок
+	ErrUnsupportedFormat = errors.New("unsupported file format")
+	ErrInvalidImport     = errors.New("invalid import file")
+	ErrInvalidCursor     = errors.New("invalid cursor")
+	ErrInvalidFilter     = errors.New("invalid list filter")
+	// ErrInvalidDetails - слишком длинные название или заметки ссылки
+	ErrInvalidDetails = errors.New("invalid link details")
+	// ErrSelfLink - адрес назначения ведёт на короткую ссылку этого сервиса, которую нельзя или не разрешено раскрыть
+	ErrSelfLink = errors.New("destination is a short link of this service")
+	// ErrRedirectLoop - цепочка коротких ссылок замыкается или слишком длинная
+	ErrRedirectLoop = errors.New("redirect chain loops or is too long")
+)
 
 type Url struct {
 	Id          string    `db:"id"`
 	OriginalUrl string    `db:"original_url"`
 	ClickCount  uint64    `db:"click_count"`
 	CreatedDate time.Time `db:"created_date"`
+	// ResolvedUrl - конечный адрес, если OriginalUrl сам был короткой ссылкой, переход ведёт сразу на него.
+	// Пустая строка - OriginalUrl никуда не перенаправляет.
+	ResolvedUrl string `db:"resolved_url"`
+	// Domain - хост брендированного домена, пустая строка означает домен по умолчанию
+	Domain string `db:"domain"`
+	// OwnerId - владелец ссылки, пустая строка для анонимных ссылок
+	OwnerId string `db:"owner_id"`
+	// CreatorIp - адрес, с которого создана анонимная ссылка, для ссылок владельцев пустой
+	CreatorIp string `db:"creator_ip"`
+	// CustomId - идентификатор задан пользователем, а не сгенерирован
+	CustomId bool `db:"custom_id"`
+	// PasswordHash - bcrypt-хэш пароля, пустая строка для открытых ссылок
+	PasswordHash string `db:"password_hash"`
+	// Interstitial - всегда показывать промежуточную страницу перед переходом на внешний домен
+	Interstitial bool `db:"interstitial"`
+	// RedirectType - способ перенаправления, пустое значение означает тип по умолчанию из конфигурации
+	RedirectType RedirectType `db:"redirect_type"`
+	// QueryMode - перенос параметров запроса в адрес перехода
+	QueryMode QueryMode `db:"query_mode"`
+	// ForwardPath - продолжение пути после идентификатора дописывается к адресу перехода
+	ForwardPath bool `db:"forward_path"`
+	// Utm - метки кампании, хранятся отдельно от OriginalUrl и добавляются при переходе
+	Utm Utm
+	// HasRules - у ссылки есть правила перенаправления, хранятся отдельно от ссылки
+	HasRules bool `db:"has_rules"`
+	// SplitMode - распределение переходов между вариантами эксперимента, пустое значение - эксперимента нет
+	SplitMode SplitMode `db:"split_mode"`
+	// Title и Notes - название и заметки владельца, при переходе не используются
+	Title string `db:"title"`
+	Notes string `db:"notes"`
+	// FolderId - папка владельца, пустая строка для ссылок вне папок
+	FolderId string `db:"folder_id"`
+	// DedupKey - ключ повторной выдачи ссылки, уникален. Пустая строка - ссылка выдаётся только тому, кто её создал.
+	DedupKey string `db:"dedup_key"`
+	// Metadata - сведения со страницы назначения, загружаются в фоне после создания ссылки
+	Metadata Metadata
+	// Health - результат последней проверки страницы назначения
+	Health Health
+}
+
+// Metadata - заголовок, описание и картинки страницы назначения, пустые поля означают,
+// что сведения ещё не загружены или страница их не указала
+type Metadata struct {
+	Title       string `db:"meta_title"`
+	Description string `db:"meta_description"`
+	Image       string `db:"meta_image"`
+	SiteName    string `db:"meta_site_name"`
+	Favicon     string `db:"meta_favicon"`
+}
+
+// Health - ответ страницы назначения при последней проверке
+type Health struct {
+	// Status - код ответа, ноль если страница не ответила
+	Status  int           `db:"health_status"`
+	Latency time.Duration `db:"health_latency_ms"`
+	// FinalUrl - адрес после всех перенаправлений
+	FinalUrl string `db:"health_final_url"`
+	// Error - причина, по которой страница не ответила
+	Error string `db:"health_error"`
+	// CheckedDate - время проверки, nil если ссылка ещё не проверялась
+	CheckedDate *time.Time `db:"health_checked_date"`
+}
+
+// IsBroken сообщает, что при последней проверке страница не ответила или вернула ошибку
+func (h Health) IsBroken() bool {
+	return h.CheckedDate != nil && (h.Status == 0 || h.Status >= 400)
+}
+
+// IsProtected сообщает, что перед переходом нужно ввести пароль
+func (u *Url) IsProtected() bool {
+	return u.PasswordHash != ""
+}
+
+// Counts - количество ссылок владельца
+type Counts struct {
+	Links         uint64
+	CustomAliases uint64
 }
@@ -0,0 +1,64 @@
+package url
+
+import (
+	"errors"
+	neturl "net/url"
+	"strings"
+)
+
+var ErrInvalidUtm = errors.New("invalid UTM parameter")
+
+// maxUtmLength - максимальная длина значения UTM-метки
+const maxUtmLength = 255
+
+// Utm - метки кампании, которые добавляются к адресу перехода
+type Utm struct {
+	Source   string `db:"utm_source"`
+	Medium   string `db:"utm_medium"`
+	Campaign string `db:"utm_campaign"`
+	Term     string `db:"utm_term"`
+	Content  string `db:"utm_content"`
+}
+
+// NewUtm обрезает пробелы и проверяет длину значений
+func NewUtm(source, medium, campaign, term, content string) (Utm, error) {
+	utm := Utm{
+		Source:   strings.TrimSpace(source),
+		Medium:   strings.TrimSpace(medium),
+		Campaign: strings.TrimSpace(campaign),
+		Term:     strings.TrimSpace(term),
+		Content:  strings.TrimSpace(content),
+	}
+	for _, value := range utm.fields() {
+		if len(value[1]) > maxUtmLength {
+			return Utm{}, ErrInvalidUtm
+		}
+	}
+	return utm, nil
+}
+
+// IsEmpty сообщает, что ни одна метка не задана
+func (u Utm) IsEmpty() bool {
+	return u == Utm{}
+}
+
+// Values возвращает заданные метки в виде параметров запроса
+func (u Utm) Values() neturl.Values {
+	values := neturl.Values{}
+	for _, field := range u.fields() {
+		if field[1] != "" {
+			values.Set(field[0], field[1])
+		}
+	}
+	return values
+}
+
+func (u Utm) fields() [][2]string {
+	return [][2]string{
+		{"utm_source", u.Source},
+		{"utm_medium", u.Medium},
+		{"utm_campaign", u.Campaign},
+		{"utm_term", u.Term},
+		{"utm_content", u.Content},
+	}
+}
@@ -0,0 +1,86 @@
+package geoIp
+
+import (
+	"leenwood/yandex-http/config"
+	"net"
+)
+
+// Location - сведения о местоположении и сети клиента, пустые поля означают, что данных нет
+type Location struct {
+	// Country - код страны ISO 3166-1 alpha-2
+	Country string
+	// Region - код региона ISO 3166-2, например "US-CA"
+	Region          string
+	Asn             uint32
+	AsnOrganization string
+}
+
+type ResolverInterface interface {
+	Lookup(ip net.IP) (Location, error)
+}
+
+// NewResolver открывает базы MaxMind из конфигурации. Базы стран или городов и база ASN
+// задаются отдельно, без настроенных путей возвращаются пустые данные.
+func NewResolver(cfg config.GeoIpConfig) (ResolverInterface, error) {
+	var readers []*reader
+	for _, path := range []string{cfg.DatabasePath, cfg.AsnDatabasePath} {
+		if path == "" {
+			continue
+		}
+		r, err := openReader(path, cfg.ReloadInterval)
+		if err != nil {
+			return nil, err
+		}
+		readers = append(readers, r)
+	}
+
+	if len(readers) == 0 {
+		return nopResolver{}, nil
+	}
+	return &Resolver{readers: readers}, nil
+}
+
+type Resolver struct {
+	readers []*reader
+}
+
+// record объединяет поля баз стран, городов и ASN
+type record struct {
+	Country struct {
+		IsoCode string `maxminddb:"iso_code"`
+	} `maxminddb:"country"`
+	Subdivisions []struct {
+		IsoCode string `maxminddb:"iso_code"`
+	} `maxminddb:"subdivisions"`
+	AutonomousSystemNumber       uint32 `maxminddb:"autonomous_system_number"`
+	AutonomousSystemOrganization string `maxminddb:"autonomous_system_organization"`
+}
+
+func (r *Resolver) Lookup(ip net.IP) (Location, error) {
+	if ip == nil {
+		return Location{}, nil
+	}
+
+	var result record
+	for _, db := range r.readers {
+		if err := db.lookup(ip, &result); err != nil {
+			return Location{}, err
+		}
+	}
+
+	location := Location{
+		Country:         result.Country.IsoCode,
+		Asn:             result.AutonomousSystemNumber,
+		AsnOrganization: result.AutonomousSystemOrganization,
+	}
+	if len(result.Subdivisions) > 0 && result.Subdivisions[0].IsoCode != "" && location.Country != "" {
+		location.Region = location.Country + "-" + result.Subdivisions[0].IsoCode
+	}
+	return location, nil
+}
+
+type nopResolver struct{}
+
+func (nopResolver) Lookup(net.IP) (Location, error) {
+	return Location{}, nil
+}
@@ -0,0 +1,78 @@
+package geoIp
+
+import (
+	"leenwood/yandex-http/config"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestGeoIp(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "GeoIP Test Suite")
+}
+
+var _ = Describe("Resolver", func() {
+	It("should annotate an address with country, region and ASN", func() {
+		resolver, err := NewResolver(config.GeoIpConfig{DatabasePath: "testdata/test.mmdb", ReloadInterval: time.Minute})
+		Expect(err).NotTo(HaveOccurred())
+
+		location, err := resolver.Lookup(net.ParseIP("192.0.2.10"))
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(location).To(Equal(Location{Country: "DE", Region: "DE-BY", Asn: 64500, AsnOrganization: "Example Net"}))
+	})
+
+	It("should return empty data for unknown addresses", func() {
+		resolver, err := NewResolver(config.GeoIpConfig{DatabasePath: "testdata/test.mmdb"})
+		Expect(err).NotTo(HaveOccurred())
+
+		location, err := resolver.Lookup(net.ParseIP("203.0.113.1"))
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(location).To(Equal(Location{}))
+	})
+
+	It("should work without a configured database", func() {
+		resolver, err := NewResolver(config.GeoIpConfig{})
+		Expect(err).NotTo(HaveOccurred())
+
+		location, err := resolver.Lookup(net.ParseIP("192.0.2.10"))
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(location).To(Equal(Location{}))
+	})
+
+	It("should reload the database after the file is replaced", func() {
+		path := filepath.Join(GinkgoT().TempDir(), "geo.mmdb")
+		copyFile("testdata/test.mmdb", path)
+		resolver, err := NewResolver(config.GeoIpConfig{DatabasePath: path})
+		Expect(err).NotTo(HaveOccurred())
+
+		before, err := resolver.Lookup(net.ParseIP("192.0.2.10"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(before.Country).To(Equal("DE"))
+
+		copyFile("testdata/test-updated.mmdb", path)
+		later := time.Now().Add(time.Minute)
+		Expect(os.Chtimes(path, later, later)).To(Succeed())
+
+		after, err := resolver.Lookup(net.ParseIP("192.0.2.10"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(after.Country).To(Equal("AT"))
+	})
+})
+
+func copyFile(from, to string) {
+	data, err := os.ReadFile(from)
+	Expect(err).NotTo(HaveOccurred())
+	// Файл заменяется атомарно, как это делает geoipupdate
+	tmp := to + ".tmp"
+	Expect(os.WriteFile(tmp, data, 0o644)).To(Succeed())
+	Expect(os.Rename(tmp, to)).To(Succeed())
+}
@@ -0,0 +1,46 @@
+package mocks
+
+import (
+	gomock "github.com/golang/mock/gomock"
+	geoIp "leenwood/yandex-http/internal/geoIp"
+	net "net"
+	reflect "reflect"
+)
+
+// MockResolverInterface is a mock of ResolverInterface interface
+type MockResolverInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockResolverInterfaceMockRecorder
+}
+
+// MockResolverInterfaceMockRecorder is the mock recorder for MockResolverInterface
+type MockResolverInterfaceMockRecorder struct {
+	mock *MockResolverInterface
+}
+
+// NewMockResolverInterface creates a new mock instance
+func NewMockResolverInterface(ctrl *gomock.Controller) *MockResolverInterface {
+	mock := &MockResolverInterface{ctrl: ctrl}
+	mock.recorder = &MockResolverInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockResolverInterface) EXPECT() *MockResolverInterfaceMockRecorder {
+	return m.recorder
+}
+
+// Lookup mocks base method
+func (m *MockResolverInterface) Lookup(ip net.IP) (geoIp.Location, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Lookup", ip)
+	ret0, _ := ret[0].(geoIp.Location)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Lookup indicates an expected call of Lookup
+func (mr *MockResolverInterfaceMockRecorder) Lookup(ip interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Lookup", reflect.TypeOf((*MockResolverInterface)(nil).Lookup), ip)
+}
@@ -0,0 +1,74 @@
+package geoIp
+
+import (
+	"net"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/oschwald/maxminddb-golang"
+)
+
+// reader - база MaxMind, которая переоткрывается после замены файла.
+// Файл проверяется не чаще одного раза за interval во время поиска.
+type reader struct {
+	path     string
+	interval time.Duration
+
+	mu      sync.RWMutex
+	db      *maxminddb.Reader
+	modTime time.Time
+	size    int64
+	checked time.Time
+}
+
+func openReader(path string, interval time.Duration) (*reader, error) {
+	r := &reader{path: path, interval: interval}
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if r.db, err = maxminddb.Open(path); err != nil {
+		return nil, err
+	}
+	r.modTime, r.size, r.checked = info.ModTime(), info.Size(), time.Now()
+	return r, nil
+}
+
+func (r *reader) lookup(ip net.IP, result any) error {
+	r.reloadIfChanged()
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.db.Lookup(ip, result)
+}
+
+// reloadIfChanged открывает файл заново, если изменились время изменения или размер.
+// Если новый файл не открывается (например, ещё копируется), используется прежняя база.
+func (r *reader) reloadIfChanged() {
+	r.mu.RLock()
+	due := time.Since(r.checked) >= r.interval
+	r.mu.RUnlock()
+	if !due {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if time.Since(r.checked) < r.interval {
+		return
+	}
+	r.checked = time.Now()
+
+	info, err := os.Stat(r.path)
+	if err != nil || (info.ModTime().Equal(r.modTime) && info.Size() == r.size) {
+		return
+	}
+	db, err := maxminddb.Open(r.path)
+	if err != nil {
+		return
+	}
+
+	_ = r.db.Close()
+	r.db, r.modTime, r.size = db, info.ModTime(), info.Size()
+}
@@ -0,0 +1,104 @@
+package handlers
+
+import (
+	"context"
+	"leenwood/yandex-http/config"
+	"leenwood/yandex-http/internal/domain/url"
+	"leenwood/yandex-http/internal/handler/middleware"
+	"leenwood/yandex-http/internal/usecase"
+	"leenwood/yandex-http/internal/usecase/dto"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxImportBodySize - наибольший размер загружаемого файла ссылок
+const maxImportBodySize = 64 << 20
+
+// exportContentTypes - тип ответа для каждого формата выгрузки
+var exportContentTypes = map[string]string{
+	usecase.ExportCsv:   "text/csv; charset=utf-8",
+	usecase.ExportJsonl: "application/x-ndjson",
+}
+
+type AdminHandler struct {
+	us        usecase.TransferUseCaseInterface
+	adminKeys []string
+}
+
+func NewAdminHandler(ctx context.Context, cfg config.Config, urls url.RepositoryInterface) (*AdminHandler, error) {
+	us, err := usecase.NewTransferUseCase(ctx, cfg, urls)
+	if err != nil {
+		return nil, err
+	}
+
+	return &AdminHandler{us: us, adminKeys: cfg.Auth.AdminKeys}, nil
+}
+
+func (ah *AdminHandler) RegisterRoutes(router *gin.Engine) {
+	admin := router.Group("/api/v1/admin", middleware.Admin(ah.adminKeys))
+	admin.GET("/urls/export", ah.ExportUrls)
+	admin.POST("/urls/import", ah.ImportUrls)
+}
+
+// ExportUrls отдаёт ссылки файлом CSV или JSON Lines, строки пишутся по мере чтения из базы
+func (ah *AdminHandler) ExportUrls(c *gin.Context) {
+	var request dto.ExportUrlsRequest
+	if err := c.ShouldBindQuery(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request data: " + err.Error()})
+		return
+	}
+	request.Format = strings.ToLower(request.Format)
+	if request.Format == "" {
+		request.Format = usecase.ExportCsv
+	}
+	contentType, ok := exportContentTypes[request.Format]
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "format must be csv or jsonl"})
+		return
+	}
+	request.BaseUrl = middleware.BaseUrl(c)
+
+	c.Header("Content-Type", contentType)
+	c.Header("Content-Disposition", `attachment; filename="links.`+request.Format+`"`)
+	if err := ah.us.ExportUrls(request, c.Writer); err != nil {
+		// После начала выгрузки статус уже отправлен, ответ просто обрывается
+		if !c.Writer.Written() {
+			c.Writer.Header().Del("Content-Type")
+			c.Writer.Header().Del("Content-Disposition")
+			c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+			return
+		}
+		_ = c.Error(err)
+	}
+}
+
+// ImportUrls загружает ссылки из CSV в теле запроса и возвращает отчёт
+func (ah *AdminHandler) ImportUrls(c *gin.Context) {
+	mapping, err := usecase.ParseColumnMapping(c.Query("map"))
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	request := dto.ImportUrlsRequest{
+		OwnerId:     c.Query("owner_id"),
+		Mapping:     mapping,
+		OnDuplicate: c.Query("on_duplicate"),
+		DryRun:      c.Query("dry_run") == "true" || c.Query("dry_run") == "1",
+	}
+
+	body := http.MaxBytesReader(c.Writer, c.Request.Body, maxImportBodySize)
+	response, err := ah.us.ImportUrls(request, body)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	status := http.StatusOK
+	if response.Aborted {
+		status = http.StatusConflict
+	}
+	c.JSON(status, response)
+}
@@ -0,0 +1,57 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"leenwood/yandex-http/internal/usecase"
+	"leenwood/yandex-http/internal/usecase/dto"
+	"mime"
+	"net/http"
+)
+
+// maxBulkBodySize - наибольший размер тела запроса массового создания
+const maxBulkBodySize = 32 << 20
+
+// ndjsonContentTypes - типы тела, в которых каждая строка содержит отдельный элемент
+var ndjsonContentTypes = map[string]bool{
+	"application/x-ndjson":    true,
+	"application/ndjson":      true,
+	"application/jsonl":       true,
+	"application/x-jsonlines": true,
+}
+
+// decodeBulkItems читает элементы из JSON-массива или из NDJSON, не более usecase.MaxBulkItems
+func decodeBulkItems(r *http.Request) ([]dto.BulkCreateItem, error) {
+	decoder := json.NewDecoder(http.MaxBytesReader(nil, r.Body, maxBulkBodySize))
+	decoder.DisallowUnknownFields()
+
+	contentType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if !ndjsonContentTypes[contentType] {
+		var items []dto.BulkCreateItem
+		if err := decoder.Decode(&items); err != nil {
+			return nil, err
+		}
+		if len(items) > usecase.MaxBulkItems {
+			return nil, fmt.Errorf("at most %d items are allowed", usecase.MaxBulkItems)
+		}
+		return items, nil
+	}
+
+	var items []dto.BulkCreateItem
+	for {
+		var item dto.BulkCreateItem
+		err := decoder.Decode(&item)
+		if errors.Is(err, io.EOF) {
+			return items, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("item %d: %w", len(items), err)
+		}
+		if len(items) == usecase.MaxBulkItems {
+			return nil, fmt.Errorf("at most %d items are allowed", usecase.MaxBulkItems)
+		}
+		items = append(items, item)
+	}
+}
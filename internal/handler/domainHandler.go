@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"context"
+	"leenwood/yandex-http/config"
+	"leenwood/yandex-http/internal/handler/middleware"
+	"leenwood/yandex-http/internal/usecase"
+	"leenwood/yandex-http/internal/usecase/dto"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type DomainHandler struct {
+	us        usecase.DomainUseCaseInterface
+	adminKeys []string
+}
+
+func NewDomainHandler(ctx context.Context, cfg config.Config) (*DomainHandler, error) {
+	us, err := usecase.NewDomainUseCase(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return &DomainHandler{us: us, adminKeys: cfg.Auth.AdminKeys}, nil
+}
+
+func (dh *DomainHandler) RegisterRoutes(router *gin.Engine) {
+	// Домены общие для всего сервиса, поэтому управляет ими только администратор
+	group := router.Group("/api/v1/domains", middleware.Admin(dh.adminKeys))
+	group.POST("", dh.RegisterDomain)
+	group.GET("", dh.GetDomainList)
+	group.POST("/:hostname/verify", dh.VerifyDomain)
+}
+
+func (dh *DomainHandler) RegisterDomain(c *gin.Context) {
+	var req dto.RegisterDomainRequest
+	if err := c.ShouldBind(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request data: " + err.Error()})
+		return
+	}
+
+	response, err := dh.us.RegisterDomain(req)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": "failed to register domain: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, response)
+}
+
+func (dh *DomainHandler) GetDomainList(c *gin.Context) {
+	data, err := dh.us.GetDomainList()
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": data})
+}
+
+func (dh *DomainHandler) VerifyDomain(c *gin.Context) {
+	request := dto.VerifyDomainRequest{Hostname: c.Param("hostname")}
+
+	response, err := dh.us.VerifyDomain(request)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": "failed to verify domain: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
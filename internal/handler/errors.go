@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"errors"
+	"leenwood/yandex-http/internal/domain/customDomain"
+	"leenwood/yandex-http/internal/domain/experiment"
+	"leenwood/yandex-http/internal/domain/folder"
+	"leenwood/yandex-http/internal/domain/quota"
+	"leenwood/yandex-http/internal/domain/rule"
+	"leenwood/yandex-http/internal/domain/tag"
+	"leenwood/yandex-http/internal/domain/url"
+	"leenwood/yandex-http/internal/qrCode"
+	"net/http"
+)
+
+// errorStatus сопоставляет доменные ошибки с HTTP-статусами
+func errorStatus(err error) int {
+	switch {
+	// Несозданный элемент атомарного запроса может оборачивать ошибку другого элемента
+	case errors.Is(err, url.ErrBulkAborted):
+		return http.StatusFailedDependency
+	case errors.Is(err, url.ErrNotFound),
+		errors.Is(err, customDomain.ErrNotFound),
+		errors.Is(err, experiment.ErrNotFound),
+		errors.Is(err, experiment.ErrVariantNotFound),
+		errors.Is(err, tag.ErrNotFound),
+		errors.Is(err, folder.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, customDomain.ErrInvalidHostname),
+		errors.Is(err, customDomain.ErrNotVerified),
+		errors.Is(err, url.ErrInvalidPassword),
+		errors.Is(err, url.ErrInvalidRedirectType),
+		errors.Is(err, url.ErrInvalidQueryMode),
+		errors.Is(err, url.ErrInvalidUtm),
+		errors.Is(err, rule.ErrInvalidRule),
+		errors.Is(err, experiment.ErrInvalid),
+		errors.Is(err, url.ErrInvalidSplitMode),
+		errors.Is(err, qrCode.ErrInvalidOptions),
+		errors.Is(err, url.ErrInvalidUrl),
+		errors.Is(err, url.ErrTooManyItems),
+		errors.Is(err, url.ErrUnsupportedFormat),
+		errors.Is(err, url.ErrInvalidImport),
+		errors.Is(err, url.ErrInvalidCursor),
+		errors.Is(err, url.ErrInvalidFilter),
+		errors.Is(err, url.ErrInvalidDetails),
+		errors.Is(err, url.ErrInvalidDedup),
+		errors.Is(err, url.ErrSelfLink),
+		errors.Is(err, tag.ErrInvalidTag),
+		errors.Is(err, folder.ErrInvalidName),
+		errors.Is(err, folder.ErrInvalidMove),
+		errors.Is(err, folder.ErrTooDeep):
+		return http.StatusBadRequest
+	case errors.Is(err, customDomain.ErrExists),
+		errors.Is(err, experiment.ErrExists),
+		errors.Is(err, url.ErrIdExists),
+		errors.Is(err, url.ErrUrlExists),
+		errors.Is(err, folder.ErrExists):
+		return http.StatusConflict
+	case errors.Is(err, customDomain.ErrVerificationFailed),
+		errors.Is(err, url.ErrRedirectLoop):
+		return http.StatusUnprocessableEntity
+	case errors.Is(err, quota.ErrExceeded),
+		errors.Is(err, quota.ErrCustomIdNotAllowed):
+		return http.StatusForbidden
+	case errors.Is(err, url.ErrTooManyAttempts):
+		return http.StatusTooManyRequests
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,59 @@
+package handlers
+
+import (
+	"fmt"
+	"leenwood/yandex-http/internal/domain/url"
+	"leenwood/yandex-http/internal/usecase"
+	"leenwood/yandex-http/internal/usecase/dto"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestHandler(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Handler Test Suite")
+}
+
+// failingUrlUseCase отклоняет создание ссылки ошибкой err, остальные методы не вызываются
+type failingUrlUseCase struct {
+	usecase.UrlUseCaseInterface
+	err error
+}
+
+func (us failingUrlUseCase) CreateShortUrl(dto.CreateShortUrlUseCaseRequest) (dto.CreateShortUrlResponse, error) {
+	return dto.CreateShortUrlResponse{}, us.err
+}
+
+var _ = Describe("Error status", func() {
+	create := func(err error) *httptest.ResponseRecorder {
+		gin.SetMode(gin.TestMode)
+		router := gin.New()
+		uh := &UrlHandler{us: failingUrlUseCase{err: err}}
+		router.POST("/", uh.CreateShortUrl)
+
+		recorder := httptest.NewRecorder()
+		request := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("url=https://localhost/abc"))
+		request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		router.ServeHTTP(recorder, request)
+		return recorder
+	}
+
+	It("should reject a destination that is a short link of this service", func() {
+		response := create(fmt.Errorf("%w: https://localhost/ is not a short link", url.ErrSelfLink))
+
+		Expect(response.Code).To(Equal(http.StatusBadRequest))
+		Expect(response.Body.String()).To(ContainSubstring(url.ErrSelfLink.Error()))
+	})
+
+	It("should reject a destination that leads into a redirect loop", func() {
+		response := create(url.ErrRedirectLoop)
+
+		Expect(response.Code).To(Equal(http.StatusUnprocessableEntity))
+	})
+})
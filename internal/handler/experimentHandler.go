@@ -0,0 +1,117 @@
+package handlers
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"leenwood/yandex-http/config"
+	"leenwood/yandex-http/internal/domain/url"
+	"leenwood/yandex-http/internal/handler/middleware"
+	"leenwood/yandex-http/internal/usecase"
+	"leenwood/yandex-http/internal/usecase/dto"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	variantCookiePrefix = "variant_"
+	variantCookieTTL    = 30 * 24 * time.Hour
+)
+
+type ExperimentHandler struct {
+	us usecase.ExperimentUseCaseInterface
+}
+
+func NewExperimentHandler(ctx context.Context, cfg config.Config, urls url.RepositoryInterface) (*ExperimentHandler, error) {
+	us, err := usecase.NewExperimentUseCase(ctx, cfg, urls)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ExperimentHandler{us: us}, nil
+}
+
+func (eh *ExperimentHandler) RegisterRoutes(router *gin.Engine) {
+	router.GET("/api/v1/urls/:id/experiment", eh.GetExperiment)
+	router.POST("/api/v1/urls/:id/experiment", eh.CreateExperiment)
+	router.PATCH("/api/v1/urls/:id/experiment", eh.UpdateExperiment)
+	router.POST("/api/v1/urls/:id/experiment/promote", eh.PromoteVariant)
+}
+
+func (eh *ExperimentHandler) GetExperiment(c *gin.Context) {
+	request := dto.ExperimentRequest{Id: c.Param("id"), OwnerId: middleware.OwnerId(c)}
+	if request.OwnerId == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "API key is required"})
+		return
+	}
+
+	response, err := eh.us.GetExperiment(request)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (eh *ExperimentHandler) CreateExperiment(c *gin.Context) {
+	var request dto.CreateExperimentRequest
+	if !bindOwnedRequest(c, &request, &request.Id, &request.OwnerId) {
+		return
+	}
+
+	response, err := eh.us.CreateExperiment(request)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, response)
+}
+
+func (eh *ExperimentHandler) UpdateExperiment(c *gin.Context) {
+	var request dto.UpdateExperimentRequest
+	if !bindOwnedRequest(c, &request, &request.Id, &request.OwnerId) {
+		return
+	}
+
+	response, err := eh.us.UpdateExperiment(request)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// PromoteVariant завершает эксперимент, делая адрес варианта адресом ссылки
+func (eh *ExperimentHandler) PromoteVariant(c *gin.Context) {
+	var request dto.PromoteVariantRequest
+	if !bindOwnedRequest(c, &request, &request.Id, &request.OwnerId) {
+		return
+	}
+
+	response, err := eh.us.PromoteVariant(request)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func variantCookieName(id string) string {
+	sum := sha256.Sum256([]byte(id))
+	return variantCookiePrefix + hex.EncodeToString(sum[:8])
+}
+
+// setVariantCookie запоминает вариант эксперимента, выданный посетителю
+func setVariantCookie(c *gin.Context, id, variant string) {
+	secure := c.Request.TLS != nil || strings.HasPrefix(middleware.BaseUrl(c), "https://")
+
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(variantCookieName(id), variant, int(variantCookieTTL.Seconds()), "/", "", secure, true)
+}
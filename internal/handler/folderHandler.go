@@ -0,0 +1,118 @@
+package handlers
+
+import (
+	"context"
+	"leenwood/yandex-http/config"
+	"leenwood/yandex-http/internal/domain/url"
+	"leenwood/yandex-http/internal/handler/middleware"
+	"leenwood/yandex-http/internal/usecase"
+	"leenwood/yandex-http/internal/usecase/dto"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type FolderHandler struct {
+	us usecase.FolderUseCaseInterface
+}
+
+func NewFolderHandler(ctx context.Context, cfg config.Config, urls url.RepositoryInterface) (*FolderHandler, error) {
+	us, err := usecase.NewFolderUseCase(ctx, cfg, urls)
+	if err != nil {
+		return nil, err
+	}
+
+	return &FolderHandler{us: us}, nil
+}
+
+func (fh *FolderHandler) RegisterRoutes(router *gin.Engine) {
+	group := router.Group("/api/v1/folders")
+	group.POST("", fh.CreateFolder)
+	group.GET("", fh.GetFolders)
+	group.PATCH("/:id", fh.UpdateFolder)
+	group.DELETE("/:id", fh.DeleteFolder)
+	router.PUT("/api/v1/urls/:id/folder", fh.MoveUrl)
+}
+
+func (fh *FolderHandler) CreateFolder(c *gin.Context) {
+	var request dto.CreateFolderRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request data: " + err.Error()})
+		return
+	}
+	request.OwnerId = middleware.OwnerId(c)
+	if request.OwnerId == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "API key is required"})
+		return
+	}
+
+	response, err := fh.us.CreateFolder(request)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, response)
+}
+
+func (fh *FolderHandler) GetFolders(c *gin.Context) {
+	request := dto.FoldersRequest{OwnerId: middleware.OwnerId(c)}
+	if request.OwnerId == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "API key is required"})
+		return
+	}
+
+	response, err := fh.us.GetFolders(request)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// UpdateFolder переименовывает папку или перемещает её в другую папку
+func (fh *FolderHandler) UpdateFolder(c *gin.Context) {
+	var request dto.UpdateFolderRequest
+	if !bindOwnedRequest(c, &request, &request.Id, &request.OwnerId) {
+		return
+	}
+
+	response, err := fh.us.UpdateFolder(request)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (fh *FolderHandler) DeleteFolder(c *gin.Context) {
+	request := dto.FolderRequest{Id: c.Param("id"), OwnerId: middleware.OwnerId(c)}
+	if request.OwnerId == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "API key is required"})
+		return
+	}
+
+	if err := fh.us.DeleteFolder(request); err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (fh *FolderHandler) MoveUrl(c *gin.Context) {
+	var request dto.MoveUrlRequest
+	if !bindOwnedRequest(c, &request, &request.Id, &request.OwnerId) {
+		return
+	}
+
+	response, err := fh.us.MoveUrl(request)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
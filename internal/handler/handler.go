@@ -2,14 +2,93 @@ package handlers
 
 import (
 	"context"
+	"fmt"
 	"github.com/gin-gonic/gin"
 	"leenwood/yandex-http/config"
 	"leenwood/yandex-http/internal/handler/middleware"
+	"leenwood/yandex-http/internal/rateLimit"
+	"leenwood/yandex-http/internal/rateLimit/memoryStore"
+	"leenwood/yandex-http/internal/rateLimit/postgresStore"
+	"leenwood/yandex-http/internal/usecase"
+	"net/http"
 )
 
 func InitializationHandlers(ctx context.Context, cfg config.Config) (*gin.Engine, error) {
+	// Создаем хранилище счётчиков ограничения частоты запросов
+	limiter, err := newRateLimitStore(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	// Создаем общий репозиторий ссылок: все сценарии изменяют ссылки через один кэш,
+	// чтобы изменение сразу сбрасывало запись, по которой выполняются перенаправления
+	urls, urlCache, err := usecase.NewUrlRepository(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+
 	// Создаем UrlHandler
-	urlHandler, err := NewUrlHandler(ctx, cfg)
+	urlHandler, err := NewUrlHandler(ctx, cfg, limiter, urls, urlCache)
+	if err != nil {
+		return nil, err
+	}
+
+	// Создаем DomainHandler
+	domainHandler, err := NewDomainHandler(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	// Создаем QuotaHandler
+	quotaHandler, err := NewQuotaHandler(ctx, cfg, urls)
+	if err != nil {
+		return nil, err
+	}
+
+	// Создаем RuleHandler
+	ruleHandler, err := NewRuleHandler(ctx, cfg, urls)
+	if err != nil {
+		return nil, err
+	}
+
+	// Создаем ExperimentHandler
+	experimentHandler, err := NewExperimentHandler(ctx, cfg, urls)
+	if err != nil {
+		return nil, err
+	}
+
+	// Создаем StatsHandler
+	statsHandler, err := NewStatsHandler(ctx, cfg, urls)
+	if err != nil {
+		return nil, err
+	}
+
+	// Создаем QrHandler
+	qrHandler, err := NewQrHandler(ctx, cfg, urls)
+	if err != nil {
+		return nil, err
+	}
+
+	// Создаем AdminHandler
+	adminHandler, err := NewAdminHandler(ctx, cfg, urls)
+	if err != nil {
+		return nil, err
+	}
+
+	// Создаем TagHandler
+	tagHandler, err := NewTagHandler(ctx, cfg, urls)
+	if err != nil {
+		return nil, err
+	}
+
+	// Создаем FolderHandler
+	folderHandler, err := NewFolderHandler(ctx, cfg, urls)
+	if err != nil {
+		return nil, err
+	}
+
+	// Создаем HealthHandler
+	healthHandler, err := NewHealthHandler(ctx, cfg, urls)
 	if err != nil {
 		return nil, err
 	}
@@ -17,11 +96,60 @@ func InitializationHandlers(ctx context.Context, cfg config.Config) (*gin.Engine
 	// Создаем новый роутер Gin
 	router := gin.New()
 
+	// Доверяем X-Forwarded-* только настроенным прокси
+	if err := router.SetTrustedProxies(cfg.App.TrustedProxies); err != nil {
+		return nil, err
+	}
+	forwarded, err := middleware.ForwardedBaseUrl(cfg.App.TrustedProxies)
+	if err != nil {
+		return nil, err
+	}
+
 	// Применяем middleware
 	router.Use(middleware.GinMiddleware())
+	router.Use(forwarded)
+	router.Use(middleware.Owner(cfg.Auth.ApiKeys))
 
 	// Регистрируем маршруты из UrlHandler
 	urlHandler.RegisterRoutes(router)
+	domainHandler.RegisterRoutes(router)
+	quotaHandler.RegisterRoutes(router)
+	ruleHandler.RegisterRoutes(router)
+	experimentHandler.RegisterRoutes(router)
+	statsHandler.RegisterRoutes(router)
+	qrHandler.RegisterRoutes(router)
+	adminHandler.RegisterRoutes(router)
+	tagHandler.RegisterRoutes(router)
+	folderHandler.RegisterRoutes(router)
+	healthHandler.RegisterRoutes(router)
 
 	return router, nil
 }
+
+func newRateLimitStore(ctx context.Context, cfg config.Config) (rateLimit.StoreInterface, error) {
+	switch cfg.RateLimit.Store {
+	case "memory":
+		return memoryStore.NewStore(), nil
+	case "postgres":
+		return postgresStore.NewStore(ctx, cfg.Database)
+	default:
+		return nil, fmt.Errorf("unknown rate limit store %q", cfg.RateLimit.Store)
+	}
+}
+
+// bindOwnedRequest разбирает JSON-тело и заполняет id ссылки и владельца.
+// При ошибке ответ уже отправлен и возвращается false.
+func bindOwnedRequest(c *gin.Context, request any, id, ownerId *string) bool {
+	if err := c.ShouldBindJSON(request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request data: " + err.Error()})
+		return false
+	}
+
+	*id = c.Param("id")
+	*ownerId = middleware.OwnerId(c)
+	if *ownerId == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "API key is required"})
+		return false
+	}
+	return true
+}
@@ -0,0 +1,56 @@
+package handlers
+
+import (
+	"context"
+	"leenwood/yandex-http/config"
+	"leenwood/yandex-http/internal/domain/url"
+	"leenwood/yandex-http/internal/handler/middleware"
+	"leenwood/yandex-http/internal/usecase"
+	"leenwood/yandex-http/internal/usecase/dto"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type HealthHandler struct {
+	us usecase.HealthUseCaseInterface
+}
+
+func NewHealthHandler(ctx context.Context, cfg config.Config, urls url.RepositoryInterface) (*HealthHandler, error) {
+	us, err := usecase.NewHealthUseCase(ctx, cfg, urls)
+	if err != nil {
+		return nil, err
+	}
+
+	return &HealthHandler{us: us}, nil
+}
+
+func (hh *HealthHandler) RegisterRoutes(router *gin.Engine) {
+	router.GET("/api/v1/reports/broken-links", hh.GetBrokenLinks)
+}
+
+// GetBrokenLinks возвращает ссылки, страницы назначения которых при последней проверке не ответили или вернули ошибку
+func (hh *HealthHandler) GetBrokenLinks(c *gin.Context) {
+	var request dto.BrokenLinksRequest
+	if err := c.ShouldBindQuery(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request data: " + err.Error()})
+		return
+	}
+
+	request.OwnerId = middleware.OwnerId(c)
+	if request.OwnerId == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "API key is required"})
+		return
+	}
+	if request.Limit <= 0 || request.Limit > 100 {
+		request.Limit = 100
+	}
+
+	response, err := hh.us.GetBrokenLinks(request)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
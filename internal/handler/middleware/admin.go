@@ -0,0 +1,30 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AdminKeyHeader - заголовок с ключом администратора
+const AdminKeyHeader = "X-Admin-Key"
+
+// Admin пропускает только запросы с одним из ключей администратора
+func Admin(adminKeys []string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if len(adminKeys) == 0 {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "admin API is disabled"})
+			return
+		}
+
+		key := c.GetHeader(AdminKeyHeader)
+		for _, adminKey := range adminKeys {
+			if subtle.ConstantTimeCompare([]byte(key), []byte(adminKey)) == 1 {
+				c.Next()
+				return
+			}
+		}
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "admin key is required"})
+	}
+}
@@ -0,0 +1,98 @@
+package middleware
+
+import (
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+const baseUrlKey = "baseUrl"
+
+// ForwardedBaseUrl восстанавливает внешний адрес сервиса из заголовков
+// X-Forwarded-Proto, X-Forwarded-Host и X-Forwarded-Prefix. Заголовки учитываются
+// только для запросов от доверенных прокси.
+func ForwardedBaseUrl(trustedProxies []string) (gin.HandlerFunc, error) {
+	networks, err := parseNetworks(trustedProxies)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(c *gin.Context) {
+		if isTrusted(networks, c.RemoteIP()) {
+			if baseUrl := forwardedBaseUrl(c); baseUrl != "" {
+				c.Set(baseUrlKey, baseUrl)
+			}
+		}
+		c.Next()
+	}, nil
+}
+
+// BaseUrl возвращает адрес, восстановленный ForwardedBaseUrl, или пустую строку
+func BaseUrl(c *gin.Context) string {
+	return c.GetString(baseUrlKey)
+}
+
+func forwardedBaseUrl(c *gin.Context) string {
+	host := firstValue(c.GetHeader("X-Forwarded-Host"))
+	if host == "" || strings.ContainsAny(host, "/\\@ ") {
+		return ""
+	}
+
+	proto := strings.ToLower(firstValue(c.GetHeader("X-Forwarded-Proto")))
+	switch proto {
+	case "http", "https":
+	case "":
+		proto = "http"
+		if c.Request.TLS != nil {
+			proto = "https"
+		}
+	default:
+		return ""
+	}
+
+	prefix := strings.Trim(firstValue(c.GetHeader("X-Forwarded-Prefix")), "/")
+	if prefix != "" {
+		prefix = "/" + prefix
+	}
+
+	return fmt.Sprintf("%s://%s%s", proto, host, prefix)
+}
+
+func firstValue(header string) string {
+	value, _, _ := strings.Cut(header, ",")
+	return strings.TrimSpace(value)
+}
+
+func parseNetworks(proxies []string) ([]*net.IPNet, error) {
+	var networks []*net.IPNet
+	for _, proxy := range proxies {
+		if !strings.Contains(proxy, "/") {
+			if strings.Contains(proxy, ":") {
+				proxy += "/128"
+			} else {
+				proxy += "/32"
+			}
+		}
+		_, network, err := net.ParseCIDR(proxy)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy %q: %w", proxy, err)
+		}
+		networks = append(networks, network)
+	}
+	return networks, nil
+}
+
+func isTrusted(networks []*net.IPNet, remoteIP string) bool {
+	ip := net.ParseIP(remoteIP)
+	if ip == nil {
+		return false
+	}
+	for _, network := range networks {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
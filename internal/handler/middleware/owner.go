@@ -0,0 +1,35 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+const ownerIdKey = "ownerId"
+
+// Owner определяет владельца запроса по API-ключу.
+// Запросы без ключа считаются анонимными, с неизвестным ключом - отклоняются.
+func Owner(apiKeys map[string]string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		apiKey := c.GetHeader(ApiKeyHeader)
+		if apiKey == "" {
+			c.Next()
+			return
+		}
+
+		ownerId, ok := apiKeys[apiKey]
+		if !ok {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "invalid API key"})
+			return
+		}
+
+		c.Set(ownerIdKey, ownerId)
+		c.Next()
+	}
+}
+
+// OwnerId возвращает владельца, определённого Owner, или пустую строку
+func OwnerId(c *gin.Context) string {
+	return c.GetString(ownerIdKey)
+}
@@ -0,0 +1,61 @@
+package middleware
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"leenwood/yandex-http/internal/rateLimit"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ApiKeyHeader - заголовок с API-ключом клиента
+const ApiKeyHeader = "X-API-Key"
+
+// RateLimit ограничивает частоту запросов клиента в пределах scope.
+// Клиент определяется по API-ключу, а при его отсутствии - по IP-адресу.
+// При недоступности хранилища запрос пропускается.
+func RateLimit(store rateLimit.StoreInterface, scope string, limit rateLimit.Limit) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !limit.Enabled() {
+			c.Next()
+			return
+		}
+
+		result, err := store.Take(clientKey(c, scope), limit)
+		if err != nil {
+			fmt.Printf("Rate limit store error - %s\r\n", err)
+			c.Next()
+			return
+		}
+
+		c.Header("RateLimit-Limit", strconv.Itoa(result.Limit))
+		c.Header("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+		c.Header("RateLimit-Reset", strconv.Itoa(seconds(result.ResetAfter)))
+
+		if !result.Allowed {
+			c.Header("Retry-After", strconv.Itoa(seconds(result.RetryAfter)))
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{"error": "rate limit exceeded"})
+			return
+		}
+
+		c.Next()
+	}
+}
+
+func clientKey(c *gin.Context, scope string) string {
+	if apiKey := c.GetHeader(ApiKeyHeader); apiKey != "" {
+		// Сам ключ в хранилище счётчиков не попадает
+		sum := sha256.Sum256([]byte(apiKey))
+		return scope + ":key:" + hex.EncodeToString(sum[:])
+	}
+	return scope + ":ip:" + c.ClientIP()
+}
+
+func seconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
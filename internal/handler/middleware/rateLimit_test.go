@@ -0,0 +1,91 @@
+package middleware
+
+import (
+	"errors"
+	"leenwood/yandex-http/internal/rateLimit"
+	"leenwood/yandex-http/internal/rateLimit/memoryStore"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestMiddleware(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Middleware Test Suite")
+}
+
+// failingStore - хранилище счётчиков, которое недоступно
+type failingStore struct{}
+
+func (failingStore) Take(string, rateLimit.Limit) (rateLimit.Result, error) {
+	return rateLimit.Result{}, errors.New("connection refused")
+}
+
+func (failingStore) Refund(string, rateLimit.Limit) error {
+	return errors.New("connection refused")
+}
+
+var _ = Describe("RateLimit", func() {
+	var router *gin.Engine
+
+	serve := func(setup func(*http.Request)) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		request := httptest.NewRequest(http.MethodGet, "/", nil)
+		request.RemoteAddr = "192.0.2.1:1234"
+		if setup != nil {
+			setup(request)
+		}
+		router.ServeHTTP(recorder, request)
+		return recorder
+	}
+
+	withStore := func(store rateLimit.StoreInterface, limit rateLimit.Limit) {
+		gin.SetMode(gin.TestMode)
+		router = gin.New()
+		router.GET("/", RateLimit(store, "list", limit), func(c *gin.Context) {
+			c.Status(http.StatusOK)
+		})
+	}
+
+	It("should report the remaining requests and reject the client over the limit", func() {
+		withStore(memoryStore.NewStore(), rateLimit.Limit{PerMinute: 1, Burst: 2})
+
+		first := serve(nil)
+		Expect(first.Code).To(Equal(http.StatusOK))
+		Expect(first.Header().Get("RateLimit-Limit")).To(Equal("2"))
+		Expect(first.Header().Get("RateLimit-Remaining")).To(Equal("1"))
+
+		Expect(serve(nil).Code).To(Equal(http.StatusOK))
+
+		rejected := serve(nil)
+		Expect(rejected.Code).To(Equal(http.StatusTooManyRequests))
+		Expect(rejected.Header().Get("Retry-After")).To(Equal("60"))
+	})
+
+	It("should count clients with an API key apart from their address", func() {
+		withStore(memoryStore.NewStore(), rateLimit.Limit{PerMinute: 1, Burst: 1})
+
+		Expect(serve(nil).Code).To(Equal(http.StatusOK))
+		withKey := serve(func(request *http.Request) { request.Header.Set(ApiKeyHeader, "secret") })
+		Expect(withKey.Code).To(Equal(http.StatusOK))
+		Expect(serve(nil).Code).To(Equal(http.StatusTooManyRequests))
+	})
+
+	It("should let requests through when the store is unavailable", func() {
+		withStore(failingStore{}, rateLimit.Limit{PerMinute: 1})
+
+		Expect(serve(nil).Code).To(Equal(http.StatusOK))
+	})
+
+	It("should not limit requests when the limit is disabled", func() {
+		withStore(failingStore{}, rateLimit.Limit{})
+
+		response := serve(nil)
+		Expect(response.Code).To(Equal(http.StatusOK))
+		Expect(response.Header().Get("RateLimit-Limit")).To(BeEmpty())
+	})
+})
@@ -0,0 +1,42 @@
+package handlers
+
+import (
+	"html/template"
+	"leenwood/yandex-http/internal/usecase/dto"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+var previewTemplate = template.Must(template.New("preview").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<meta name="robots" content="noindex">
+<title>Link preview</title>
+</head>
+<body>
+<h1>{{.ShortUrl}}</h1>
+<dl>
+<dt>Destination</dt><dd>{{.Destination}}</dd>
+<dt>Created</dt><dd>{{.CreatedDate.Format "2006-01-02 15:04"}}</dd>
+<dt>Clicks</dt><dd>{{.CountClick}}</dd>
+<dt>Safety</dt><dd>{{.Safety}}</dd>
+</dl>
+<p><a href="{{.ShortUrl}}?confirm=1" rel="noreferrer">Continue to the destination</a></p>
+</body>
+</html>
+`))
+
+// renderPreview отдаёт страницу предпросмотра или JSON, если клиент его запросил
+func renderPreview(c *gin.Context, preview dto.UrlPreviewResponse) {
+	c.Header("Cache-Control", "no-store")
+	if c.NegotiateFormat(gin.MIMEHTML, gin.MIMEJSON) == gin.MIMEJSON {
+		c.JSON(http.StatusOK, preview)
+		return
+	}
+
+	c.Status(http.StatusOK)
+	c.Header("Content-Type", "text/html; charset=utf-8")
+	_ = previewTemplate.Execute(c.Writer, preview)
+}
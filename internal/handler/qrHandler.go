@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"context"
+	"leenwood/yandex-http/config"
+	"leenwood/yandex-http/internal/domain/url"
+	"leenwood/yandex-http/internal/handler/middleware"
+	"leenwood/yandex-http/internal/usecase"
+	"leenwood/yandex-http/internal/usecase/dto"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+type QrHandler struct {
+	us usecase.QrUseCaseInterface
+}
+
+func NewQrHandler(ctx context.Context, cfg config.Config, urls url.RepositoryInterface) (*QrHandler, error) {
+	us, err := usecase.NewQrUseCase(ctx, cfg, urls)
+	if err != nil {
+		return nil, err
+	}
+
+	return &QrHandler{us: us}, nil
+}
+
+func (qh *QrHandler) RegisterRoutes(router *gin.Engine) {
+	router.GET("/api/v1/urls/:id/qr", qh.GetQr)
+}
+
+// GetQr отдаёт QR-код короткой ссылки в PNG или SVG
+func (qh *QrHandler) GetQr(c *gin.Context) {
+	var request dto.UrlQrRequest
+	if err := c.ShouldBindQuery(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request data: " + err.Error()})
+		return
+	}
+
+	request.Id = c.Param("id")
+	request.BaseUrl = middleware.BaseUrl(c)
+	request.OwnerId = middleware.OwnerId(c)
+	if request.OwnerId == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "API key is required"})
+		return
+	}
+
+	response, err := qh.us.GetQr(request)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	// Изображение доступно только владельцу по API-ключу, поэтому кэшируется только клиентом
+	c.Header("Cache-Control", "private, max-age=86400")
+	c.Header("ETag", response.ETag)
+	if etagMatches(c.GetHeader("If-None-Match"), response.ETag) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+
+	c.Data(http.StatusOK, response.ContentType, response.Body)
+}
+
+// etagMatches проверяет заголовок If-None-Match со списком тегов, слабые теги сравниваются как сильные
+func etagMatches(header, etag string) bool {
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimPrefix(strings.TrimSpace(tag), "W/")
+		if tag == "*" || tag == etag {
+			return true
+		}
+	}
+	return false
+}
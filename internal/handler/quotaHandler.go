@@ -0,0 +1,46 @@
+package handlers
+
+import (
+	"context"
+	"leenwood/yandex-http/config"
+	"leenwood/yandex-http/internal/domain/url"
+	"leenwood/yandex-http/internal/handler/middleware"
+	"leenwood/yandex-http/internal/usecase"
+	"leenwood/yandex-http/internal/usecase/dto"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type QuotaHandler struct {
+	us usecase.QuotaUseCaseInterface
+}
+
+func NewQuotaHandler(ctx context.Context, cfg config.Config, urls url.RepositoryInterface) (*QuotaHandler, error) {
+	us, err := usecase.NewQuotaUseCase(ctx, cfg, urls)
+	if err != nil {
+		return nil, err
+	}
+
+	return &QuotaHandler{us: us}, nil
+}
+
+func (qh *QuotaHandler) RegisterRoutes(router *gin.Engine) {
+	router.GET("/api/v1/usage", qh.GetUsage)
+}
+
+func (qh *QuotaHandler) GetUsage(c *gin.Context) {
+	request := dto.UsageRequest{OwnerId: middleware.OwnerId(c)}
+	if request.OwnerId == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "API key is required"})
+		return
+	}
+
+	response, err := qh.us.GetUsage(request)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
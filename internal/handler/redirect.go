@@ -0,0 +1,52 @@
+package handlers
+
+import (
+	"fmt"
+	"html/template"
+	"leenwood/yandex-http/internal/domain/url"
+	"leenwood/yandex-http/internal/usecase/dto"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+var redirectPageTemplate = template.Must(template.New("redirect").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<meta name="robots" content="noindex">
+{{if .Meta}}<meta http-equiv="refresh" content="0; url={{.Url}}">{{end}}
+<title>Redirecting</title>
+</head>
+<body>
+<p>Redirecting to <a href="{{.Url}}">{{.Url}}</a></p>
+{{if not .Meta}}<script>window.location.replace({{.Url}});</script>{{end}}
+</body>
+</html>
+`))
+
+// writeRedirect выполняет перенаправление выбранным для ссылки способом.
+// Постоянные перенаправления разрешено кэшировать, остальные - нет, чтобы каждый переход учитывался.
+// Переходы по вариантам эксперимента и закрытые перенаправления не кэшируются,
+// так как адрес зависит от посетителя или доступен не всем.
+func writeRedirect(c *gin.Context, response dto.UrlClickResponse, permanentMaxAge time.Duration) {
+	redirectType := url.RedirectType(response.RedirectType)
+
+	if redirectType.IsPermanent() && response.Variant == "" && !response.Private {
+		c.Header("Cache-Control", fmt.Sprintf("public, max-age=%d", int(permanentMaxAge.Seconds())))
+	} else {
+		c.Header("Cache-Control", "private, no-store, max-age=0")
+	}
+
+	if !redirectType.IsPage() {
+		c.Redirect(redirectType.StatusCode(), response.Url)
+		return
+	}
+
+	c.Status(redirectType.StatusCode())
+	c.Header("Content-Type", "text/html; charset=utf-8")
+	_ = redirectPageTemplate.Execute(c.Writer, gin.H{
+		"Url":  response.Url,
+		"Meta": redirectType == url.RedirectMetaRefresh,
+	})
+}
@@ -0,0 +1,41 @@
+package handlers
+
+import (
+	"leenwood/yandex-http/internal/domain/url"
+	"leenwood/yandex-http/internal/usecase/dto"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Redirect caching", func() {
+	redirect := func(response dto.UrlClickResponse) string {
+		recorder := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(recorder)
+		c.Request = httptest.NewRequest(http.MethodGet, "/abc", nil)
+		writeRedirect(c, response, time.Hour)
+		return recorder.Header().Get("Cache-Control")
+	}
+
+	It("should let shared caches keep a permanent redirect", func() {
+		cacheControl := redirect(dto.UrlClickResponse{Url: "https://example.com", RedirectType: string(url.RedirectMovedPermanently)})
+
+		Expect(cacheControl).To(Equal("public, max-age=3600"))
+	})
+
+	It("should not cache a permanent redirect that is private", func() {
+		cacheControl := redirect(dto.UrlClickResponse{Url: "https://example.com", RedirectType: string(url.RedirectMovedPermanently), Private: true})
+
+		Expect(cacheControl).To(Equal("private, no-store, max-age=0"))
+	})
+
+	It("should not cache a temporary redirect", func() {
+		cacheControl := redirect(dto.UrlClickResponse{Url: "https://example.com", RedirectType: string(url.RedirectFound)})
+
+		Expect(cacheControl).To(Equal("private, no-store, max-age=0"))
+	})
+})
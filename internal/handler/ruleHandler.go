@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"context"
+	"leenwood/yandex-http/config"
+	"leenwood/yandex-http/internal/domain/url"
+	"leenwood/yandex-http/internal/handler/middleware"
+	"leenwood/yandex-http/internal/usecase"
+	"leenwood/yandex-http/internal/usecase/dto"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type RuleHandler struct {
+	us usecase.RuleUseCaseInterface
+}
+
+func NewRuleHandler(ctx context.Context, cfg config.Config, urls url.RepositoryInterface) (*RuleHandler, error) {
+	us, err := usecase.NewRuleUseCase(ctx, cfg, urls)
+	if err != nil {
+		return nil, err
+	}
+
+	return &RuleHandler{us: us}, nil
+}
+
+func (rh *RuleHandler) RegisterRoutes(router *gin.Engine) {
+	router.GET("/api/v1/urls/:id/rules", rh.GetRules)
+	router.PUT("/api/v1/urls/:id/rules", rh.ReplaceRules)
+}
+
+func (rh *RuleHandler) GetRules(c *gin.Context) {
+	request := dto.UrlRulesRequest{Id: c.Param("id"), OwnerId: middleware.OwnerId(c)}
+	if request.OwnerId == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "API key is required"})
+		return
+	}
+
+	response, err := rh.us.GetRules(request)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// ReplaceRules заменяет список правил ссылки, пустой список удаляет все правила
+func (rh *RuleHandler) ReplaceRules(c *gin.Context) {
+	var request dto.ReplaceUrlRulesRequest
+	if !bindOwnedRequest(c, &request, &request.Id, &request.OwnerId) {
+		return
+	}
+
+	response, err := rh.us.ReplaceRules(request)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
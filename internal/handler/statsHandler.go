@@ -0,0 +1,102 @@
+package handlers
+
+import (
+	"context"
+	"leenwood/yandex-http/config"
+	"leenwood/yandex-http/internal/domain/url"
+	"leenwood/yandex-http/internal/handler/middleware"
+	"leenwood/yandex-http/internal/usecase"
+	"leenwood/yandex-http/internal/usecase/dto"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type StatsHandler struct {
+	us usecase.StatsUseCaseInterface
+}
+
+func NewStatsHandler(ctx context.Context, cfg config.Config, urls url.RepositoryInterface) (*StatsHandler, error) {
+	us, err := usecase.NewStatsUseCase(ctx, cfg, urls)
+	if err != nil {
+		return nil, err
+	}
+
+	return &StatsHandler{us: us}, nil
+}
+
+func (sh *StatsHandler) RegisterRoutes(router *gin.Engine) {
+	router.GET("/api/v1/stats", sh.GetStats)
+	router.GET("/api/v1/urls/:id/stats", sh.GetUrlStats)
+	router.GET("/api/v1/folders/:id/stats", sh.GetFolderStats)
+}
+
+// GetStats возвращает статистику по всем ссылкам владельца, в том числе по меткам кампании
+func (sh *StatsHandler) GetStats(c *gin.Context) {
+	var request dto.StatsRequest
+	if err := c.ShouldBindQuery(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request data: " + err.Error()})
+		return
+	}
+
+	request.OwnerId = middleware.OwnerId(c)
+	if request.OwnerId == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "API key is required"})
+		return
+	}
+
+	response, err := sh.us.GetStats(request)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (sh *StatsHandler) GetUrlStats(c *gin.Context) {
+	var request dto.UrlStatsRequest
+	if err := c.ShouldBindQuery(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request data: " + err.Error()})
+		return
+	}
+
+	request.Id = c.Param("id")
+	request.OwnerId = middleware.OwnerId(c)
+	if request.OwnerId == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "API key is required"})
+		return
+	}
+
+	response, err := sh.us.GetUrlStats(request)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// GetFolderStats возвращает статистику по ссылкам папки вместе с вложенными папками
+func (sh *StatsHandler) GetFolderStats(c *gin.Context) {
+	var request dto.FolderStatsRequest
+	if err := c.ShouldBindQuery(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request data: " + err.Error()})
+		return
+	}
+
+	request.Id = c.Param("id")
+	request.OwnerId = middleware.OwnerId(c)
+	if request.OwnerId == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "API key is required"})
+		return
+	}
+
+	response, err := sh.us.GetFolderStats(request)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
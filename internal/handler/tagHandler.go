@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"context"
+	"leenwood/yandex-http/config"
+	"leenwood/yandex-http/internal/domain/url"
+	"leenwood/yandex-http/internal/handler/middleware"
+	"leenwood/yandex-http/internal/usecase"
+	"leenwood/yandex-http/internal/usecase/dto"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type TagHandler struct {
+	us usecase.TagUseCaseInterface
+}
+
+func NewTagHandler(ctx context.Context, cfg config.Config, urls url.RepositoryInterface) (*TagHandler, error) {
+	us, err := usecase.NewTagUseCase(ctx, cfg, urls)
+	if err != nil {
+		return nil, err
+	}
+
+	return &TagHandler{us: us}, nil
+}
+
+func (th *TagHandler) RegisterRoutes(router *gin.Engine) {
+	router.PATCH("/api/v1/urls/:id", th.UpdateUrlDetails)
+	router.GET("/api/v1/tags", th.GetTags)
+	// Метки могут содержать "/", поэтому имена передаются в теле, а не в пути
+	router.POST("/api/v1/tags/rename", th.RenameTag)
+}
+
+// UpdateUrlDetails меняет название, заметки и метки ссылки, незаданные поля остаются прежними
+func (th *TagHandler) UpdateUrlDetails(c *gin.Context) {
+	var request dto.UpdateUrlDetailsRequest
+	if !bindOwnedRequest(c, &request, &request.Id, &request.OwnerId) {
+		return
+	}
+
+	response, err := th.us.UpdateUrlDetails(request)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (th *TagHandler) GetTags(c *gin.Context) {
+	request := dto.TagsRequest{OwnerId: middleware.OwnerId(c)}
+	if request.OwnerId == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "API key is required"})
+		return
+	}
+
+	response, err := th.us.GetTags(request)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (th *TagHandler) RenameTag(c *gin.Context) {
+	var request dto.RenameTagRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request data: " + err.Error()})
+		return
+	}
+	request.OwnerId = middleware.OwnerId(c)
+	if request.OwnerId == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "API key is required"})
+		return
+	}
+
+	response, err := th.us.RenameTag(request)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,103 @@
+package handlers
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"html/template"
+	"leenwood/yandex-http/config"
+	"leenwood/yandex-http/internal/handler/middleware"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const unlockCookiePrefix = "unlock_"
+
+var passwordFormTemplate = template.Must(template.New("password").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<meta name="robots" content="noindex">
+<title>Password required</title>
+</head>
+<body>
+<form method="post">
+<p>This link is password protected.</p>
+<input type="password" name="password" autofocus required>
+<button type="submit">Continue</button>
+{{if .Error}}<p>{{.Error}}</p>{{end}}
+</form>
+</body>
+</html>
+`))
+
+// unlockSigner выдаёт и проверяет подписанные cookie доступа к защищённым ссылкам.
+// Имя cookie выводится из id ссылки, значение содержит время истечения и HMAC от id и этого времени.
+type unlockSigner struct {
+	secret []byte
+	ttl    time.Duration
+}
+
+func newUnlockSigner(cfg config.AuthConfig) (unlockSigner, error) {
+	secret := []byte(cfg.CookieSecret)
+	if len(secret) == 0 {
+		// Без заданного ключа cookie действуют до перезапуска сервиса
+		secret = make([]byte, 32)
+		if _, err := rand.Read(secret); err != nil {
+			return unlockSigner{}, err
+		}
+	}
+	return unlockSigner{secret: secret, ttl: cfg.UnlockTTL}, nil
+}
+
+func (s unlockSigner) cookieName(id string) string {
+	sum := sha256.Sum256([]byte(id))
+	return unlockCookiePrefix + hex.EncodeToString(sum[:8])
+}
+
+func (s unlockSigner) sign(id string, expires int64) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(id + "|" + strconv.FormatInt(expires, 10)))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// setCookie выдаёт клиенту доступ к ссылке id на время ttl
+func (s unlockSigner) setCookie(c *gin.Context, id string) {
+	expires := time.Now().Add(s.ttl).Unix()
+	value := strconv.FormatInt(expires, 10) + "." + s.sign(id, expires)
+	secure := c.Request.TLS != nil || strings.HasPrefix(middleware.BaseUrl(c), "https://")
+
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(s.cookieName(id), value, int(s.ttl.Seconds()), "/", "", secure, true)
+}
+
+// verify сообщает, что запрос содержит действующую cookie доступа к ссылке id
+func (s unlockSigner) verify(c *gin.Context, id string) bool {
+	value, err := c.Cookie(s.cookieName(id))
+	if err != nil {
+		return false
+	}
+
+	rawExpires, signature, ok := strings.Cut(value, ".")
+	if !ok {
+		return false
+	}
+	expires, err := strconv.ParseInt(rawExpires, 10, 64)
+	if err != nil || time.Now().Unix() > expires {
+		return false
+	}
+
+	return hmac.Equal([]byte(signature), []byte(s.sign(id, expires)))
+}
+
+func renderPasswordForm(c *gin.Context, status int, message string) {
+	c.Header("Cache-Control", "no-store")
+	c.Status(status)
+	c.Header("Content-Type", "text/html; charset=utf-8")
+	_ = passwordFormTemplate.Execute(c.Writer, gin.H{"Error": message})
+}
@@ -2,33 +2,80 @@ package handlers
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"leenwood/yandex-http/config"
+	"leenwood/yandex-http/internal/domain/click"
+	"leenwood/yandex-http/internal/domain/url"
+	"leenwood/yandex-http/internal/domain/url/cachedRepository"
+	"leenwood/yandex-http/internal/handler/middleware"
+	"leenwood/yandex-http/internal/rateLimit"
 	"leenwood/yandex-http/internal/usecase"
 	"leenwood/yandex-http/internal/usecase/dto"
+	"net"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
 type UrlHandler struct {
-	us usecase.UrlUseCaseInterface
+	us     usecase.UrlUseCaseInterface
+	limits urlRateLimits
+	unlock unlockSigner
+	// permanentMaxAge - время кэширования постоянных перенаправлений
+	permanentMaxAge time.Duration
+	adminKeys       []string
 }
 
-func NewUrlHandler(ctx context.Context, cfg config.Config) (*UrlHandler, error) {
-	us, err := usecase.NewUrlUseCase(ctx, cfg)
+// reservedQueryParams - параметры сервиса, которые не переносятся в адрес перехода
+var reservedQueryParams = []string{"preview", "confirm"}
+
+type urlRateLimits struct {
+	create   gin.HandlerFunc
+	list     gin.HandlerFunc
+	redirect gin.HandlerFunc
+}
+
+func NewUrlHandler(ctx context.Context, cfg config.Config, limiter rateLimit.StoreInterface, urls url.RepositoryInterface, urlCache *cachedRepository.Cache) (*UrlHandler, error) {
+	us, err := usecase.NewUrlUseCase(ctx, cfg, limiter, urls, urlCache)
+	if err != nil {
+		return nil, err
+	}
+
+	unlock, err := newUnlockSigner(cfg.Auth)
 	if err != nil {
 		return nil, err
 	}
 
-	return &UrlHandler{us: us}, nil
+	limits := urlRateLimits{
+		create:   middleware.RateLimit(limiter, "create", rateLimit.NewLimit(cfg.RateLimit.Create)),
+		list:     middleware.RateLimit(limiter, "list", rateLimit.NewLimit(cfg.RateLimit.List)),
+		redirect: middleware.RateLimit(limiter, "redirect", rateLimit.NewLimit(cfg.RateLimit.Redirect)),
+	}
+
+	return &UrlHandler{
+		us:              us,
+		limits:          limits,
+		unlock:          unlock,
+		permanentMaxAge: cfg.App.PermanentRedirectMaxAge,
+		adminKeys:       cfg.Auth.AdminKeys,
+	}, nil
 }
 
 func (uh *UrlHandler) RegisterRoutes(router *gin.Engine) {
-	router.POST("/", uh.CreateShortUrl)
-	router.GET("/:id", uh.RedirectToRouteById)
+	router.POST("/", uh.limits.create, uh.CreateShortUrl)
+	router.GET("/:id", uh.limits.redirect, uh.RedirectToRouteById)
+	router.POST("/:id", uh.limits.redirect, uh.UnlockRouteById)
+	// Продолжение пути после идентификатора передаётся ссылкам с переносом пути
+	router.GET("/:id/*path", uh.limits.redirect, uh.RedirectToRouteById)
+	router.POST("/:id/*path", uh.limits.redirect, uh.UnlockRouteById)
 	router.GET("/healthz", uh.CheckHealthz)
-	router.GET("/list", uh.GetUrlsInfo)
+	router.GET("/list", uh.limits.list, uh.GetUrlsInfo)
+	router.POST("/api/v1/urls/bulk", uh.limits.create, uh.CreateShortUrls)
+	router.GET("/api/v1/admin/cache", middleware.Admin(uh.adminKeys), uh.GetCacheStats)
 }
 func (uh *UrlHandler) CreateShortUrl(c *gin.Context) {
 	var req dto.CreateShortUrlRequest
@@ -43,15 +90,17 @@ func (uh *UrlHandler) CreateShortUrl(c *gin.Context) {
 	)
 
 	// Обработка в зависимости от наличия ID
+	baseUrl := middleware.BaseUrl(c)
+	ownerId := middleware.OwnerId(c)
 	if req.Id != "" {
-		response, err = uh.handleCustomIdRequest(req)
+		response, err = uh.handleCustomIdRequest(req, ownerId, baseUrl, c.ClientIP())
 	} else {
-		response, err = uh.handleDefaultRequest(req)
+		response, err = uh.handleDefaultRequest(req, ownerId, baseUrl, c.ClientIP())
 	}
 
 	// Проверка на ошибки
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create short URL: " + err.Error()})
+		c.JSON(errorStatus(err), gin.H{"error": "failed to create short URL: " + err.Error()})
 		return
 	}
 
@@ -60,22 +109,88 @@ func (uh *UrlHandler) CreateShortUrl(c *gin.Context) {
 }
 
 // Обработка запроса с пользовательским ID
-func (uh *UrlHandler) handleCustomIdRequest(req dto.CreateShortUrlRequest) (interface{}, error) {
+func (uh *UrlHandler) handleCustomIdRequest(req dto.CreateShortUrlRequest, ownerId, baseUrl, clientIp string) (interface{}, error) {
 	request := dto.CreateShortUrlWithCustomIdRequest{
-		Url: req.Url,
-		Id:  req.Id,
+		Url:          req.Url,
+		Id:           req.Id,
+		Domain:       req.Domain,
+		Password:     req.Password,
+		Interstitial: req.Interstitial,
+		RedirectType: req.RedirectType,
+		QueryMode:    req.QueryMode,
+		ForwardPath:  req.ForwardPath,
+		Utm:          req.Utm,
+		Dedup:        req.Dedup,
+		OwnerId:      ownerId,
+		BaseUrl:      baseUrl,
+		ClientIp:     clientIp,
 	}
 	return uh.us.CreateShortUrlWithCustomId(request)
 }
 
 // Обработка запроса без пользовательского ID
-func (uh *UrlHandler) handleDefaultRequest(req dto.CreateShortUrlRequest) (interface{}, error) {
+func (uh *UrlHandler) handleDefaultRequest(req dto.CreateShortUrlRequest, ownerId, baseUrl, clientIp string) (interface{}, error) {
 	request := dto.CreateShortUrlUseCaseRequest{
-		Url: req.Url,
+		Url:          req.Url,
+		Domain:       req.Domain,
+		Password:     req.Password,
+		Interstitial: req.Interstitial,
+		RedirectType: req.RedirectType,
+		QueryMode:    req.QueryMode,
+		ForwardPath:  req.ForwardPath,
+		Utm:          req.Utm,
+		Dedup:        req.Dedup,
+		OwnerId:      ownerId,
+		BaseUrl:      baseUrl,
+		ClientIp:     clientIp,
 	}
 	return uh.us.CreateShortUrl(request)
 }
 
+// CreateShortUrls создаёт ссылки из JSON-массива или NDJSON и возвращает результат каждого элемента.
+// С параметром atomic=true при любой ошибке не создаётся ни одна ссылка.
+func (uh *UrlHandler) CreateShortUrls(c *gin.Context) {
+	request := dto.BulkCreateRequest{
+		OwnerId: middleware.OwnerId(c),
+		BaseUrl: middleware.BaseUrl(c),
+		Atomic:  c.Query("atomic") == "true" || c.Query("atomic") == "1",
+	}
+	if request.OwnerId == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "API key is required"})
+		return
+	}
+
+	items, err := decodeBulkItems(c.Request)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request data: " + err.Error()})
+		return
+	}
+	request.Items = items
+
+	response, err := uh.us.CreateShortUrls(request)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	for i := range response.Results {
+		response.Results[i].Status = http.StatusCreated
+		if !response.Results[i].Created {
+			response.Results[i].Status = http.StatusOK
+		}
+		if err := response.Results[i].Err; err != nil {
+			response.Results[i].Status = errorStatus(err)
+			response.Results[i].Error = err.Error()
+		}
+	}
+
+	status := http.StatusOK
+	if request.Atomic && response.Failed > 0 {
+		status = http.StatusUnprocessableEntity
+	}
+	c.JSON(status, response)
+}
+
 func (uh *UrlHandler) GetUrlsInfo(c *gin.Context) {
 	var request dto.PaginationRequest
 
@@ -85,35 +200,154 @@ func (uh *UrlHandler) GetUrlsInfo(c *gin.Context) {
 	}
 
 	// Устанавливаем значения по умолчанию
-	if request.Limit == 0 || request.Limit > 100 {
+	if request.Limit <= 0 || request.Limit > 100 {
 		request.Limit = 100 // Значение по умолчанию для Limit
 	}
-	if request.Page == 0 {
-		request.Page = 1 // Значение по умолчанию для Page
-	}
+	request.BaseUrl = middleware.BaseUrl(c)
+	request.OwnerId = middleware.OwnerId(c)
 
-	data, err := uh.us.GetUrlList(request)
+	response, err := uh.us.GetUrlList(request)
 
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
 	}
 
-	// Логика обработки запроса (пример)
-	c.JSON(http.StatusOK, gin.H{"data": data})
+	response.Next, response.Prev = listLinks(c, response)
+	var links []string
+	if response.Next != "" {
+		links = append(links, fmt.Sprintf(`<%s>; rel="next"`, response.Next))
+	}
+	if response.Prev != "" {
+		links = append(links, fmt.Sprintf(`<%s>; rel="prev"`, response.Prev))
+	}
+	if len(links) > 0 {
+		c.Header("Link", strings.Join(links, ", "))
+	}
+
+	c.JSON(http.StatusOK, response)
+}
 
+// listLinks строит адреса соседних окон списка из адреса текущего запроса
+func listLinks(c *gin.Context, response dto.UrlListResponse) (next, prev string) {
+	link := func(cursor string, page int) string {
+		query := c.Request.URL.Query()
+		query.Del("cursor")
+		query.Del("page")
+		if cursor != "" {
+			query.Set("cursor", cursor)
+		} else {
+			query.Set("page", strconv.Itoa(page))
+		}
+		return middleware.BaseUrl(c) + c.Request.URL.Path + "?" + query.Encode()
+	}
+
+	switch {
+	case response.NextCursor != "":
+		next = link(response.NextCursor, 0)
+	case response.NextPage > 0:
+		next = link("", response.NextPage)
+	}
+	switch {
+	case response.PrevCursor != "":
+		prev = link(response.PrevCursor, 0)
+	case response.PrevPage > 0:
+		prev = link("", response.PrevPage)
+	}
+	return next, prev
 }
 
 func (uh *UrlHandler) RedirectToRouteById(c *gin.Context) {
+	// "/:id+" и "?preview=1" показывают страницу предпросмотра вместо перехода
+	id, preview := strings.CutSuffix(c.Param("id"), "+")
+	if preview || c.Query("preview") == "1" {
+		uh.previewById(c, id)
+		return
+	}
+
 	var request dto.UrlClickRequest
-	request.Id = c.Param("id")
+	request.Id = id
+	request.Host = requestHost(c.Request)
+	request.Unlocked = uh.unlock.verify(c, request.Id)
+	request.Confirmed = c.Query("confirm") == "1"
+	request.Path = c.Param("path")
+	request.Query, request.Source = forwardedQuery(c.Request)
+	request.UserAgent = c.Request.UserAgent()
+	request.AcceptLanguage = c.GetHeader("Accept-Language")
+	request.ClientIp = c.ClientIP()
+	request.Variant, _ = c.Cookie(variantCookieName(id))
 
-	redirectUrl, err := uh.us.ClickUrl(request)
+	response, err := uh.us.ClickUrl(request)
+	if errors.Is(err, url.ErrPasswordRequired) {
+		renderPasswordForm(c, http.StatusOK, "")
+		return
+	}
+	if errors.Is(err, url.ErrInterstitialRequired) {
+		uh.previewById(c, id)
+		return
+	}
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	if response.StickyVariant {
+		setVariantCookie(c, id, response.Variant)
+	}
+	writeRedirect(c, response, uh.permanentMaxAge)
+}
+
+func (uh *UrlHandler) previewById(c *gin.Context, id string) {
+	request := dto.UrlPreviewRequest{
+		Id:       id,
+		Host:     requestHost(c.Request),
+		Unlocked: uh.unlock.verify(c, id),
+		BaseUrl:  middleware.BaseUrl(c),
+	}
+
+	preview, err := uh.us.PreviewUrl(request)
+	if errors.Is(err, url.ErrPasswordRequired) {
+		renderPasswordForm(c, http.StatusOK, "")
+		return
+	}
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
 	}
 
-	c.Redirect(http.StatusTemporaryRedirect, redirectUrl)
+	renderPreview(c, preview)
+}
+
+// UnlockRouteById принимает пароль защищённой ссылки и выдаёт cookie доступа
+func (uh *UrlHandler) UnlockRouteById(c *gin.Context) {
+	id, _ := strings.CutSuffix(c.Param("id"), "+")
+	request := dto.UnlockUrlRequest{
+		Id:       id,
+		Host:     requestHost(c.Request),
+		Password: c.PostForm("password"),
+		ClientIp: c.ClientIP(),
+	}
+
+	err := uh.us.UnlockUrl(request)
+	switch {
+	case errors.Is(err, url.ErrInvalidPassword):
+		renderPasswordForm(c, http.StatusUnauthorized, "Invalid password.")
+		return
+	case errors.Is(err, url.ErrTooManyAttempts):
+		renderPasswordForm(c, http.StatusTooManyRequests, "Too many attempts, try again later.")
+		return
+	case err != nil:
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	uh.unlock.setCookie(c, request.Id)
+	c.Redirect(http.StatusSeeOther, c.Request.URL.RequestURI())
+}
 
+// GetCacheStats возвращает попадания, промахи и вытеснения кэша ссылок этого процесса
+func (uh *UrlHandler) GetCacheStats(c *gin.Context) {
+	c.JSON(http.StatusOK, uh.us.GetCacheStats())
 }
 
 func (uh *UrlHandler) CheckHealthz(c *gin.Context) {
@@ -134,3 +368,28 @@ func (uh *UrlHandler) CheckHealthz(c *gin.Context) {
 
 	c.String(http.StatusOK, body)
 }
+
+// forwardedQuery возвращает параметры запроса без служебных параметров сервиса и источник перехода.
+// Параметр источника считается служебным, только если содержит известную метку.
+func forwardedQuery(r *http.Request) (map[string][]string, string) {
+	query := r.URL.Query()
+	for _, key := range reservedQueryParams {
+		query.Del(key)
+	}
+
+	var source string
+	if query.Get(click.SourceParam) == click.SourceQr {
+		source = click.SourceQr
+		query.Del(click.SourceParam)
+	}
+	return query, source
+}
+
+// requestHost возвращает хост запроса без порта
+func requestHost(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.Host)
+	if err != nil {
+		return r.Host
+	}
+	return host
+}
@@ -0,0 +1,59 @@
+package handlers
+
+import (
+	"leenwood/yandex-http/internal/handler/middleware"
+	"leenwood/yandex-http/internal/usecase"
+	"leenwood/yandex-http/internal/usecase/dto"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/gin-gonic/gin"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// listUrlUseCase запоминает запрос списка ссылок, остальные методы не вызываются
+type listUrlUseCase struct {
+	usecase.UrlUseCaseInterface
+	request *dto.PaginationRequest
+}
+
+func (us listUrlUseCase) GetUrlList(request dto.PaginationRequest) (dto.UrlListResponse, error) {
+	*us.request = request
+	return dto.UrlListResponse{}, nil
+}
+
+var _ = Describe("GetUrlsInfo", func() {
+	var (
+		router  *gin.Engine
+		request dto.PaginationRequest
+	)
+
+	BeforeEach(func() {
+		gin.SetMode(gin.TestMode)
+		router = gin.New()
+		router.Use(middleware.Owner(map[string]string{"alice-key": "alice"}))
+		uh := &UrlHandler{us: listUrlUseCase{request: &request}}
+		router.GET("/list", uh.GetUrlsInfo)
+	})
+
+	list := func(apiKey string) int {
+		recorder := httptest.NewRecorder()
+		httpRequest := httptest.NewRequest(http.MethodGet, "/list?owner_id=bob", nil)
+		if apiKey != "" {
+			httpRequest.Header.Set(middleware.ApiKeyHeader, apiKey)
+		}
+		router.ServeHTTP(recorder, httpRequest)
+		return recorder.Code
+	}
+
+	It("should list the links of the owner of the API key", func() {
+		Expect(list("alice-key")).To(Equal(http.StatusOK))
+		Expect(request.OwnerId).To(Equal("alice"))
+	})
+
+	It("should ignore the owner from the query", func() {
+		Expect(list("")).To(Equal(http.StatusOK))
+		Expect(request.OwnerId).To(BeEmpty())
+	})
+})
@@ -0,0 +1,196 @@
+package metadata
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	neturl "net/url"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/charset"
+)
+
+var ErrNotHtml = errors.New("destination is not an HTML page")
+
+const (
+	maxTextLength = 500
+	maxUrlLength  = 2048
+	userAgent     = "Mozilla/5.0 (compatible; LinkPreview/1.0)"
+)
+
+// Page - сведения о странице назначения, пустые поля означают, что страница их не указала
+type Page struct {
+	Title       string
+	Description string
+	// Image и Favicon - абсолютные адреса
+	Image    string
+	SiteName string
+	Favicon  string
+}
+
+type FetcherInterface interface {
+	Fetch(ctx context.Context, rawUrl string) (Page, error)
+}
+
+// Fetcher загружает страницу и разбирает её заголовок. Ограничения по времени, адресам
+// и перенаправлениям задаёт переданный клиент, размер ответа ограничивается здесь.
+type Fetcher struct {
+	client      *http.Client
+	maxBodySize int64
+}
+
+func NewFetcher(client *http.Client, maxBodySize int64) *Fetcher {
+	return &Fetcher{client: client, maxBodySize: maxBodySize}
+}
+
+func (f *Fetcher) Fetch(ctx context.Context, rawUrl string) (Page, error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, rawUrl, nil)
+	if err != nil {
+		return Page{}, err
+	}
+	request.Header.Set("User-Agent", userAgent)
+	request.Header.Set("Accept", "text/html,application/xhtml+xml;q=0.9,*/*;q=0.1")
+
+	response, err := f.client.Do(request)
+	if err != nil {
+		return Page{}, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		return Page{}, fmt.Errorf("unexpected status %d", response.StatusCode)
+	}
+	contentType := response.Header.Get("Content-Type")
+	if mediaType, _, _ := mime.ParseMediaType(contentType); mediaType != "text/html" && mediaType != "application/xhtml+xml" {
+		return Page{}, ErrNotHtml
+	}
+
+	body, err := charset.NewReader(io.LimitReader(response.Body, f.maxBodySize), contentType)
+	if err != nil {
+		return Page{}, err
+	}
+	// Адреса картинок считаются от адреса после всех перенаправлений
+	return Parse(body, response.Request.URL)
+}
+
+// Parse читает заголовок документа до начала тела. Open Graph важнее Twitter Card,
+// а они оба важнее обычных <title> и description.
+func Parse(body io.Reader, base *neturl.URL) (Page, error) {
+	var (
+		fields  = make(map[string]string)
+		title   string
+		favicon string
+		inTitle bool
+	)
+
+	tokenizer := html.NewTokenizer(body)
+loop:
+	for {
+		switch tokenizer.Next() {
+		case html.ErrorToken:
+			// Обрезанный по размеру документ - не ошибка, используем то, что успели прочитать
+			if err := tokenizer.Err(); err != io.EOF && !errors.Is(err, io.ErrUnexpectedEOF) {
+				return Page{}, err
+			}
+			break loop
+		case html.TextToken:
+			if inTitle {
+				title += string(tokenizer.Text())
+			}
+		case html.EndTagToken:
+			name, _ := tokenizer.TagName()
+			switch string(name) {
+			case "title":
+				inTitle = false
+			case "head":
+				break loop
+			}
+		case html.StartTagToken, html.SelfClosingTagToken:
+			name, hasAttributes := tokenizer.TagName()
+			attributes := make(map[string]string)
+			for hasAttributes {
+				var key, value []byte
+				key, value, hasAttributes = tokenizer.TagAttr()
+				attributes[string(key)] = string(value)
+			}
+
+			switch string(name) {
+			case "title":
+				inTitle = title == ""
+			case "body":
+				break loop
+			case "base":
+				if href, err := base.Parse(attributes["href"]); err == nil && attributes["href"] != "" {
+					base = href
+				}
+			case "meta":
+				key := attributes["property"]
+				if key == "" {
+					key = attributes["name"]
+				}
+				key = strings.ToLower(strings.TrimSpace(key))
+				if _, ok := fields[key]; !ok {
+					fields[key] = attributes["content"]
+				}
+			case "link":
+				if favicon == "" && isIcon(attributes["rel"]) {
+					favicon = attributes["href"]
+				}
+			}
+		}
+	}
+
+	page := Page{
+		Title:       clean(first(fields["og:title"], fields["twitter:title"], title)),
+		Description: clean(first(fields["og:description"], fields["twitter:description"], fields["description"])),
+		Image:       resolve(base, first(fields["og:image"], fields["og:image:url"], fields["twitter:image"], fields["twitter:image:src"])),
+		SiteName:    clean(fields["og:site_name"]),
+		Favicon:     resolve(base, first(favicon, "/favicon.ico")),
+	}
+	return page, nil
+}
+
+func isIcon(rel string) bool {
+	for _, value := range strings.Fields(strings.ToLower(rel)) {
+		if value == "icon" || value == "apple-touch-icon" {
+			return true
+		}
+	}
+	return false
+}
+
+func first(values ...string) string {
+	for _, value := range values {
+		if strings.TrimSpace(value) != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// clean схлопывает пробелы и обрезает слишком длинный текст
+func clean(value string) string {
+	value = strings.Join(strings.Fields(value), " ")
+	if utf8.RuneCountInString(value) > maxTextLength {
+		value = strings.TrimSpace(string([]rune(value)[:maxTextLength-1])) + "…"
+	}
+	return value
+}
+
+// resolve превращает адрес со страницы в абсолютный, отбрасывая всё, кроме http и https
+func resolve(base *neturl.URL, value string) string {
+	value = strings.TrimSpace(value)
+	if value == "" || base == nil {
+		return ""
+	}
+	resolved, err := base.Parse(value)
+	if err != nil || (resolved.Scheme != "http" && resolved.Scheme != "https") || len(resolved.String()) > maxUrlLength {
+		return ""
+	}
+	return resolved.String()
+}
@@ -0,0 +1,110 @@
+package metadata
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestMetadata(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Metadata Test Suite")
+}
+
+var _ = Describe("Fetcher", func() {
+	var (
+		server  *httptest.Server
+		fetcher *Fetcher
+	)
+
+	BeforeEach(func() {
+		mux := http.NewServeMux()
+		mux.HandleFunc("/article", func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			w.Write([]byte(`<!DOCTYPE html>
+<html><head>
+  <title> Plain   title </title>
+  <meta name="description" content="Plain description">
+  <meta property="og:title" content="Open Graph title">
+  <meta property="og:image" content="/images/cover.png">
+  <meta property="og:site_name" content="Example">
+  <meta name="twitter:description" content="Twitter description">
+  <link rel="shortcut icon" href="static/icon.ico">
+</head><body><meta property="og:description" content="ignored"></body></html>`))
+		})
+		mux.HandleFunc("/plain", func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "text/html; charset=windows-1251")
+			// "Привет" в windows-1251
+			w.Write([]byte("<title>\xcf\xf0\xe8\xe2\xe5\xf2</title>"))
+		})
+		mux.HandleFunc("/moved", func(w http.ResponseWriter, r *http.Request) {
+			http.Redirect(w, r, "/docs/page", http.StatusFound)
+		})
+		mux.HandleFunc("/docs/page", func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "text/html")
+			w.Write([]byte(`<head><meta name="twitter:image" content="cover.jpg"></head>`))
+		})
+		mux.HandleFunc("/large", func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "text/html")
+			w.Write([]byte("<head>" + strings.Repeat("<!-- padding -->", 1000) + "<title>Too far</title></head>"))
+		})
+		mux.HandleFunc("/file.pdf", func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/pdf")
+		})
+		mux.HandleFunc("/missing", http.NotFound)
+		server = httptest.NewServer(mux)
+		fetcher = NewFetcher(server.Client(), 4096)
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("should prefer Open Graph and Twitter tags and resolve relative addresses", func() {
+		page, err := fetcher.Fetch(context.Background(), server.URL+"/article")
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(page).To(Equal(Page{
+			Title:       "Open Graph title",
+			Description: "Twitter description",
+			Image:       server.URL + "/images/cover.png",
+			SiteName:    "Example",
+			Favicon:     server.URL + "/static/icon.ico",
+		}))
+	})
+
+	It("should decode the page charset and fall back to the default favicon", func() {
+		page, err := fetcher.Fetch(context.Background(), server.URL+"/plain")
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(page.Title).To(Equal("Привет"))
+		Expect(page.Favicon).To(Equal(server.URL + "/favicon.ico"))
+	})
+
+	It("should resolve addresses against the page after redirects", func() {
+		page, err := fetcher.Fetch(context.Background(), server.URL+"/moved")
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(page.Image).To(Equal(server.URL + "/docs/cover.jpg"))
+	})
+
+	It("should read no more than the size limit", func() {
+		page, err := fetcher.Fetch(context.Background(), server.URL+"/large")
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(page.Title).To(BeEmpty())
+	})
+
+	It("should reject pages that are not HTML or not found", func() {
+		_, err := fetcher.Fetch(context.Background(), server.URL+"/file.pdf")
+		Expect(err).To(MatchError(ErrNotHtml))
+
+		_, err = fetcher.Fetch(context.Background(), server.URL+"/missing")
+		Expect(err).To(MatchError(ContainSubstring("404")))
+	})
+})
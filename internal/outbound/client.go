@@ -0,0 +1,75 @@
+package outbound
+
+import (
+	"errors"
+	"fmt"
+	"leenwood/yandex-http/config"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+)
+
+var (
+	ErrForbiddenAddress = errors.New("destination address is not allowed")
+	ErrTooManyRedirects = errors.New("too many redirects")
+	ErrUnsupportedUrl   = errors.New("only http and https destinations are allowed")
+)
+
+// sharedAddressSpace - адреса операторов связи (RFC 6598), net.IP.IsPrivate их не учитывает
+var sharedAddressSpace = &net.IPNet{IP: net.IPv4(100, 64, 0, 0), Mask: net.CIDRMask(10, 32)}
+
+// CheckAddress запрещает запросы к локальным, внутренним и служебным адресам
+func CheckAddress(ip net.IP) error {
+	if ip == nil ||
+		ip.IsLoopback() ||
+		ip.IsPrivate() ||
+		ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() ||
+		ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() ||
+		ip.IsMulticast() ||
+		sharedAddressSpace.Contains(ip) {
+		return ErrForbiddenAddress
+	}
+	return nil
+}
+
+// NewClient создаёт HTTP-клиент для запросов к страницам назначения. Адрес проверяется
+// при подключении, уже после разрешения имени, поэтому DNS-запись, указывающая
+// во внутреннюю сеть, тоже отклоняется. Прокси из окружения не используется.
+func NewClient(cfg config.FetchConfig) *http.Client {
+	dialer := &net.Dialer{Timeout: cfg.Timeout}
+	if !cfg.AllowPrivateNetworks {
+		dialer.Control = func(_, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if err = CheckAddress(net.ParseIP(host)); err != nil {
+				return fmt.Errorf("%w: %s", err, host)
+			}
+			return nil
+		}
+	}
+
+	return &http.Client{
+		Timeout: cfg.Timeout,
+		Transport: &http.Transport{
+			DialContext:           dialer.DialContext,
+			TLSHandshakeTimeout:   cfg.Timeout,
+			ResponseHeaderTimeout: cfg.Timeout,
+			IdleConnTimeout:       90 * time.Second,
+			MaxIdleConnsPerHost:   2,
+		},
+		CheckRedirect: func(request *http.Request, via []*http.Request) error {
+			if len(via) > cfg.MaxRedirects {
+				return ErrTooManyRedirects
+			}
+			if request.URL.Scheme != "http" && request.URL.Scheme != "https" {
+				return ErrUnsupportedUrl
+			}
+			return nil
+		},
+	}
+}
@@ -0,0 +1,91 @@
+package outbound
+
+import (
+	"leenwood/yandex-http/config"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestOutbound(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Outbound Test Suite")
+}
+
+var _ = Describe("Client", func() {
+	var (
+		server *httptest.Server
+		cfg    config.FetchConfig
+	)
+
+	BeforeEach(func() {
+		mux := http.NewServeMux()
+		mux.HandleFunc("/ok", func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNoContent)
+		})
+		mux.HandleFunc("/loop", func(w http.ResponseWriter, r *http.Request) {
+			http.Redirect(w, r, "/loop", http.StatusFound)
+		})
+		mux.HandleFunc("/file", func(w http.ResponseWriter, r *http.Request) {
+			http.Redirect(w, r, "file:///etc/passwd", http.StatusFound)
+		})
+		server = httptest.NewServer(mux)
+		cfg = config.FetchConfig{Timeout: time.Second, MaxRedirects: 3}
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("should refuse to connect to local addresses", func() {
+		_, err := NewClient(cfg).Get(server.URL + "/ok")
+
+		Expect(err).To(MatchError(ErrForbiddenAddress))
+	})
+
+	It("should connect to local addresses when private networks are allowed", func() {
+		cfg.AllowPrivateNetworks = true
+
+		response, err := NewClient(cfg).Get(server.URL + "/ok")
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(response.StatusCode).To(Equal(http.StatusNoContent))
+	})
+
+	It("should limit redirects and their schemes", func() {
+		cfg.AllowPrivateNetworks = true
+		client := NewClient(cfg)
+
+		_, err := client.Get(server.URL + "/loop")
+		Expect(err).To(MatchError(ErrTooManyRedirects))
+
+		_, err = client.Get(server.URL + "/file")
+		Expect(err).To(MatchError(ErrUnsupportedUrl))
+	})
+
+	DescribeTable("CheckAddress",
+		func(address string, allowed bool) {
+			err := CheckAddress(net.ParseIP(address))
+			if allowed {
+				Expect(err).NotTo(HaveOccurred())
+			} else {
+				Expect(err).To(MatchError(ErrForbiddenAddress))
+			}
+		},
+		Entry("public IPv4", "93.184.216.34", true),
+		Entry("public IPv6", "2606:2800:220:1::1", true),
+		Entry("loopback", "127.0.0.1", false),
+		Entry("IPv6 loopback", "::1", false),
+		Entry("private", "10.1.2.3", false),
+		Entry("cloud metadata", "169.254.169.254", false),
+		Entry("shared address space", "100.64.0.1", false),
+		Entry("unspecified", "0.0.0.0", false),
+		Entry("IPv4-mapped private", "::ffff:192.168.0.1", false),
+		Entry("unique local IPv6", "fd00::1", false),
+	)
+})
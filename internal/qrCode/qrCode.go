@@ -0,0 +1,276 @@
+package qrCode
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/skip2/go-qrcode"
+)
+
+var ErrInvalidOptions = errors.New("invalid QR code options")
+
+type Format string
+
+const (
+	FormatPng Format = "png"
+	FormatSvg Format = "svg"
+)
+
+const (
+	DefaultSize   = 256
+	MinSize       = 64
+	MaxSize       = 2048
+	DefaultMargin = 4
+	MaxMargin     = 16
+)
+
+// logoShare - доля стороны кода, которую занимает логотип. При уровне коррекции H
+// код читается, даже если закрыта заметно большая площадь.
+const logoShare = 0.2
+
+var levels = map[string]qrcode.RecoveryLevel{
+	"L": qrcode.Low,
+	"M": qrcode.Medium,
+	"Q": qrcode.High,
+	"H": qrcode.Highest,
+}
+
+// Options - параметры отрисовки, нулевые значения заменяются значениями по умолчанию
+type Options struct {
+	Format Format
+	// Size - сторона изображения в пикселях
+	Size int
+	// Level - уровень коррекции ошибок: L, M, Q или H
+	Level string
+	// Margin - ширина поля вокруг кода в модулях
+	Margin     int
+	Foreground string
+	Background string
+	// Logo - изображение в центре кода, требует уровня коррекции Q или H
+	Logo image.Image
+}
+
+// Normalize проверяет параметры и подставляет значения по умолчанию
+func Normalize(options Options) (Options, error) {
+	options.Format = Format(strings.ToLower(string(options.Format)))
+	switch options.Format {
+	case "":
+		options.Format = FormatPng
+	case FormatPng, FormatSvg:
+	default:
+		return options, fmt.Errorf("%w: unsupported format %q", ErrInvalidOptions, options.Format)
+	}
+
+	if options.Size == 0 {
+		options.Size = DefaultSize
+	}
+	if options.Size < MinSize || options.Size > MaxSize {
+		return options, fmt.Errorf("%w: size must be between %d and %d", ErrInvalidOptions, MinSize, MaxSize)
+	}
+
+	if options.Margin < 0 || options.Margin > MaxMargin {
+		return options, fmt.Errorf("%w: margin must be between 0 and %d", ErrInvalidOptions, MaxMargin)
+	}
+
+	options.Level = strings.ToUpper(options.Level)
+	if options.Level == "" {
+		options.Level = "M"
+		if options.Logo != nil {
+			options.Level = "H"
+		}
+	}
+	if _, ok := levels[options.Level]; !ok {
+		return options, fmt.Errorf("%w: level must be one of L, M, Q, H", ErrInvalidOptions)
+	}
+	if options.Logo != nil && (options.Level == "L" || options.Level == "M") {
+		return options, fmt.Errorf("%w: logo requires level Q or H", ErrInvalidOptions)
+	}
+
+	var err error
+	if options.Foreground, err = normalizeColor(options.Foreground, "000000"); err != nil {
+		return options, err
+	}
+	if options.Background, err = normalizeColor(options.Background, "ffffff"); err != nil {
+		return options, err
+	}
+	return options, nil
+}
+
+// Render рисует QR-код с содержимым content, параметры должны быть приведены Normalize
+func Render(content string, options Options) ([]byte, error) {
+	code, err := qrcode.New(content, levels[options.Level])
+	if err != nil {
+		return nil, err
+	}
+	code.DisableBorder = true
+	modules := code.Bitmap()
+
+	if options.Format == FormatSvg {
+		return renderSvg(modules, options)
+	}
+	return renderPng(modules, options)
+}
+
+// Logo - логотип из настроек сервиса
+type Logo struct {
+	Image image.Image
+	// Digest - хэш файла, меняется при замене логотипа
+	Digest string
+}
+
+// LoadLogo читает PNG логотипа, пустой путь означает отсутствие логотипа
+func LoadLogo(path string) (*Logo, error) {
+	if path == "" {
+		return nil, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	img, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("invalid QR logo %s: %w", path, err)
+	}
+	sum := sha256.Sum256(data)
+	return &Logo{Image: img, Digest: hex.EncodeToString(sum[:8])}, nil
+}
+
+func renderPng(modules [][]bool, options Options) ([]byte, error) {
+	foreground, background := parseColor(options.Foreground), parseColor(options.Background)
+	img := image.NewRGBA(image.Rect(0, 0, options.Size, options.Size))
+	fill(img, img.Bounds(), background)
+
+	// Модули рисуются целым числом пикселей, остаток делится между полями
+	count := len(modules) + 2*options.Margin
+	scale := max(options.Size/count, 1)
+	offset := (options.Size - scale*len(modules)) / 2
+	for y, row := range modules {
+		for x, dark := range row {
+			if dark {
+				fill(img, image.Rect(offset+x*scale, offset+y*scale, offset+(x+1)*scale, offset+(y+1)*scale), foreground)
+			}
+		}
+	}
+
+	if options.Logo != nil {
+		side := int(float64(scale*len(modules)) * logoShare)
+		start := (options.Size - side) / 2
+		area := image.Rect(start, start, start+side, start+side)
+		fill(img, area.Inset(-scale), background)
+		drawScaled(img, area, options.Logo)
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func renderSvg(modules [][]bool, options Options) ([]byte, error) {
+	count := len(modules) + 2*options.Margin
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" shape-rendering="crispEdges">`,
+		options.Size, options.Size, count, count)
+	fmt.Fprintf(&buf, `<rect width="%d" height="%d" fill="#%s"/>`, count, count, options.Background)
+
+	buf.WriteString(`<path fill="#` + options.Foreground + `" d="`)
+	for y, row := range modules {
+		for x, dark := range row {
+			if dark {
+				fmt.Fprintf(&buf, "M%d %dh1v1h-1z", x+options.Margin, y+options.Margin)
+			}
+		}
+	}
+	buf.WriteString(`"/>`)
+
+	if options.Logo != nil {
+		var logo bytes.Buffer
+		if err := png.Encode(&logo, options.Logo); err != nil {
+			return nil, err
+		}
+		side := float64(len(modules)) * logoShare
+		start := (float64(count) - side) / 2
+		fmt.Fprintf(&buf, `<rect x="%s" y="%s" width="%s" height="%s" fill="#%s"/>`,
+			formatFloat(start-1), formatFloat(start-1), formatFloat(side+2), formatFloat(side+2), options.Background)
+		fmt.Fprintf(&buf, `<image x="%s" y="%s" width="%s" height="%s" href="data:image/png;base64,%s"/>`,
+			formatFloat(start), formatFloat(start), formatFloat(side), formatFloat(side),
+			base64.StdEncoding.EncodeToString(logo.Bytes()))
+	}
+
+	buf.WriteString("</svg>")
+	return buf.Bytes(), nil
+}
+
+func fill(img *image.RGBA, area image.Rectangle, c color.RGBA) {
+	area = area.Intersect(img.Bounds())
+	for y := area.Min.Y; y < area.Max.Y; y++ {
+		for x := area.Min.X; x < area.Max.X; x++ {
+			img.SetRGBA(x, y, c)
+		}
+	}
+}
+
+// drawScaled вписывает изображение в область методом ближайшего соседа
+func drawScaled(img *image.RGBA, area image.Rectangle, src image.Image) {
+	bounds := src.Bounds()
+	for y := 0; y < area.Dy(); y++ {
+		for x := 0; x < area.Dx(); x++ {
+			sx := bounds.Min.X + x*bounds.Dx()/area.Dx()
+			sy := bounds.Min.Y + y*bounds.Dy()/area.Dy()
+			r, g, b, a := src.At(sx, sy).RGBA()
+			if a == 0 {
+				continue
+			}
+			// Полупрозрачные пиксели логотипа накладываются на фон
+			base := img.RGBAAt(area.Min.X+x, area.Min.Y+y)
+			img.SetRGBA(area.Min.X+x, area.Min.Y+y, color.RGBA{
+				R: blend(base.R, r, a),
+				G: blend(base.G, g, a),
+				B: blend(base.B, b, a),
+				A: 0xff,
+			})
+		}
+	}
+}
+
+// blend накладывает канал с предумноженной альфой на непрозрачный фон
+func blend(base uint8, value, alpha uint32) uint8 {
+	return uint8((value + uint32(base)*0x101*(0xffff-alpha)/0xffff) >> 8)
+}
+
+// normalizeColor приводит цвет вида #rgb или #rrggbb к шести шестнадцатеричным цифрам
+func normalizeColor(value, defaultValue string) (string, error) {
+	value = strings.ToLower(strings.TrimPrefix(value, "#"))
+	if value == "" {
+		return defaultValue, nil
+	}
+	if len(value) == 3 {
+		value = string([]byte{value[0], value[0], value[1], value[1], value[2], value[2]})
+	}
+	if _, err := strconv.ParseUint(value, 16, 32); err != nil || len(value) != 6 {
+		return "", fmt.Errorf("%w: invalid color %q", ErrInvalidOptions, value)
+	}
+	return value, nil
+}
+
+func parseColor(value string) color.RGBA {
+	rgb, _ := strconv.ParseUint(value, 16, 32)
+	return color.RGBA{R: uint8(rgb >> 16), G: uint8(rgb >> 8), B: uint8(rgb), A: 0xff}
+}
+
+func formatFloat(value float64) string {
+	return strconv.FormatFloat(value, 'f', 2, 64)
+}
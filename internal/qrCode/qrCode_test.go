@@ -0,0 +1,90 @@
+package qrCode
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"strings"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestQrCode(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "QrCode Test Suite")
+}
+
+var _ = Describe("QrCode", func() {
+	const content = "https://sho.rt/abc?src=qr"
+
+	Describe("Normalize", func() {
+		It("should fill defaults", func() {
+			options, err := Normalize(Options{Margin: DefaultMargin})
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(options).To(Equal(Options{
+				Format: FormatPng, Size: DefaultSize, Level: "M", Margin: DefaultMargin,
+				Foreground: "000000", Background: "ffffff",
+			}))
+		})
+
+		It("should expand short colors and raise the level for a logo", func() {
+			logo := image.NewRGBA(image.Rect(0, 0, 4, 4))
+			options, err := Normalize(Options{Format: "SVG", Foreground: "#f00", Background: "00FF00", Logo: logo})
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(options.Format).To(Equal(FormatSvg))
+			Expect(options.Foreground).To(Equal("ff0000"))
+			Expect(options.Background).To(Equal("00ff00"))
+			Expect(options.Level).To(Equal("H"))
+		})
+
+		DescribeTable("should reject invalid options",
+			func(options Options) {
+				_, err := Normalize(options)
+				Expect(err).To(MatchError(ErrInvalidOptions))
+			},
+			Entry("format", Options{Format: "gif"}),
+			Entry("size", Options{Size: 10}),
+			Entry("margin", Options{Margin: MaxMargin + 1}),
+			Entry("level", Options{Level: "X"}),
+			Entry("color", Options{Foreground: "#12345z"}),
+			Entry("logo with low level", Options{Level: "L", Logo: image.NewRGBA(image.Rect(0, 0, 1, 1))}),
+		)
+	})
+
+	Describe("Render", func() {
+		It("should render a png of the requested size and colors", func() {
+			options, _ := Normalize(Options{Size: 300, Margin: 2, Foreground: "0000ff"})
+
+			body, err := Render(content, options)
+			Expect(err).ToNot(HaveOccurred())
+
+			img, err := png.Decode(bytes.NewReader(body))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(img.Bounds().Dx()).To(Equal(300))
+			Expect(color.RGBAModel.Convert(img.At(0, 0))).To(Equal(color.RGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff}))
+			// Левый верхний угол кода - тёмный модуль поискового узора
+			offset := (300 - 300/(25+4)*25) / 2
+			Expect(color.RGBAModel.Convert(img.At(offset, offset))).To(Equal(color.RGBA{B: 0xff, A: 0xff}))
+		})
+
+		It("should render an svg with a centered logo", func() {
+			logo := image.NewRGBA(image.Rect(0, 0, 8, 8))
+			options, _ := Normalize(Options{Format: FormatSvg, Margin: 1, Logo: logo})
+
+			body, err := Render(content, options)
+			Expect(err).ToNot(HaveOccurred())
+
+			svg := string(body)
+			Expect(svg).To(HavePrefix("<svg "))
+			Expect(svg).To(ContainSubstring(`width="256"`))
+			Expect(svg).To(ContainSubstring("M1 1h1v1h-1z"))
+			Expect(svg).To(ContainSubstring("data:image/png;base64,"))
+			Expect(strings.Count(svg, "<image")).To(Equal(1))
+		})
+	})
+})
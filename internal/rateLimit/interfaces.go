@@ -0,0 +1,8 @@
+package rateLimit
+
+type StoreInterface interface {
+	// Take расходует один токен из корзины key
+	Take(key string, limit Limit) (Result, error)
+	// Refund возвращает в корзину key токен, взятый Take
+	Refund(key string, limit Limit) error
+}
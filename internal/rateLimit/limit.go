@@ -0,0 +1,85 @@
+package rateLimit
+
+import (
+	"leenwood/yandex-http/config"
+	"math"
+	"time"
+)
+
+// Limit описывает token bucket: PerMinute токенов пополняется за минуту,
+// Burst - ёмкость корзины. Нулевой PerMinute отключает ограничение.
+type Limit struct {
+	PerMinute int
+	Burst     int
+}
+
+func NewLimit(cfg config.LimitConfig) Limit {
+	return Limit{PerMinute: cfg.PerMinute, Burst: cfg.Burst}
+}
+
+func (l Limit) Enabled() bool {
+	return l.PerMinute > 0
+}
+
+func (l Limit) capacity() float64 {
+	if l.Burst > 0 {
+		return float64(l.Burst)
+	}
+	return float64(l.PerMinute)
+}
+
+func (l Limit) interval() time.Duration {
+	return time.Minute / time.Duration(l.PerMinute)
+}
+
+type Result struct {
+	Allowed    bool
+	Limit      int
+	Remaining  int
+	ResetAfter time.Duration
+	RetryAfter time.Duration
+}
+
+// Bucket - состояние корзины клиента, хранимое в StoreInterface
+type Bucket struct {
+	Tokens      float64
+	UpdatedDate time.Time
+}
+
+// NewBucket возвращает полную корзину для нового клиента
+func NewBucket(limit Limit, now time.Time) Bucket {
+	return Bucket{Tokens: limit.capacity(), UpdatedDate: now}
+}
+
+// Take пополняет корзину за прошедшее время и пытается забрать один токен
+func (b *Bucket) Take(limit Limit, now time.Time) Result {
+	capacity := limit.capacity()
+	interval := limit.interval()
+
+	if elapsed := now.Sub(b.UpdatedDate); elapsed > 0 {
+		b.Tokens = math.Min(capacity, b.Tokens+float64(elapsed)/float64(interval))
+	}
+	b.UpdatedDate = now
+
+	result := Result{Limit: int(capacity)}
+	if b.Tokens >= 1 {
+		b.Tokens--
+		result.Allowed = true
+	} else {
+		result.RetryAfter = time.Duration((1 - b.Tokens) * float64(interval))
+	}
+	result.Remaining = int(b.Tokens)
+	result.ResetAfter = time.Duration((capacity - b.Tokens) * float64(interval))
+
+	return result
+}
+
+// Refund возвращает в корзину взятый токен, не превышая ёмкость
+func (b *Bucket) Refund(limit Limit) {
+	b.Tokens = math.Min(limit.capacity(), b.Tokens+1)
+}
+
+// Full сообщает, что корзина пополнилась бы до ёмкости к моменту now
+func (b *Bucket) Full(limit Limit, now time.Time) bool {
+	return b.Tokens+float64(now.Sub(b.UpdatedDate))/float64(limit.interval()) >= limit.capacity()
+}
@@ -0,0 +1,68 @@
+package rateLimit
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestRateLimit(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Rate Limit Test Suite")
+}
+
+var _ = Describe("Bucket", func() {
+	var (
+		limit Limit
+		now   time.Time
+	)
+
+	BeforeEach(func() {
+		limit = Limit{PerMinute: 60, Burst: 2}
+		now = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	})
+
+	Context("when the bucket is full", func() {
+		It("should allow up to Burst requests at once", func() {
+			bucket := NewBucket(limit, now)
+
+			first := bucket.Take(limit, now)
+			second := bucket.Take(limit, now)
+			third := bucket.Take(limit, now)
+
+			Expect(first.Allowed).To(BeTrue())
+			Expect(first.Remaining).To(Equal(1))
+			Expect(second.Allowed).To(BeTrue())
+			Expect(second.Remaining).To(Equal(0))
+			Expect(third.Allowed).To(BeFalse())
+			Expect(third.RetryAfter).To(Equal(time.Second))
+			Expect(third.ResetAfter).To(Equal(2 * time.Second))
+		})
+	})
+
+	Context("when time passes", func() {
+		It("should refill tokens at PerMinute rate without exceeding Burst", func() {
+			bucket := NewBucket(limit, now)
+			bucket.Take(limit, now)
+			bucket.Take(limit, now)
+
+			Expect(bucket.Take(limit, now.Add(time.Second)).Allowed).To(BeTrue())
+			Expect(bucket.Full(limit, now.Add(time.Hour))).To(BeTrue())
+
+			result := bucket.Take(limit, now.Add(time.Hour))
+			Expect(result.Allowed).To(BeTrue())
+			Expect(result.Remaining).To(Equal(1))
+		})
+	})
+
+	Context("when Burst is not set", func() {
+		It("should use PerMinute as the capacity", func() {
+			limit = Limit{PerMinute: 5}
+			bucket := NewBucket(limit, now)
+
+			Expect(bucket.Take(limit, now).Limit).To(Equal(5))
+		})
+	})
+})
@@ -0,0 +1,71 @@
+package memoryStore
+
+import (
+	"leenwood/yandex-http/internal/rateLimit"
+	"sync"
+	"time"
+)
+
+// cleanupInterval - как часто удаляются корзины, успевшие пополниться до конца
+const cleanupInterval = time.Minute
+
+// Store хранит корзины в памяти процесса, подходит для одного экземпляра сервиса
+type Store struct {
+	mu          sync.Mutex
+	buckets     map[string]*entry
+	lastCleanup time.Time
+	now         func() time.Time
+}
+
+type entry struct {
+	bucket rateLimit.Bucket
+	limit  rateLimit.Limit
+}
+
+func NewStore() *Store {
+	return &Store{
+		buckets:     make(map[string]*entry),
+		lastCleanup: time.Now(),
+		now:         time.Now,
+	}
+}
+
+func (s *Store) Take(key string, limit rateLimit.Limit) (rateLimit.Result, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	if now.Sub(s.lastCleanup) > cleanupInterval {
+		s.cleanup(now)
+	}
+
+	e, ok := s.buckets[key]
+	if !ok {
+		e = &entry{bucket: rateLimit.NewBucket(limit, now)}
+		s.buckets[key] = e
+	}
+	e.limit = limit
+
+	return e.bucket.Take(limit, now), nil
+}
+
+// Refund ничего не делает для удалённой корзины: она и так считается полной
+func (s *Store) Refund(key string, limit rateLimit.Limit) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if e, ok := s.buckets[key]; ok {
+		e.bucket.Refund(limit)
+	}
+	return nil
+}
+
+// cleanup удаляет полные корзины: они неотличимы от отсутствующих
+func (s *Store) cleanup(now time.Time) {
+	for key, e := range s.buckets {
+		if e.bucket.Full(e.limit, now) {
+			delete(s.buckets, key)
+		}
+	}
+	s.lastCleanup = now
+}
@@ -0,0 +1,78 @@
+package memoryStore
+
+import (
+	"leenwood/yandex-http/internal/rateLimit"
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestMemoryStore(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Memory Store Test Suite")
+}
+
+var _ = Describe("Store", func() {
+	var (
+		store *Store
+		limit rateLimit.Limit
+		now   time.Time
+	)
+
+	BeforeEach(func() {
+		now = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+		store = NewStore()
+		store.lastCleanup = now
+		store.now = func() time.Time { return now }
+		limit = rateLimit.Limit{PerMinute: 60, Burst: 2}
+	})
+
+	It("should allow up to Burst requests and then refill one token per interval", func() {
+		for range 2 {
+			result, err := store.Take("client", limit)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.Allowed).To(BeTrue())
+		}
+		result, _ := store.Take("client", limit)
+		Expect(result.Allowed).To(BeFalse())
+		Expect(result.RetryAfter).To(Equal(time.Second))
+
+		now = now.Add(time.Second)
+		result, _ = store.Take("client", limit)
+		Expect(result.Allowed).To(BeTrue())
+		Expect(result.Remaining).To(BeZero())
+	})
+
+	It("should keep separate buckets per key", func() {
+		store.Take("first", limit)
+		store.Take("first", limit)
+
+		result, _ := store.Take("second", limit)
+		Expect(result.Allowed).To(BeTrue())
+	})
+
+	It("should refund a taken token without exceeding the burst", func() {
+		store.Take("client", limit)
+		store.Take("client", limit)
+
+		Expect(store.Refund("client", limit)).To(Succeed())
+		Expect(store.Refund("client", limit)).To(Succeed())
+		Expect(store.Refund("client", limit)).To(Succeed())
+		Expect(store.buckets["client"].bucket.Tokens).To(Equal(float64(2)))
+
+		Expect(store.Refund("unknown", limit)).To(Succeed())
+		Expect(store.buckets).NotTo(HaveKey("unknown"))
+	})
+
+	It("should drop buckets that have refilled", func() {
+		store.Take("idle", limit)
+
+		now = now.Add(cleanupInterval + time.Second)
+		store.Take("other", limit)
+
+		Expect(store.buckets).NotTo(HaveKey("idle"))
+		Expect(store.buckets).To(HaveKey("other"))
+	})
+})
@@ -0,0 +1,147 @@
+package postgresStore
+
+import (
+	"context"
+	"fmt"
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"leenwood/yandex-http/config"
+	"leenwood/yandex-http/internal/rateLimit"
+	"sync"
+	"time"
+)
+
+const (
+	// pruneInterval - как часто удаляются давно не используемые корзины
+	pruneInterval = time.Hour
+	// staleAfter - сколько корзина хранится без запросов. За это время любая корзина
+	// с разумным лимитом пополняется до конца и неотличима от отсутствующей.
+	staleAfter = 24 * time.Hour
+)
+
+// Store хранит корзины в таблице rate_limits, счётчики общие для всех экземпляров сервиса
+type Store struct {
+	db  *pgxpool.Pool
+	sq  sq.StatementBuilderType
+	ctx context.Context
+	now func() time.Time
+
+	mu        sync.Mutex
+	lastPrune time.Time
+}
+
+func NewStore(ctx context.Context, config config.DatabaseConfig) (*Store, error) {
+	dsn := fmt.Sprintf(
+		"postgres://%s:%s@%s:%s/%s",
+		config.Username,
+		config.Password,
+		config.Hostname,
+		config.Port,
+		config.Database,
+	)
+	dbpool, err := pgxpool.New(ctx, dsn)
+	if err != nil {
+		return nil, err
+	}
+	return &Store{
+		db:  dbpool,
+		sq:  sq.StatementBuilder.PlaceholderFormat(sq.Dollar),
+		ctx: ctx,
+		now: func() time.Time { return time.Now().UTC() },
+	}, nil
+}
+
+func (s *Store) Take(key string, limit rateLimit.Limit) (rateLimit.Result, error) {
+	s.prune(s.now())
+
+	var result rateLimit.Result
+	err := s.update(key, limit, func(bucket *rateLimit.Bucket, now time.Time) {
+		result = bucket.Take(limit, now)
+	})
+	return result, err
+}
+
+func (s *Store) Refund(key string, limit rateLimit.Limit) error {
+	return s.update(key, limit, func(bucket *rateLimit.Bucket, _ time.Time) {
+		bucket.Refund(limit)
+	})
+}
+
+// update меняет корзину key под блокировкой строки, чтобы параллельные запросы изменяли её по очереди
+func (s *Store) update(key string, limit rateLimit.Limit, change func(bucket *rateLimit.Bucket, now time.Time)) error {
+	now := s.now()
+	bucket := rateLimit.NewBucket(limit, now)
+
+	tx, err := s.db.Begin(s.ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(s.ctx)
+
+	// Создаём полную корзину, если клиента ещё нет, чтобы затем заблокировать строку
+	query, args, err := s.sq.
+		Insert("rate_limits").
+		Columns("key", "tokens", "updated_date").
+		Values(key, bucket.Tokens, bucket.UpdatedDate).
+		Suffix("ON CONFLICT (key) DO NOTHING").
+		ToSql()
+	if err != nil {
+		return err
+	}
+	if _, err = tx.Exec(s.ctx, query, args...); err != nil {
+		return err
+	}
+
+	query, args, err = s.sq.
+		Select("tokens", "updated_date").
+		From("rate_limits").
+		Where(sq.Eq{"key": key}).
+		Suffix("FOR UPDATE").
+		ToSql()
+	if err != nil {
+		return err
+	}
+	if err = tx.QueryRow(s.ctx, query, args...).Scan(&bucket.Tokens, &bucket.UpdatedDate); err != nil {
+		return err
+	}
+
+	change(&bucket, now)
+
+	query, args, err = s.sq.
+		Update("rate_limits").
+		Set("tokens", bucket.Tokens).
+		Set("updated_date", bucket.UpdatedDate).
+		Where(sq.Eq{"key": key}).
+		ToSql()
+	if err != nil {
+		return err
+	}
+	if _, err = tx.Exec(s.ctx, query, args...); err != nil {
+		return err
+	}
+
+	return tx.Commit(s.ctx)
+}
+
+// prune не чаще раза в pruneInterval удаляет корзины, к которым не обращались дольше staleAfter.
+// Ошибка удаления не мешает ограничению запросов.
+func (s *Store) prune(now time.Time) {
+	s.mu.Lock()
+	if now.Sub(s.lastPrune) < pruneInterval {
+		s.mu.Unlock()
+		return
+	}
+	s.lastPrune = now
+	s.mu.Unlock()
+
+	query, args, err := s.sq.
+		Delete("rate_limits").
+		Where(sq.Lt{"updated_date": now.Add(-staleAfter)}).
+		ToSql()
+	if err == nil {
+		_, err = s.db.Exec(s.ctx, query, args...)
+	}
+	if err != nil {
+		fmt.Printf("Rate limit prune error - %s\r\n", err)
+	}
+}
@@ -0,0 +1,90 @@
+package postgresStore
+
+import (
+	"context"
+	"leenwood/yandex-http/config"
+	"leenwood/yandex-http/internal/rateLimit"
+	"os"
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestPostgresStore(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Postgres Store Test Suite")
+}
+
+// Тесты работают с базой из DATABASE_* с применёнными миграциями и запускаются только с RATE_LIMIT_TEST_POSTGRES=1
+var _ = Describe("Store", func() {
+	var (
+		store *Store
+		limit rateLimit.Limit
+		now   time.Time
+		key   string
+	)
+
+	BeforeEach(func() {
+		if os.Getenv("RATE_LIMIT_TEST_POSTGRES") != "1" {
+			Skip("RATE_LIMIT_TEST_POSTGRES is not set")
+		}
+
+		var err error
+		store, err = NewStore(context.Background(), config.NewConfig().Database)
+		Expect(err).NotTo(HaveOccurred())
+		DeferCleanup(store.db.Close)
+
+		now = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+		store.now = func() time.Time { return now }
+		store.lastPrune = now
+		limit = rateLimit.Limit{PerMinute: 60, Burst: 2}
+		key = "test:" + time.Now().Format(time.RFC3339Nano)
+		DeferCleanup(func() {
+			_, err := store.db.Exec(context.Background(), "DELETE FROM rate_limits WHERE key = $1", key)
+			Expect(err).NotTo(HaveOccurred())
+		})
+	})
+
+	It("should allow up to Burst requests and then refill one token per interval", func() {
+		for range 2 {
+			result, err := store.Take(key, limit)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.Allowed).To(BeTrue())
+		}
+		result, err := store.Take(key, limit)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Allowed).To(BeFalse())
+		Expect(result.RetryAfter).To(Equal(time.Second))
+
+		now = now.Add(time.Second)
+		result, err = store.Take(key, limit)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Allowed).To(BeTrue())
+	})
+
+	It("should refund a taken token", func() {
+		for range 2 {
+			_, err := store.Take(key, limit)
+			Expect(err).NotTo(HaveOccurred())
+		}
+		Expect(store.Refund(key, limit)).To(Succeed())
+
+		result, err := store.Take(key, limit)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Allowed).To(BeTrue())
+	})
+
+	It("should delete buckets that were not used for a long time", func() {
+		_, err := store.Take(key, limit)
+		Expect(err).NotTo(HaveOccurred())
+
+		now = now.Add(staleAfter + pruneInterval)
+		store.prune(now)
+
+		var count int
+		Expect(store.db.QueryRow(context.Background(), "SELECT count(*) FROM rate_limits WHERE key = $1", key).Scan(&count)).To(Succeed())
+		Expect(count).To(BeZero())
+	})
+})
@@ -0,0 +1,247 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"leenwood/yandex-http/internal/domain/customDomain"
+	"leenwood/yandex-http/internal/domain/url"
+	"leenwood/yandex-http/internal/usecase/dto"
+	"strings"
+)
+
+const (
+	// MaxBulkItems - наибольшее число ссылок в одном запросе массового создания
+	MaxBulkItems = 10000
+	// bulkBatchSize - сколько ссылок сохраняется одной транзакцией вне атомарного режима
+	bulkBatchSize = 500
+)
+
+// bulkItem - проверенный элемент запроса, ожидающий сохранения
+type bulkItem struct {
+	index  int
+	url    *url.Url
+	domain *customDomain.Domain
+}
+
+// CreateShortUrls создаёт ссылки пачками и возвращает результат каждого элемента в порядке запроса.
+// Сначала проверяются все элементы, затем сохраняются прошедшие проверку.
+// В атомарном режиме при любой ошибке не создаётся ни одна ссылка.
+func (us *UrlUseCase) CreateShortUrls(request dto.BulkCreateRequest) (dto.BulkCreateResponse, error) {
+	if len(request.Items) > MaxBulkItems {
+		return dto.BulkCreateResponse{}, fmt.Errorf("%w: at most %d links per request", url.ErrTooManyItems, MaxBulkItems)
+	}
+
+	results := make([]dto.BulkCreateResult, len(request.Items))
+	pending, duplicates := us.prepareBulk(request, results)
+
+	var customId bool
+	for _, item := range pending {
+		customId = customId || item.url.CustomId
+	}
+	limit, err := us.p.createLimit(request.OwnerId, "", customId)
+	if err != nil {
+		return dto.BulkCreateResponse{}, err
+	}
+
+	if request.Atomic {
+		if hasErrors(results) {
+			abortBulk(results, nil)
+		} else if err := us.saveBulk(pending, request.BaseUrl, results, limit); err != nil {
+			abortBulk(results, err)
+		}
+	} else {
+		for start := 0; start < len(pending); start += bulkBatchSize {
+			batch := pending[start:min(start+bulkBatchSize, len(pending))]
+			if err := us.saveBulk(batch, request.BaseUrl, results, limit); err != nil {
+				// Пачка откатилась целиком, сохраняем её по одной ссылке, чтобы найти ошибочные
+				us.saveBulkOneByOne(batch, request.BaseUrl, results, limit)
+			}
+		}
+	}
+
+	// Повторы ссылки без собственных настроек получают результат первого вхождения
+	for index, first := range duplicates {
+		results[index] = results[first]
+		results[index].Index = index
+		results[index].Created = false
+	}
+
+	response := dto.BulkCreateResponse{Results: results}
+	for _, result := range results {
+		switch {
+		case result.Err != nil:
+			response.Failed++
+		case result.Created:
+			response.Created++
+		}
+	}
+	return response, nil
+}
+
+// prepareBulk проверяет элементы и возвращает ссылки для сохранения и повторы: индекс повтора - индекс первого вхождения.
+// Ошибки и найденные существующие ссылки сразу записываются в results.
+func (us *UrlUseCase) prepareBulk(request dto.BulkCreateRequest, results []dto.BulkCreateResult) ([]bulkItem, map[int]int) {
+	var (
+		pending    []bulkItem
+		duplicates = make(map[int]int)
+		first      = make(map[string]int)
+		ids        = make(map[string]bool)
+		domains    = make(map[string]*customDomain.Domain)
+	)
+
+	for i, item := range request.Items {
+		results[i].Index = i
+		create := transformBulkItem(item, request.OwnerId, request.BaseUrl)
+		if strings.TrimSpace(create.Url) == "" {
+			results[i].Err = fmt.Errorf("%w: url is required", url.ErrInvalidUrl)
+			continue
+		}
+
+		hostname := strings.ToLower(create.Domain)
+		domain, ok := domains[hostname]
+		if !ok {
+			var err error
+			if domain, err = findVerifiedDomain(us.d, hostname); err != nil {
+				results[i].Err = err
+				continue
+			}
+			domains[hostname] = domain
+		}
+
+		prepared, err := prepareUrl(us.r, create)
+		if err != nil {
+			results[i].Err = err
+			continue
+		}
+		if prepared.existing != nil {
+			results[i].Id = prepared.existing.Id
+			results[i].Url = us.b.Build(request.BaseUrl, domain, prepared.existing.Id)
+			continue
+		}
+
+		if key := prepared.url.DedupKey; key != "" {
+			if index, ok := first[key]; ok {
+				duplicates[i] = index
+				continue
+			}
+			first[key] = i
+		}
+
+		if create.Id != "" {
+			if err = us.checkBulkId(create.Id, ids); err != nil {
+				results[i].Err = err
+				continue
+			}
+			ids[create.Id] = true
+		}
+
+		if err = us.resolveDestination(prepared.url, request.BaseUrl); err != nil {
+			results[i].Err = err
+			continue
+		}
+
+		pending = append(pending, bulkItem{index: i, url: prepared.url, domain: domain})
+	}
+	return pending, duplicates
+}
+
+// checkBulkId проверяет, что собственный id не занят ни в базе, ни другим элементом запроса
+func (us *UrlUseCase) checkBulkId(id string, requested map[string]bool) error {
+	if requested[id] {
+		return fmt.Errorf("%w: %s", url.ErrIdExists, id)
+	}
+	_, err := us.r.FindById(id)
+	switch {
+	case err == nil:
+		return fmt.Errorf("%w: %s", url.ErrIdExists, id)
+	case errors.Is(err, url.ErrNotFound):
+		return nil
+	default:
+		return err
+	}
+}
+
+// saveBulk сохраняет ссылки одной транзакцией и записывает результаты
+func (us *UrlUseCase) saveBulk(items []bulkItem, baseUrl string, results []dto.BulkCreateResult, limit url.Limit) error {
+	if len(items) == 0 {
+		return nil
+	}
+
+	models := make([]*url.Url, 0, len(items))
+	for _, item := range items {
+		models = append(models, item.url)
+	}
+	saved, err := us.saveManyWithin(models, limit)
+	if err != nil {
+		// SaveMany мог выдать id до отката, при повторном сохранении они генерируются заново
+		for _, item := range items {
+			if !item.url.CustomId {
+				item.url.Id = ""
+			}
+		}
+		return err
+	}
+
+	for i, item := range items {
+		results[item.index].Id = saved[i].Id
+		results[item.index].Url = us.b.Build(baseUrl, item.domain, saved[i].Id)
+		results[item.index].Created = true
+		us.enqueueMetadata(saved[i])
+	}
+	return nil
+}
+
+func (us *UrlUseCase) saveBulkOneByOne(items []bulkItem, baseUrl string, results []dto.BulkCreateResult, limit url.Limit) {
+	for _, item := range items {
+		saved, err := us.saveWithin(item.url, limit)
+		if err != nil {
+			results[item.index].Err = err
+			continue
+		}
+		results[item.index].Id = saved.Id
+		results[item.index].Url = us.b.Build(baseUrl, item.domain, saved.Id)
+		results[item.index].Created = true
+		us.enqueueMetadata(saved)
+	}
+}
+
+func hasErrors(results []dto.BulkCreateResult) bool {
+	for _, result := range results {
+		if result.Err != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// abortBulk отмечает элементы без собственной ошибки как несозданные.
+// cause - ошибка сохранения, если откатилась сама транзакция.
+func abortBulk(results []dto.BulkCreateResult, cause error) {
+	for i := range results {
+		if results[i].Err != nil {
+			continue
+		}
+		results[i].Id, results[i].Url, results[i].Created = "", "", false
+		results[i].Err = url.ErrBulkAborted
+		if cause != nil {
+			results[i].Err = fmt.Errorf("%w: %w", url.ErrBulkAborted, cause)
+		}
+	}
+}
+
+func transformBulkItem(item dto.BulkCreateItem, ownerId, baseUrl string) dto.CreateShortUrlWithCustomIdRequest {
+	return dto.CreateShortUrlWithCustomIdRequest{
+		Url:          item.Url,
+		Id:           item.Id,
+		Domain:       item.Domain,
+		Password:     item.Password,
+		Interstitial: item.Interstitial,
+		RedirectType: item.RedirectType,
+		QueryMode:    item.QueryMode,
+		ForwardPath:  item.ForwardPath,
+		Utm:          item.Utm,
+		Dedup:        item.Dedup,
+		OwnerId:      ownerId,
+		BaseUrl:      baseUrl,
+	}
+}
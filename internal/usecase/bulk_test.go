@@ -0,0 +1,145 @@
+package usecase
+
+import (
+	"errors"
+	"leenwood/yandex-http/config"
+	"leenwood/yandex-http/internal/domain/quota"
+	quotaMocks "leenwood/yandex-http/internal/domain/quota/mocks"
+	"leenwood/yandex-http/internal/domain/url"
+	"leenwood/yandex-http/internal/domain/url/mocks"
+	"leenwood/yandex-http/internal/usecase/dto"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Bulk creation", func() {
+	var (
+		ctrl       *gomock.Controller
+		mockRepo   *mocks.MockRepositoryInterface
+		mockQuotas *quotaMocks.MockRepositoryInterface
+		urlUseCase *UrlUseCase
+	)
+
+	// assignIds имитирует SaveMany, выдавая id по порядку
+	assignIds := func(urls []*url.Url) ([]*url.Url, error) {
+		for i, u := range urls {
+			if u.Id == "" {
+				u.Id = string(rune('a' + i))
+			}
+		}
+		return urls, nil
+	}
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		mockRepo = mocks.NewMockRepositoryInterface(ctrl)
+		mockQuotas = quotaMocks.NewMockRepositoryInterface(ctrl)
+		builder, err := NewShortUrlBuilder(config.AppConfig{Hostname: "localhost", Port: "8080"})
+		Expect(err).NotTo(HaveOccurred())
+		urlUseCase = &UrlUseCase{r: mockRepo, b: builder, p: quotaPolicy{r: mockRepo, q: mockQuotas, plan: config.QuotaConfig{AllowCustomId: true}}}
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	It("should report every item in input order", func() {
+		mockRepo.EXPECT().FindByDedupKey(gomock.Any()).Return(nil, nil).AnyTimes()
+		mockRepo.EXPECT().FindById("taken").Return(&url.Url{Id: "taken"}, nil)
+		mockRepo.EXPECT().FindById("promo").Return(nil, url.ErrNotFound)
+		mockRepo.EXPECT().SaveMany(gomock.Len(2)).DoAndReturn(assignIds)
+
+		response, err := urlUseCase.CreateShortUrls(dto.BulkCreateRequest{Items: []dto.BulkCreateItem{
+			{Url: "https://example.com/1"},
+			{Url: "https://example.com/2", Id: "taken"},
+			{Url: "https://example.com/1"},
+			{Url: "https://example.com/3", RedirectType: "999"},
+			{Url: "https://example.com/4", Id: "promo"},
+			{Url: ""},
+		}})
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(response.Created).To(Equal(2))
+		Expect(response.Failed).To(Equal(3))
+
+		results := response.Results
+		Expect(results[0]).To(Equal(dto.BulkCreateResult{Index: 0, Id: "a", Url: "http://localhost:8080/a", Created: true}))
+		Expect(results[1].Err).To(MatchError(url.ErrIdExists))
+		Expect(results[2]).To(Equal(dto.BulkCreateResult{Index: 2, Id: "a", Url: "http://localhost:8080/a"}))
+		Expect(results[3].Err).To(MatchError(url.ErrInvalidRedirectType))
+		Expect(results[4].Id).To(Equal("promo"))
+		Expect(results[5].Err).To(MatchError(url.ErrInvalidUrl))
+	})
+
+	It("should retry a failed batch one link at a time", func() {
+		mockRepo.EXPECT().FindByDedupKey(gomock.Any()).Return(nil, nil).AnyTimes()
+		mockRepo.EXPECT().SaveMany(gomock.Len(2)).Return(nil, errors.New("unique violation"))
+		mockRepo.EXPECT().Save(gomock.Any()).DoAndReturn(func(u *url.Url) (*url.Url, error) {
+			u.Id = "ok"
+			return u, nil
+		})
+		mockRepo.EXPECT().Save(gomock.Any()).Return(nil, url.ErrIdExists)
+
+		response, err := urlUseCase.CreateShortUrls(dto.BulkCreateRequest{Items: []dto.BulkCreateItem{
+			{Url: "https://example.com/1"},
+			{Url: "https://example.com/2"},
+		}})
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(response.Results[0].Id).To(Equal("ok"))
+		Expect(response.Results[1].Err).To(MatchError(url.ErrIdExists))
+	})
+
+	It("should create nothing in atomic mode when an item is invalid", func() {
+		mockRepo.EXPECT().FindByDedupKey("https://example.com/1").Return(nil, nil)
+
+		response, err := urlUseCase.CreateShortUrls(dto.BulkCreateRequest{Atomic: true, Items: []dto.BulkCreateItem{
+			{Url: "https://example.com/1"},
+			{Url: "https://example.com/2", QueryMode: "unknown"},
+		}})
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(response.Created).To(BeZero())
+		Expect(response.Failed).To(Equal(2))
+		Expect(response.Results[0].Err).To(MatchError(url.ErrBulkAborted))
+		Expect(response.Results[1].Err).To(MatchError(url.ErrInvalidQueryMode))
+	})
+
+	It("should create links until the quota is reached", func() {
+		limit := url.Limit{OwnerId: "alice", MaxLinks: 5}
+		exceeded := &url.LimitError{Limit: "links", Max: 5}
+		mockRepo.EXPECT().FindByDedupKey(gomock.Any()).Return(nil, nil).Times(3)
+		mockQuotas.EXPECT().FindByOwner("alice").Return(&quota.Quota{OwnerId: "alice", MaxLinks: 5}, nil)
+		gomock.InOrder(
+			mockRepo.EXPECT().SaveWithin(gomock.Len(3), limit).Return(nil, exceeded),
+			mockRepo.EXPECT().SaveWithin(gomock.Len(1), limit).DoAndReturn(func(models []*url.Url, _ url.Limit) ([]*url.Url, error) {
+				models[0].Id = "one"
+				return models, nil
+			}),
+			mockRepo.EXPECT().SaveWithin(gomock.Len(1), limit).DoAndReturn(func(models []*url.Url, _ url.Limit) ([]*url.Url, error) {
+				models[0].Id = "two"
+				return models, nil
+			}),
+			mockRepo.EXPECT().SaveWithin(gomock.Len(1), limit).Return(nil, exceeded),
+		)
+
+		response, err := urlUseCase.CreateShortUrls(dto.BulkCreateRequest{OwnerId: "alice", Items: []dto.BulkCreateItem{
+			{Url: "https://example.com/1"},
+			{Url: "https://example.com/2"},
+			{Url: "https://example.com/3"},
+		}})
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(response.Created).To(Equal(2))
+		Expect(response.Results[2].Err).To(MatchError(quota.ErrExceeded))
+		Expect(response.Results[2].Err.Error()).To(ContainSubstring("active links limit is 5"))
+	})
+
+	It("should reject oversized requests", func() {
+		_, err := urlUseCase.CreateShortUrls(dto.BulkCreateRequest{Items: make([]dto.BulkCreateItem, MaxBulkItems+1)})
+
+		Expect(err).To(MatchError(url.ErrTooManyItems))
+	})
+})
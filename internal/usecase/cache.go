@@ -0,0 +1,54 @@
+package usecase
+
+import (
+	"context"
+	"leenwood/yandex-http/config"
+	"leenwood/yandex-http/internal/domain/url"
+	"leenwood/yandex-http/internal/domain/url/cachedRepository"
+	"leenwood/yandex-http/internal/domain/url/postgresRepository"
+	"leenwood/yandex-http/internal/usecase/dto"
+)
+
+// NewUrlRepository создаёт репозиторий ссылок, общий для всех сценариев процесса. Ссылки по id читаются
+// через кэш, и изменение ссылки в любом сценарии сбрасывает запись, по которой выполняются перенаправления.
+// При нулевом размере кэша запросы идут сразу в базу, а вместо кэша возвращается nil.
+func NewUrlRepository(ctx context.Context, config config.Config) (url.RepositoryInterface, *cachedRepository.Cache, error) {
+	repository, err := postgresRepository.NewRepository(ctx, config.Database)
+	if err != nil {
+		return nil, nil, err
+	}
+	cached, urlCache := newCachedUrls(repository, config.Cache)
+	return cached, urlCache, nil
+}
+
+// newCachedUrls оборачивает репозиторий ссылок кэшем размера cfg.Size
+func newCachedUrls(repository url.RepositoryInterface, cfg config.CacheConfig) (url.RepositoryInterface, *cachedRepository.Cache) {
+	if cfg.Size <= 0 {
+		return repository, nil
+	}
+	urlCache := cachedRepository.NewCache(cfg)
+	return cachedRepository.NewRepository(repository, urlCache), urlCache
+}
+
+func (us *UrlUseCase) GetCacheStats() dto.CacheStatsResponse {
+	return transformToCacheStats(us.cache)
+}
+
+// transformToCacheStats переводит счётчики кэша в ответ, nil - кэш отключён
+func transformToCacheStats(cache *cachedRepository.Cache) dto.CacheStatsResponse {
+	if cache == nil {
+		return dto.CacheStatsResponse{}
+	}
+	stats := cache.Stats()
+	response := dto.CacheStatsResponse{
+		Enabled:   true,
+		Hits:      stats.Hits,
+		Misses:    stats.Misses,
+		Evictions: stats.Evictions,
+		Size:      stats.Size,
+	}
+	if total := stats.Hits + stats.Misses; total > 0 {
+		response.HitRatio = float64(stats.Hits) / float64(total)
+	}
+	return response
+}
@@ -0,0 +1,144 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"leenwood/yandex-http/config"
+	"leenwood/yandex-http/internal/domain/quota"
+	quotaMocks "leenwood/yandex-http/internal/domain/quota/mocks"
+	"leenwood/yandex-http/internal/domain/rule"
+	ruleMocks "leenwood/yandex-http/internal/domain/rule/mocks"
+	tagMocks "leenwood/yandex-http/internal/domain/tag/mocks"
+	"leenwood/yandex-http/internal/domain/url"
+	"leenwood/yandex-http/internal/domain/url/cachedRepository"
+	"leenwood/yandex-http/internal/domain/url/sqliteRepository"
+	"leenwood/yandex-http/internal/rateLimit/memoryStore"
+	"leenwood/yandex-http/internal/usecase/dto"
+	"os"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// urlsTable - таблица ссылок в том виде, который дают миграции
+const urlsTable = `CREATE TABLE urls (
+	id TEXT PRIMARY KEY, original_url TEXT NOT NULL, click_count INTEGER NOT NULL DEFAULT 0, created_date DATETIME NOT NULL,
+	domain TEXT NOT NULL DEFAULT '', owner_id TEXT NOT NULL DEFAULT '', creator_ip TEXT NOT NULL DEFAULT '', custom_id BOOLEAN NOT NULL DEFAULT FALSE,
+	password_hash TEXT NOT NULL DEFAULT '', interstitial BOOLEAN NOT NULL DEFAULT FALSE, redirect_type TEXT NOT NULL DEFAULT '',
+	query_mode TEXT NOT NULL DEFAULT '', forward_path BOOLEAN NOT NULL DEFAULT FALSE,
+	utm_source TEXT NOT NULL DEFAULT '', utm_medium TEXT NOT NULL DEFAULT '', utm_campaign TEXT NOT NULL DEFAULT '',
+	utm_term TEXT NOT NULL DEFAULT '', utm_content TEXT NOT NULL DEFAULT '', has_rules BOOLEAN NOT NULL DEFAULT FALSE,
+	split_mode TEXT NOT NULL DEFAULT '', title TEXT NOT NULL DEFAULT '', notes TEXT NOT NULL DEFAULT '', folder_id TEXT NOT NULL DEFAULT '',
+	meta_title TEXT NOT NULL DEFAULT '', meta_description TEXT NOT NULL DEFAULT '', meta_image TEXT NOT NULL DEFAULT '',
+	meta_site_name TEXT NOT NULL DEFAULT '', meta_favicon TEXT NOT NULL DEFAULT '',
+	health_status INTEGER NOT NULL DEFAULT 0, health_latency_ms INTEGER NOT NULL DEFAULT 0, health_final_url TEXT NOT NULL DEFAULT '',
+	health_error TEXT NOT NULL DEFAULT '', health_checked_date DATETIME NULL, resolved_url TEXT NOT NULL DEFAULT '', dedup_key TEXT NULL UNIQUE
+)`
+
+// newSqliteUrls создаёт таблицу ссылок в базе SQLite во временном каталоге и открывает её репозиторий
+func newSqliteUrls() *sqliteRepository.Repository {
+	// Репозиторий SQLite открывает файл базы в рабочем каталоге
+	workDir, err := os.Getwd()
+	Expect(err).NotTo(HaveOccurred())
+	Expect(os.Chdir(GinkgoT().TempDir())).To(Succeed())
+	DeferCleanup(os.Chdir, workDir)
+
+	db, err := sql.Open("sqlite3", "database.sqlite")
+	Expect(err).NotTo(HaveOccurred())
+	DeferCleanup(db.Close)
+	_, err = db.Exec(urlsTable)
+	Expect(err).NotTo(HaveOccurred())
+
+	repository, err := sqliteRepository.NewRepository(context.Background(), config.DatabaseConfig{})
+	Expect(err).NotTo(HaveOccurred())
+	return repository
+}
+
+var _ = Describe("Cached links", func() {
+	var (
+		ctrl       *gomock.Controller
+		database   *sqliteRepository.Repository
+		repository url.RepositoryInterface
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		database = newSqliteUrls()
+		repository = cachedRepository.NewRepository(database, cachedRepository.NewCache(config.CacheConfig{Size: 10, TTL: time.Minute}))
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	It("should redirect by rules right after they are replaced through the rule use case", func() {
+		ctx := context.Background()
+		cfg := config.Config{
+			App:   config.AppConfig{Hostname: "localhost", Port: "8080"},
+			Chain: config.ChainConfig{SelfLinks: "resolve", MaxHops: 5},
+			Cache: config.CacheConfig{Size: 10, TTL: time.Minute},
+		}
+		_, err := database.Save(&url.Url{Id: "app", OriginalUrl: "https://example.com", OwnerId: "acme"})
+		Expect(err).NotTo(HaveOccurred())
+
+		// Сценарии собираются так же, как в InitializationHandlers: с одним репозиторием ссылок и кэшем
+		urls, urlCache := newCachedUrls(database, cfg.Cache)
+		urlUseCase, err := NewUrlUseCase(ctx, cfg, memoryStore.NewStore(), urls, urlCache)
+		Expect(err).NotTo(HaveOccurred())
+		ruleUseCase, err := NewRuleUseCase(ctx, cfg, urls)
+		Expect(err).NotTo(HaveOccurred())
+
+		// Правила, переходы и квоты хранятся в Postgres, их заменяют заглушки
+		quotas := quotaMocks.NewMockRepositoryInterface(ctrl)
+		quotas.EXPECT().FindByOwner("acme").Return(nil, quota.ErrNotFound).AnyTimes()
+		quotas.EXPECT().IncrementMonthlyClicks("acme", gomock.Any()).Return(uint64(1), nil).AnyTimes()
+		rules := ruleMocks.NewMockRepositoryInterface(ctrl)
+		rules.EXPECT().Replace("app", gomock.Len(1)).Return(nil)
+		rules.EXPECT().FindByUrl("app").Return([]*rule.Rule{{UrlId: "app", Condition: rule.Condition{Os: []string{"ios"}}, TargetUrl: "https://apps.apple.com/app"}}, nil)
+		urlUseCase.clicks, urlUseCase.p.q, urlUseCase.rules = acceptClicks(ctrl), quotas, rules
+		ruleUseCase.rules = rules
+
+		iphone := dto.UrlClickRequest{Id: "app", UserAgent: "Mozilla/5.0 (iPhone; CPU iPhone OS 17_0 like Mac OS X) AppleWebKit/605.1.15 Mobile/15E148"}
+		response, err := urlUseCase.ClickUrl(iphone)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(response.Url).To(Equal("https://example.com"))
+
+		_, err = ruleUseCase.ReplaceRules(dto.ReplaceUrlRulesRequest{Id: "app", OwnerId: "acme", Rules: []dto.UrlRule{
+			{Condition: dto.RuleCondition{Os: []string{"ios"}}, TargetUrl: "https://apps.apple.com/app"},
+		}})
+		Expect(err).NotTo(HaveOccurred())
+
+		response, err = urlUseCase.ClickUrl(iphone)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(response.Url).To(Equal("https://apps.apple.com/app"))
+		Expect(response.Private).To(BeTrue())
+	})
+
+	It("should keep clicks counted after the link was cached when its details change", func() {
+		_, err := repository.Save(&url.Url{Id: "abc", OriginalUrl: "https://example.com", OwnerId: "acme"})
+		Expect(err).NotTo(HaveOccurred())
+
+		quotas := quotaMocks.NewMockRepositoryInterface(ctrl)
+		quotas.EXPECT().FindByOwner("acme").Return(nil, quota.ErrNotFound).AnyTimes()
+		quotas.EXPECT().IncrementMonthlyClicks("acme", gomock.Any()).Return(uint64(1), nil).AnyTimes()
+		urlUseCase := &UrlUseCase{r: repository, p: quotaPolicy{r: repository, q: quotas}, clicks: acceptClicks(ctrl), geo: noGeoIp, ua: agents}
+		tags := tagMocks.NewMockRepositoryInterface(ctrl)
+		tags.EXPECT().Replace("abc", []string{"promo"}).Return(nil)
+		tagUseCase := &TagUseCase{r: repository, tags: tags}
+
+		for range 2 {
+			_, err = urlUseCase.ClickUrl(dto.UrlClickRequest{Id: "abc"})
+			Expect(err).NotTo(HaveOccurred())
+		}
+		title := "Spring sale"
+		_, err = tagUseCase.UpdateUrlDetails(dto.UpdateUrlDetailsRequest{Id: "abc", OwnerId: "acme", Title: &title, Tags: &[]string{"promo"}})
+		Expect(err).NotTo(HaveOccurred())
+
+		stored, err := database.FindById("abc")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(stored.ClickCount).To(Equal(uint64(2)))
+		Expect(stored.Title).To(Equal("Spring sale"))
+	})
+})
@@ -0,0 +1,218 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"leenwood/yandex-http/config"
+	"leenwood/yandex-http/internal/domain/customDomain"
+	"leenwood/yandex-http/internal/domain/url"
+	"net/http"
+	neturl "net/url"
+	"strings"
+)
+
+const (
+	SelfLinksResolve = "resolve"
+	SelfLinksReject  = "reject"
+)
+
+// destinationResolver раскрывает адреса назначения, которые сами являются короткими ссылками:
+// ссылками этого сервиса и, если включено, ссылками внешних сокращателей
+type destinationResolver struct {
+	ctx context.Context
+	r   url.RepositoryInterface
+	d   customDomain.RepositoryInterface
+	b   ShortUrlBuilder
+	cfg config.ChainConfig
+	// client не следует перенаправлениям сам, nil если внешние цепочки не раскрываются
+	client     *http.Client
+	shorteners map[string]bool
+}
+
+func newDestinationResolver(ctx context.Context, r url.RepositoryInterface, d customDomain.RepositoryInterface, b ShortUrlBuilder, cfg config.ChainConfig, client *http.Client) (*destinationResolver, error) {
+	if cfg.SelfLinks != SelfLinksResolve && cfg.SelfLinks != SelfLinksReject {
+		return nil, fmt.Errorf("invalid SELF_LINKS: expected %s or %s, got %q", SelfLinksResolve, SelfLinksReject, cfg.SelfLinks)
+	}
+
+	resolver := &destinationResolver{ctx: ctx, r: r, d: d, b: b, cfg: cfg, shorteners: make(map[string]bool)}
+	for _, host := range cfg.ShortenerHosts {
+		resolver.shorteners[strings.ToLower(host)] = true
+	}
+	if cfg.ExternalHops > 0 && client != nil {
+		// Каждое перенаправление разбирается отдельно, чтобы считать шаги и замечать свои ссылки
+		resolver.client = &http.Client{
+			Transport: client.Transport,
+			Timeout:   client.Timeout,
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		}
+	}
+	return resolver, nil
+}
+
+// resolveDestination заполняет ResolvedUrl новой ссылки, если её адрес назначения - короткая ссылка
+func (us *UrlUseCase) resolveDestination(model *url.Url, requestBaseUrl string) error {
+	if us.chains == nil {
+		return nil
+	}
+	resolved, err := us.chains.resolve(model, requestBaseUrl)
+	if err != nil {
+		return err
+	}
+	if resolved != destinationUrl(model) {
+		model.ResolvedUrl = resolved
+	}
+	return nil
+}
+
+// resolve проходит цепочку от адреса назначения ссылки до конечного адреса. Свои ссылки
+// раскрываются без запросов по данным из базы, внешние - запросами к сокращателю.
+func (dr *destinationResolver) resolve(model *url.Url, requestBaseUrl string) (string, error) {
+	current := destinationUrl(model)
+	links := make(map[string]bool)
+	if model.Id != "" {
+		links[model.Domain+"/"+model.Id] = true
+	}
+	visited := map[string]bool{current: true}
+
+	for hops, external := 0, 0; ; {
+		link, suffix, query, err := dr.findOwnLink(current, requestBaseUrl)
+		if err != nil {
+			return "", err
+		}
+
+		var next string
+		switch {
+		case link != nil:
+			if dr.cfg.SelfLinks == SelfLinksReject {
+				return "", url.ErrSelfLink
+			}
+			if links[link.Domain+"/"+link.Id] {
+				return "", fmt.Errorf("%w: %s points back to a link of the chain", url.ErrRedirectLoop, current)
+			}
+			links[link.Domain+"/"+link.Id] = true
+			if next, err = linkTarget(link, suffix, query); err != nil {
+				return "", err
+			}
+		case dr.client != nil && external < dr.cfg.ExternalHops && dr.isShortener(current):
+			external++
+			// Недоступный сокращатель не мешает созданию ссылки, цепочка просто не раскрывается дальше
+			if next = dr.follow(current); next == "" {
+				return current, nil
+			}
+		default:
+			return current, nil
+		}
+
+		hops++
+		if hops > dr.cfg.MaxHops {
+			return "", fmt.Errorf("%w: more than %d hops", url.ErrRedirectLoop, dr.cfg.MaxHops)
+		}
+		if visited[next] {
+			return "", fmt.Errorf("%w: %s is visited twice", url.ErrRedirectLoop, next)
+		}
+		visited[next] = true
+		current = next
+	}
+}
+
+// findOwnLink находит короткую ссылку этого сервиса по адресу. suffix и query - продолжение
+// пути после идентификатора и параметры запроса. Для чужих адресов возвращает nil.
+func (dr *destinationResolver) findOwnLink(destination, requestBaseUrl string) (link *url.Url, suffix string, query neturl.Values, err error) {
+	parsed, err := neturl.Parse(destination)
+	if err != nil || parsed.Host == "" {
+		return nil, "", nil, nil
+	}
+
+	domain := ""
+	rest, own := "", false
+	for _, base := range dr.b.ownBaseUrls(requestBaseUrl) {
+		baseUrl, err := neturl.Parse(base)
+		if err != nil || !strings.EqualFold(baseUrl.Host, parsed.Host) {
+			continue
+		}
+		if rest, own = strings.CutPrefix(parsed.Path, strings.TrimRight(baseUrl.Path, "/")+"/"); own {
+			break
+		}
+	}
+	if !own {
+		hostname := strings.ToLower(parsed.Hostname())
+		if _, err = dr.d.FindByHostname(hostname); errors.Is(err, customDomain.ErrNotFound) {
+			return nil, "", nil, nil
+		} else if err != nil {
+			return nil, "", nil, err
+		}
+		domain, rest = hostname, strings.TrimPrefix(parsed.Path, "/")
+	}
+
+	id, path, found := strings.Cut(rest, "/")
+	if id == "" {
+		return nil, "", nil, fmt.Errorf("%w: %s is not a short link", url.ErrSelfLink, destination)
+	}
+	if found {
+		suffix = "/" + path
+	}
+
+	link, err = dr.r.FindById(id)
+	if errors.Is(err, url.ErrNotFound) || (err == nil && link.Domain != domain) {
+		return nil, "", nil, fmt.Errorf("%w: short link %s does not exist", url.ErrSelfLink, destination)
+	}
+	if err != nil {
+		return nil, "", nil, err
+	}
+	return link, suffix, parsed.Query(), nil
+}
+
+// linkTarget повторяет переход по своей ссылке. Ссылки, адрес перехода которых зависит
+// от посетителя или требует подтверждения, раскрыть нельзя.
+func linkTarget(link *url.Url, suffix string, query neturl.Values) (string, error) {
+	if link.IsProtected() || link.HasRules || link.SplitMode != "" || link.Interstitial {
+		return "", fmt.Errorf("%w: short link %s has its own redirect settings", url.ErrSelfLink, link.Id)
+	}
+
+	destination, err := applyUtm(destinationUrl(link), link.Utm)
+	if err != nil {
+		return "", err
+	}
+	destination, err = forwardRequest(destination, link, suffix, query)
+	if errors.Is(err, url.ErrNotFound) {
+		return "", fmt.Errorf("%w: short link %s does not forward paths", url.ErrSelfLink, link.Id)
+	}
+	return destination, err
+}
+
+func (dr *destinationResolver) isShortener(destination string) bool {
+	host := hostOf(destination)
+	return dr.shorteners[host] || dr.shorteners[strings.TrimPrefix(host, "www.")]
+}
+
+// follow делает один шаг по перенаправлению сокращателя, пустая строка - шага нет
+func (dr *destinationResolver) follow(destination string) string {
+	for _, method := range []string{http.MethodHead, http.MethodGet} {
+		request, err := http.NewRequestWithContext(dr.ctx, method, destination, nil)
+		if err != nil {
+			return ""
+		}
+		response, err := dr.client.Do(request)
+		if err != nil {
+			return ""
+		}
+		response.Body.Close()
+
+		// Не все сокращатели отвечают на HEAD
+		if response.StatusCode == http.StatusMethodNotAllowed {
+			continue
+		}
+		location, err := response.Location()
+		if err != nil || response.StatusCode < 300 || response.StatusCode > 399 {
+			return ""
+		}
+		if location.Scheme != "http" && location.Scheme != "https" {
+			return ""
+		}
+		return location.String()
+	}
+	return ""
+}
@@ -0,0 +1,150 @@
+package usecase
+
+import (
+	"context"
+	"leenwood/yandex-http/config"
+	"leenwood/yandex-http/internal/domain/customDomain"
+	domainMocks "leenwood/yandex-http/internal/domain/customDomain/mocks"
+	"leenwood/yandex-http/internal/domain/url"
+	"leenwood/yandex-http/internal/domain/url/mocks"
+	"leenwood/yandex-http/internal/usecase/dto"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Redirect chains", func() {
+	var (
+		ctrl        *gomock.Controller
+		mockRepo    *mocks.MockRepositoryInterface
+		mockDomains *domainMocks.MockRepositoryInterface
+		builder     ShortUrlBuilder
+		cfg         config.ChainConfig
+		urlUseCase  *UrlUseCase
+	)
+
+	useResolver := func(client *http.Client) {
+		resolver, err := newDestinationResolver(context.Background(), mockRepo, mockDomains, builder, cfg, client)
+		Expect(err).NotTo(HaveOccurred())
+		urlUseCase = &UrlUseCase{r: mockRepo, d: mockDomains, b: builder, chains: resolver}
+	}
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		mockRepo = mocks.NewMockRepositoryInterface(ctrl)
+		mockDomains = domainMocks.NewMockRepositoryInterface(ctrl)
+		var err error
+		builder, err = NewShortUrlBuilder(config.AppConfig{Hostname: "localhost", Port: "8080", PublicBaseUrl: "https://sho.rt"})
+		Expect(err).NotTo(HaveOccurred())
+		cfg = config.ChainConfig{SelfLinks: SelfLinksResolve, MaxHops: 3}
+		useResolver(nil)
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	It("should store the target of our own short link next to the original", func() {
+		mockRepo.EXPECT().FindByDedupKey("https://sho.rt/abc/docs").Return(nil, nil)
+		mockRepo.EXPECT().FindById("abc").Return(&url.Url{Id: "abc", OriginalUrl: "https://example.com", ForwardPath: true}, nil)
+		mockDomains.EXPECT().FindByHostname("example.com").Return(nil, customDomain.ErrNotFound)
+		mockRepo.EXPECT().Save(&url.Url{OriginalUrl: "https://sho.rt/abc/docs", ResolvedUrl: "https://example.com/docs", DedupKey: "https://sho.rt/abc/docs"}).
+			DoAndReturn(func(u *url.Url) (*url.Url, error) {
+				u.Id = "new"
+				return u, nil
+			})
+
+		_, err := urlUseCase.CreateShortUrl(dto.CreateShortUrlUseCaseRequest{Url: "https://sho.rt/abc/docs"})
+
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should resolve links of verified custom domains with their UTM tags", func() {
+		mockDomains.EXPECT().FindByHostname("go.acme.io").Return(&customDomain.Domain{Hostname: "go.acme.io", Verified: true}, nil)
+		mockRepo.EXPECT().FindById("promo").Return(&url.Url{Id: "promo", Domain: "go.acme.io", OriginalUrl: "https://acme.io/sale", Utm: url.Utm{Source: "qr"}}, nil)
+		mockDomains.EXPECT().FindByHostname("acme.io").Return(nil, customDomain.ErrNotFound)
+		model := &url.Url{OriginalUrl: "https://go.acme.io/promo"}
+
+		Expect(urlUseCase.resolveDestination(model, "")).To(Succeed())
+		Expect(model.ResolvedUrl).To(Equal("https://acme.io/sale?utm_source=qr"))
+	})
+
+	It("should leave other destinations untouched", func() {
+		mockDomains.EXPECT().FindByHostname("example.com").Return(nil, customDomain.ErrNotFound)
+		model := &url.Url{OriginalUrl: "example.com/page"}
+
+		Expect(urlUseCase.resolveDestination(model, "")).To(Succeed())
+		Expect(model.ResolvedUrl).To(BeEmpty())
+	})
+
+	It("should detect loops between existing links", func() {
+		mockRepo.EXPECT().FindById("a").Return(&url.Url{Id: "a", OriginalUrl: "https://sho.rt/b"}, nil)
+		mockRepo.EXPECT().FindById("b").Return(&url.Url{Id: "b", OriginalUrl: "https://sho.rt/a"}, nil)
+
+		err := urlUseCase.resolveDestination(&url.Url{OriginalUrl: "https://sho.rt/a"}, "")
+
+		Expect(err).To(MatchError(url.ErrRedirectLoop))
+	})
+
+	It("should reject links to missing or visitor-dependent short links", func() {
+		mockRepo.EXPECT().FindById("self").Return(nil, url.ErrNotFound)
+		err := urlUseCase.resolveDestination(&url.Url{Id: "self", CustomId: true, OriginalUrl: "https://sho.rt/self"}, "")
+		Expect(err).To(MatchError(url.ErrSelfLink))
+
+		mockRepo.EXPECT().FindById("secret").Return(&url.Url{Id: "secret", OriginalUrl: "https://example.com", PasswordHash: "hash"}, nil)
+		err = urlUseCase.resolveDestination(&url.Url{OriginalUrl: "http://localhost:8080/secret"}, "")
+		Expect(err).To(MatchError(url.ErrSelfLink))
+	})
+
+	It("should reject own links when configured", func() {
+		cfg.SelfLinks = SelfLinksReject
+		useResolver(nil)
+		mockRepo.EXPECT().FindById("abc").Return(&url.Url{Id: "abc", OriginalUrl: "https://example.com"}, nil)
+
+		err := urlUseCase.resolveDestination(&url.Url{OriginalUrl: "https://sho.rt/abc"}, "")
+
+		Expect(err).To(MatchError(url.ErrSelfLink))
+	})
+
+	Describe("external shorteners", func() {
+		var server *httptest.Server
+
+		BeforeEach(func() {
+			mux := http.NewServeMux()
+			mux.HandleFunc("/first", func(w http.ResponseWriter, r *http.Request) {
+				http.Redirect(w, r, "/second", http.StatusMovedPermanently)
+			})
+			mux.HandleFunc("/second", func(w http.ResponseWriter, r *http.Request) {
+				http.Redirect(w, r, "https://example.com/final", http.StatusFound)
+			})
+			server = httptest.NewServer(mux)
+			cfg.ShortenerHosts = []string{"127.0.0.1"}
+			mockDomains.EXPECT().FindByHostname(gomock.Any()).Return(nil, customDomain.ErrNotFound).AnyTimes()
+		})
+
+		AfterEach(func() {
+			server.Close()
+		})
+
+		It("should follow shortener redirects to the final target", func() {
+			cfg.ExternalHops = 5
+			useResolver(server.Client())
+			model := &url.Url{OriginalUrl: server.URL + "/first"}
+
+			Expect(urlUseCase.resolveDestination(model, "")).To(Succeed())
+			Expect(model.ResolvedUrl).To(Equal("https://example.com/final"))
+		})
+
+		It("should stop after the configured number of hops", func() {
+			cfg.ExternalHops = 1
+			useResolver(server.Client())
+			model := &url.Url{OriginalUrl: server.URL + "/first"}
+
+			Expect(urlUseCase.resolveDestination(model, "")).To(Succeed())
+			Expect(model.ResolvedUrl).To(Equal(server.URL + "/second"))
+		})
+	})
+})
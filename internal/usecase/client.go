@@ -0,0 +1,92 @@
+package usecase
+
+import (
+	"leenwood/yandex-http/internal/domain/click"
+	"leenwood/yandex-http/internal/domain/experiment"
+	"leenwood/yandex-http/internal/domain/rule"
+	"leenwood/yandex-http/internal/domain/url"
+	"leenwood/yandex-http/internal/geoIp"
+	"leenwood/yandex-http/internal/usecase/dto"
+	"leenwood/yandex-http/internal/userAgent"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// routeDestination возвращает адрес первого подошедшего правила ссылки, затем - варианта эксперимента,
+// а без них - адрес ссылки. Вариант возвращается, только если переход идёт по нему.
+func (us *UrlUseCase) routeDestination(u *url.Url, request dto.UrlClickRequest, agent userAgent.Client, location geoIp.Location) (string, *experiment.Variant, error) {
+	if u.HasRules {
+		rules, err := us.rules.FindByUrl(u.Id)
+		if err != nil {
+			return "", nil, err
+		}
+
+		if matched := rule.Match(rules, ruleClient(request, agent, location)); matched != nil {
+			return matched.TargetUrl, nil, nil
+		}
+	}
+
+	if u.SplitMode != url.SplitNone {
+		variants, err := us.variants.FindByUrl(u.Id)
+		if err != nil {
+			return "", nil, err
+		}
+		if variant := pickVariant(u, variants, request); variant != nil {
+			return variant.TargetUrl, variant, nil
+		}
+	}
+
+	return destinationUrl(u), nil, nil
+}
+
+// recordClick сохраняет переход с источником, местоположением, сетью и устройством посетителя
+func (us *UrlUseCase) recordClick(u *url.Url, request dto.UrlClickRequest, agent userAgent.Client, location geoIp.Location) error {
+	return us.clicks.Save(&click.Click{
+		UrlId:       u.Id,
+		CreatedDate: time.Now(),
+		Country:     location.Country,
+		Region:      location.Region,
+		Asn:         location.Asn,
+		Browser:     agent.Browser,
+		Os:          agent.Os,
+		Device:      agent.Device,
+		Source:      request.Source,
+	})
+}
+
+// ruleClient собирает атрибуты запроса для проверки условий правил
+func ruleClient(request dto.UrlClickRequest, agent userAgent.Client, location geoIp.Location) rule.Client {
+	return rule.Client{
+		Device:   agent.Device,
+		Os:       strings.ToLower(agent.Os),
+		Language: preferredLanguage(request.AcceptLanguage),
+		Country:  location.Country,
+		Query:    request.Query,
+		Time:     time.Now(),
+	}
+}
+
+// preferredLanguage возвращает язык с наибольшим весом из Accept-Language в нижнем регистре
+func preferredLanguage(header string) string {
+	var (
+		language string
+		best     float64
+	)
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		weight := 1.0
+		if value, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+			weight = parsed
+		}
+		if tag == "" || tag == "*" || weight <= best {
+			continue
+		}
+		language, best = strings.ToLower(tag), weight
+	}
+	return language
+}
@@ -0,0 +1,57 @@
+package usecase
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"leenwood/yandex-http/internal/domain/url"
+	"time"
+)
+
+// listCursor - содержимое курсора списка ссылок. Клиенту он передаётся
+// непрозрачной строкой, чтобы формат можно было менять.
+type listCursor struct {
+	// Sort - порядок списка, в котором выдан курсор
+	Sort        string    `json:"s,omitempty"`
+	CreatedDate time.Time `json:"t"`
+	ClickCount  uint64    `json:"c,omitempty"`
+	Id          string    `json:"id"`
+	// Backward - курсор ведёт к окну перед ссылкой
+	Backward bool `json:"b,omitempty"`
+}
+
+func encodeListCursor(u *url.Url, sort url.Sort, backward bool) string {
+	data, _ := json.Marshal(listCursor{
+		Sort:        cursorSort(sort),
+		CreatedDate: u.CreatedDate,
+		ClickCount:  u.ClickCount,
+		Id:          u.Id,
+		Backward:    backward,
+	})
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decodeListCursor разбирает курсор. Курсор другого порядка списка не подходит:
+// его позиция не имеет смысла при новой сортировке.
+func decodeListCursor(value string, sort url.Sort) (url.Cursor, bool, error) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return url.Cursor{}, false, url.ErrInvalidCursor
+	}
+
+	var cursor listCursor
+	if err = json.Unmarshal(data, &cursor); err != nil || cursor.Id == "" || cursor.Sort != cursorSort(sort) {
+		return url.Cursor{}, false, url.ErrInvalidCursor
+	}
+	return url.Cursor{CreatedDate: cursor.CreatedDate, ClickCount: cursor.ClickCount, Id: cursor.Id}, cursor.Backward, nil
+}
+
+func cursorSort(sort url.Sort) string {
+	value := string(sort.Field)
+	if value == "" {
+		value = string(url.SortCreatedDate)
+	}
+	if sort.Desc {
+		value = "-" + value
+	}
+	return value
+}
@@ -0,0 +1,142 @@
+package usecase
+
+import (
+	"leenwood/yandex-http/config"
+	"leenwood/yandex-http/internal/domain/quota"
+	quotaMocks "leenwood/yandex-http/internal/domain/quota/mocks"
+	"leenwood/yandex-http/internal/domain/url"
+	"leenwood/yandex-http/internal/domain/url/mocks"
+	"leenwood/yandex-http/internal/usecase/dto"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Link reuse", func() {
+	var (
+		ctrl       *gomock.Controller
+		mockRepo   *mocks.MockRepositoryInterface
+		urlUseCase *UrlUseCase
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		mockRepo = mocks.NewMockRepositoryInterface(ctrl)
+		mockQuotas := quotaMocks.NewMockRepositoryInterface(ctrl)
+		mockQuotas.EXPECT().FindByOwner(gomock.Any()).Return(nil, quota.ErrNotFound).AnyTimes()
+		builder, err := NewShortUrlBuilder(config.AppConfig{Hostname: "localhost", Port: "8080"})
+		Expect(err).NotTo(HaveOccurred())
+		urlUseCase = &UrlUseCase{r: mockRepo, b: builder, p: quotaPolicy{r: mockRepo, q: mockQuotas, plan: config.QuotaConfig{AllowCustomId: true}}}
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	It("should normalize the destination in the key", func() {
+		Expect(url.Normalize("Example.COM")).To(Equal("https://example.com/"))
+		Expect(url.Normalize("HTTP://example.com:80/Docs?q=1")).To(Equal("http://example.com/Docs?q=1"))
+		Expect(url.Normalize("https://example.com:8443")).To(Equal("https://example.com:8443/"))
+		Expect(url.DedupKey(url.DedupReuse, "go.acme.io", "alice", "example.com")).To(Equal("go.acme.io https://example.com/"))
+		Expect(url.DedupKey(url.DedupOwner, "", "alice", "example.com")).To(Equal("owner:alice https://example.com/"))
+		Expect(url.DedupKey(url.DedupNew, "", "alice", "example.com")).To(BeEmpty())
+	})
+
+	It("should reuse a link to the same normalized address", func() {
+		mockRepo.EXPECT().FindByDedupKey("https://example.com/").Return(&url.Url{Id: "old", ClickCount: 3}, nil)
+
+		response, err := urlUseCase.CreateShortUrl(dto.CreateShortUrlUseCaseRequest{Url: "EXAMPLE.com"})
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(response.Url).To(Equal("http://localhost:8080/old"))
+		Expect(response.ClickCount).To(Equal(uint64(3)))
+	})
+
+	It("should honor the requested id even if the address is already shortened", func() {
+		saved := &url.Url{Id: "bio", OriginalUrl: "https://example.com", CustomId: true}
+		mockRepo.EXPECT().Save(&url.Url{Id: "bio", OriginalUrl: "https://example.com", CustomId: true}).Return(saved, nil)
+
+		response, err := urlUseCase.CreateShortUrlWithCustomId(dto.CreateShortUrlWithCustomIdRequest{Url: "https://example.com", Id: "bio"})
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(response.Url).To(Equal("http://localhost:8080/bio"))
+	})
+
+	It("should report a conflict when the requested id is taken", func() {
+		mockRepo.EXPECT().Save(gomock.Any()).Return(nil, url.ErrIdExists)
+
+		_, err := urlUseCase.CreateShortUrlWithCustomId(dto.CreateShortUrlWithCustomIdRequest{Url: "https://example.com", Id: "bio"})
+
+		Expect(err).To(MatchError(url.ErrIdExists))
+	})
+
+	It("should always create a new link with the new policy", func() {
+		mockRepo.EXPECT().Save(&url.Url{OriginalUrl: "https://example.com"}).Return(&url.Url{Id: "fresh"}, nil)
+
+		response, err := urlUseCase.CreateShortUrl(dto.CreateShortUrlUseCaseRequest{Url: "https://example.com", Dedup: "new"})
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(response.Url).To(Equal("http://localhost:8080/fresh"))
+	})
+
+	Context("with the owner policy", func() {
+		It("should reuse a shared link created by the same owner", func() {
+			mockRepo.EXPECT().FindByDedupKey("owner:alice https://example.com/").Return(nil, nil)
+			mockRepo.EXPECT().FindByDedupKey("https://example.com/").Return(&url.Url{Id: "mine", OwnerId: "alice"}, nil)
+
+			response, err := urlUseCase.CreateShortUrl(dto.CreateShortUrlUseCaseRequest{Url: "https://example.com", Dedup: "owner", OwnerId: "alice"})
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(response.Url).To(Equal("http://localhost:8080/mine"))
+		})
+
+		It("should not reuse a link of another owner", func() {
+			mockRepo.EXPECT().FindByDedupKey("owner:alice https://example.com/").Return(nil, nil)
+			mockRepo.EXPECT().FindByDedupKey("https://example.com/").Return(&url.Url{Id: "theirs", OwnerId: "bob"}, nil)
+			mockRepo.EXPECT().Save(&url.Url{OriginalUrl: "https://example.com", OwnerId: "alice", DedupKey: "owner:alice https://example.com/"}).
+				Return(&url.Url{Id: "mine"}, nil)
+
+			response, err := urlUseCase.CreateShortUrl(dto.CreateShortUrlUseCaseRequest{Url: "https://example.com", Dedup: "owner", OwnerId: "alice"})
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(response.Url).To(Equal("http://localhost:8080/mine"))
+		})
+	})
+
+	It("should return the link saved by a concurrent request", func() {
+		gomock.InOrder(
+			mockRepo.EXPECT().FindByDedupKey("https://example.com/").Return(nil, nil),
+			mockRepo.EXPECT().Save(gomock.Any()).Return(nil, url.ErrUrlExists),
+			mockRepo.EXPECT().FindByDedupKey("https://example.com/").Return(&url.Url{Id: "raced"}, nil),
+		)
+
+		response, err := urlUseCase.CreateShortUrl(dto.CreateShortUrlUseCaseRequest{Url: "https://example.com"})
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(response.Url).To(Equal("http://localhost:8080/raced"))
+	})
+
+	It("should reject an unknown policy", func() {
+		_, err := urlUseCase.CreateShortUrl(dto.CreateShortUrlUseCaseRequest{Url: "https://example.com", Dedup: "sometimes"})
+
+		Expect(err).To(MatchError(url.ErrInvalidDedup))
+	})
+
+	It("should create every bulk item with the new policy", func() {
+		mockRepo.EXPECT().SaveMany(gomock.Len(2)).DoAndReturn(func(urls []*url.Url) ([]*url.Url, error) {
+			urls[0].Id, urls[1].Id = "a", "b"
+			return urls, nil
+		})
+
+		response, err := urlUseCase.CreateShortUrls(dto.BulkCreateRequest{Items: []dto.BulkCreateItem{
+			{Url: "https://example.com", Dedup: "new"},
+			{Url: "https://example.com", Dedup: "new"},
+		}})
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(response.Created).To(Equal(2))
+		Expect(response.Results[0].Id).To(Equal("a"))
+		Expect(response.Results[1].Id).To(Equal("b"))
+	})
+})
@@ -0,0 +1,95 @@
+package usecase
+
+import (
+	"leenwood/yandex-http/internal/domain/url"
+	neturl "net/url"
+	"path"
+	"strings"
+)
+
+const (
+	SafetyOk       = "ok"
+	SafetyInsecure = "insecure"
+	SafetyUnsafe   = "unsafe"
+)
+
+// destinationUrl возвращает адрес перехода, дополняя его схемой https при отсутствии схемы.
+// Если адрес назначения был короткой ссылкой, переход ведёт сразу на её конечный адрес.
+func destinationUrl(u *url.Url) string {
+	if u.ResolvedUrl != "" {
+		return u.ResolvedUrl
+	}
+	if !strings.HasPrefix(u.OriginalUrl, "http://") && !strings.HasPrefix(u.OriginalUrl, "https://") {
+		return "https://" + u.OriginalUrl
+	}
+	return u.OriginalUrl
+}
+
+// forwardRequest дописывает к адресу перехода продолжение пути и параметры запроса
+// согласно настройкам ссылки. Продолжение пути для ссылки без ForwardPath - ErrNotFound.
+func forwardRequest(destination string, u *url.Url, suffix string, query neturl.Values) (string, error) {
+	if suffix == "/" && !u.ForwardPath {
+		suffix = ""
+	}
+	if suffix != "" && !u.ForwardPath {
+		return "", url.ErrNotFound
+	}
+	if suffix == "" && (u.QueryMode == url.QueryDrop || len(query) == 0) {
+		return destination, nil
+	}
+
+	parsed, err := neturl.Parse(destination)
+	if err != nil {
+		return "", err
+	}
+
+	if suffix != "" {
+		// Clean не даёт выйти за пределы пути ссылки через "..", завершающий слэш сохраняется
+		cleaned := path.Clean("/" + suffix)
+		if strings.HasSuffix(suffix, "/") && cleaned != "/" {
+			cleaned += "/"
+		}
+		parsed.Path = strings.TrimSuffix(parsed.Path, "/") + cleaned
+		parsed.RawPath = ""
+	}
+
+	if u.QueryMode != url.QueryDrop && len(query) > 0 {
+		parsed.RawQuery = mergeQuery(parsed.Query(), query, u.QueryMode).Encode()
+	}
+	return parsed.String(), nil
+}
+
+// mergeQuery переносит параметры запроса в параметры адреса перехода
+func mergeQuery(destination, incoming neturl.Values, mode url.QueryMode) neturl.Values {
+	for key, values := range incoming {
+		_, exists := destination[key]
+		switch {
+		case mode == url.QueryAppend:
+			destination[key] = append(destination[key], values...)
+		case mode == url.QueryOverride || !exists:
+			destination[key] = values
+		}
+	}
+	return destination
+}
+
+// isExternal сообщает, что адрес ведёт на хост, отличный от хоста короткой ссылки
+func isExternal(destination, host string) bool {
+	parsed, err := neturl.Parse(destination)
+	if err != nil {
+		return true
+	}
+	return host == "" || !strings.EqualFold(parsed.Hostname(), host)
+}
+
+// safetyStatus даёт простую оценку адреса для страницы предпросмотра
+func safetyStatus(destination string) string {
+	parsed, err := neturl.Parse(destination)
+	if err != nil || parsed.Host == "" || parsed.User != nil {
+		return SafetyUnsafe
+	}
+	if parsed.Scheme == "http" {
+		return SafetyInsecure
+	}
+	return SafetyOk
+}
@@ -0,0 +1,128 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"leenwood/yandex-http/config"
+	"leenwood/yandex-http/internal/domain/customDomain"
+	"leenwood/yandex-http/internal/domain/customDomain/postgresRepository"
+	"leenwood/yandex-http/internal/usecase/dto"
+	"net"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var hostnamePattern = regexp.MustCompile(`^([a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?\.)+[a-z]{2,63}$`)
+
+type DomainUseCaseInterface interface {
+	RegisterDomain(request dto.RegisterDomainRequest) (dto.DomainResponse, error)
+	GetDomainList() ([]dto.DomainResponse, error)
+	VerifyDomain(request dto.VerifyDomainRequest) (dto.DomainResponse, error)
+}
+
+type DomainUseCase struct {
+	r        customDomain.RepositoryInterface
+	resolver customDomain.ResolverInterface
+	ctx      context.Context
+}
+
+func NewDomainUseCase(ctx context.Context, config config.Config) (*DomainUseCase, error) {
+	repository, err := postgresRepository.NewRepository(ctx, config.Database)
+	if err != nil {
+		return nil, err
+	}
+	return &DomainUseCase{r: repository, resolver: net.DefaultResolver, ctx: ctx}, nil
+}
+
+func (ds *DomainUseCase) RegisterDomain(request dto.RegisterDomainRequest) (dto.DomainResponse, error) {
+	hostname := strings.ToLower(strings.TrimSpace(request.Hostname))
+	if !hostnamePattern.MatchString(hostname) {
+		return dto.DomainResponse{}, customDomain.ErrInvalidHostname
+	}
+
+	scheme := strings.ToLower(request.Scheme)
+	if scheme == "" {
+		scheme = "https"
+	}
+	if scheme != "http" && scheme != "https" {
+		return dto.DomainResponse{}, customDomain.ErrInvalidHostname
+	}
+
+	domain, err := ds.r.Save(&customDomain.Domain{
+		Hostname:          hostname,
+		Scheme:            scheme,
+		VerificationToken: uuid.New().String(),
+	})
+	if err != nil {
+		return dto.DomainResponse{}, err
+	}
+
+	return transformToDomainResponse(domain), nil
+}
+
+func (ds *DomainUseCase) GetDomainList() ([]dto.DomainResponse, error) {
+	domains, err := ds.r.FindAll()
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]dto.DomainResponse, 0, len(domains))
+	for _, domain := range domains {
+		result = append(result, transformToDomainResponse(domain))
+	}
+	return result, nil
+}
+
+// VerifyDomain ищет TXT-запись с токеном подтверждения и помечает домен подтверждённым
+func (ds *DomainUseCase) VerifyDomain(request dto.VerifyDomainRequest) (dto.DomainResponse, error) {
+	domain, err := ds.r.FindByHostname(strings.ToLower(request.Hostname))
+	if err != nil {
+		return dto.DomainResponse{}, err
+	}
+
+	if domain.Verified {
+		return transformToDomainResponse(domain), nil
+	}
+
+	records, err := ds.resolver.LookupTXT(ds.ctx, domain.VerificationRecordName())
+	if err != nil {
+		var dnsErr *net.DNSError
+		if errors.As(err, &dnsErr) && dnsErr.IsNotFound {
+			return dto.DomainResponse{}, customDomain.ErrVerificationFailed
+		}
+		return dto.DomainResponse{}, err
+	}
+
+	expected := domain.VerificationRecordValue()
+	for _, record := range records {
+		if strings.TrimSpace(record) != expected {
+			continue
+		}
+
+		verifiedDate := time.Now()
+		domain.Verified = true
+		domain.VerifiedDate = &verifiedDate
+		domain, err = ds.r.Update(domain)
+		if err != nil {
+			return dto.DomainResponse{}, err
+		}
+		return transformToDomainResponse(domain), nil
+	}
+
+	return dto.DomainResponse{}, customDomain.ErrVerificationFailed
+}
+
+func transformToDomainResponse(domain *customDomain.Domain) dto.DomainResponse {
+	return dto.DomainResponse{
+		Hostname:           domain.Hostname,
+		Scheme:             domain.Scheme,
+		Verified:           domain.Verified,
+		VerifiedDate:       domain.VerifiedDate,
+		VerificationRecord: domain.VerificationRecordName(),
+		VerificationValue:  domain.VerificationRecordValue(),
+		CreatedDate:        domain.CreatedDate,
+	}
+}
@@ -0,0 +1,105 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"leenwood/yandex-http/internal/domain/customDomain"
+	"leenwood/yandex-http/internal/domain/customDomain/mocks"
+	"leenwood/yandex-http/internal/usecase/dto"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("DomainUseCase", func() {
+	var (
+		ctrl          *gomock.Controller
+		mockRepo      *mocks.MockRepositoryInterface
+		mockResolver  *mocks.MockResolverInterface
+		domainUseCase DomainUseCaseInterface
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		mockRepo = mocks.NewMockRepositoryInterface(ctrl)
+		mockResolver = mocks.NewMockResolverInterface(ctrl)
+		domainUseCase = &DomainUseCase{r: mockRepo, resolver: mockResolver, ctx: context.Background()}
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	Describe("RegisterDomain", func() {
+		Context("when the hostname is valid", func() {
+			It("should save the domain with a verification token", func() {
+				mockRepo.EXPECT().Save(gomock.Any()).DoAndReturn(func(domain *customDomain.Domain) (*customDomain.Domain, error) {
+					Expect(domain.Hostname).To(Equal("go.acme.io"))
+					Expect(domain.Scheme).To(Equal("https"))
+					Expect(domain.VerificationToken).NotTo(BeEmpty())
+					return domain, nil
+				})
+
+				response, err := domainUseCase.RegisterDomain(dto.RegisterDomainRequest{Hostname: " GO.acme.io "})
+
+				Expect(err).NotTo(HaveOccurred())
+				Expect(response.Verified).To(BeFalse())
+				Expect(response.VerificationRecord).To(Equal("_yandex-http.go.acme.io"))
+			})
+		})
+
+		Context("when the hostname is invalid", func() {
+			It("should return an error", func() {
+				_, err := domainUseCase.RegisterDomain(dto.RegisterDomainRequest{Hostname: "https://go.acme.io/path"})
+
+				Expect(err).To(MatchError(customDomain.ErrInvalidHostname))
+			})
+		})
+	})
+
+	Describe("VerifyDomain", func() {
+		var domain *customDomain.Domain
+
+		BeforeEach(func() {
+			domain = &customDomain.Domain{Hostname: "go.acme.io", Scheme: "https", VerificationToken: "token"}
+			mockRepo.EXPECT().FindByHostname("go.acme.io").Return(domain, nil)
+		})
+
+		Context("when the TXT record matches", func() {
+			It("should mark the domain as verified", func() {
+				mockResolver.EXPECT().LookupTXT(gomock.Any(), "_yandex-http.go.acme.io").
+					Return([]string{"other", "yandex-http-verification=token"}, nil)
+				mockRepo.EXPECT().Update(gomock.Any()).DoAndReturn(func(d *customDomain.Domain) (*customDomain.Domain, error) {
+					return d, nil
+				})
+
+				response, err := domainUseCase.VerifyDomain(dto.VerifyDomainRequest{Hostname: "go.acme.io"})
+
+				Expect(err).NotTo(HaveOccurred())
+				Expect(response.Verified).To(BeTrue())
+				Expect(response.VerifiedDate).NotTo(BeNil())
+			})
+		})
+
+		Context("when the TXT record is missing", func() {
+			It("should return a verification error", func() {
+				mockResolver.EXPECT().LookupTXT(gomock.Any(), "_yandex-http.go.acme.io").Return([]string{"other"}, nil)
+
+				_, err := domainUseCase.VerifyDomain(dto.VerifyDomainRequest{Hostname: "go.acme.io"})
+
+				Expect(err).To(MatchError(customDomain.ErrVerificationFailed))
+			})
+		})
+
+		Context("when the lookup fails", func() {
+			It("should return the resolver error", func() {
+				mockResolver.EXPECT().LookupTXT(gomock.Any(), gomock.Any()).Return(nil, errors.New("dns error"))
+
+				_, err := domainUseCase.VerifyDomain(dto.VerifyDomainRequest{Hostname: "go.acme.io"})
+
+				Expect(err).To(MatchError("dns error"))
+			})
+		})
+	})
+})
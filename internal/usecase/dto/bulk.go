@@ -0,0 +1,43 @@
+package dto
+
+// BulkCreateItem - ссылка в запросе массового создания. В отличие от формы создания
+// пароль принимается из JSON, потому что элементы приходят только в теле запроса.
+type BulkCreateItem struct {
+	Url          string `json:"url"`
+	Id           string `json:"id"`
+	Domain       string `json:"domain"`
+	Password     string `json:"password"`
+	Interstitial bool   `json:"interstitial"`
+	RedirectType string `json:"redirect_type"`
+	QueryMode    string `json:"query_mode"`
+	ForwardPath  bool   `json:"forward_path"`
+	Utm
+	Dedup string `json:"dedup"`
+}
+
+type BulkCreateRequest struct {
+	Items []BulkCreateItem
+	// Atomic - создать все ссылки или ни одной
+	Atomic  bool
+	OwnerId string
+	BaseUrl string
+}
+
+// BulkCreateResult - результат элемента в порядке запроса
+type BulkCreateResult struct {
+	Index int    `json:"index"`
+	Id    string `json:"id,omitempty"`
+	Url   string `json:"url,omitempty"`
+	// Created - ссылка создана, а не найдена среди существующих
+	Created bool `json:"created"`
+	// Err - ошибка элемента, Error и Status по ней заполняет обработчик
+	Err    error  `json:"-"`
+	Error  string `json:"error,omitempty"`
+	Status int    `json:"status"`
+}
+
+type BulkCreateResponse struct {
+	Created int                `json:"created"`
+	Failed  int                `json:"failed"`
+	Results []BulkCreateResult `json:"results"`
+}
@@ -0,0 +1,11 @@
+package dto
+
+// CacheStatsResponse - счётчики кэша ссылок процесса с момента запуска
+type CacheStatsResponse struct {
+	Enabled   bool    `json:"enabled"`
+	Hits      uint64  `json:"hits"`
+	Misses    uint64  `json:"misses"`
+	Evictions uint64  `json:"evictions"`
+	Size      int     `json:"size"`
+	HitRatio  float64 `json:"hit_ratio"`
+}
@@ -0,0 +1,22 @@
+package dto
+
+import "time"
+
+type RegisterDomainRequest struct {
+	Hostname string `form:"hostname" json:"hostname" binding:"required"`
+	Scheme   string `form:"scheme" json:"scheme"`
+}
+
+type VerifyDomainRequest struct {
+	Hostname string
+}
+
+type DomainResponse struct {
+	Hostname           string     `json:"hostname"`
+	Scheme             string     `json:"scheme"`
+	Verified           bool       `json:"verified"`
+	VerifiedDate       *time.Time `json:"verified_date,omitempty"`
+	VerificationRecord string     `json:"verification_record"`
+	VerificationValue  string     `json:"verification_value"`
+	CreatedDate        time.Time  `json:"created_date"`
+}
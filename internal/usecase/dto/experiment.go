@@ -0,0 +1,40 @@
+package dto
+
+type ExperimentVariant struct {
+	Name       string `json:"name"`
+	TargetUrl  string `json:"target_url"`
+	Weight     int    `json:"weight"`
+	ClickCount uint64 `json:"click_count"`
+}
+
+type ExperimentRequest struct {
+	Id      string
+	OwnerId string
+}
+
+type CreateExperimentRequest struct {
+	Id        string              `json:"-"`
+	OwnerId   string              `json:"-"`
+	SplitMode string              `json:"split_mode"`
+	Variants  []ExperimentVariant `json:"variants"`
+}
+
+// UpdateExperimentRequest - новые веса вариантов по имени, неуказанные варианты не меняются
+type UpdateExperimentRequest struct {
+	Id        string         `json:"-"`
+	OwnerId   string         `json:"-"`
+	SplitMode string         `json:"split_mode"`
+	Weights   map[string]int `json:"weights"`
+}
+
+type PromoteVariantRequest struct {
+	Id      string `json:"-"`
+	OwnerId string `json:"-"`
+	Variant string `json:"variant"`
+}
+
+type ExperimentResponse struct {
+	Id        string              `json:"id"`
+	SplitMode string              `json:"split_mode"`
+	Variants  []ExperimentVariant `json:"variants"`
+}
@@ -0,0 +1,55 @@
+package dto
+
+import "time"
+
+type CreateFolderRequest struct {
+	OwnerId  string `json:"-"`
+	Name     string `json:"name" binding:"required"`
+	ParentId string `json:"parent_id"`
+}
+
+// UpdateFolderRequest переименовывает или перемещает папку, незаданные поля не меняются.
+// Пустой ParentId переносит папку на верхний уровень.
+type UpdateFolderRequest struct {
+	Id       string  `json:"-"`
+	OwnerId  string  `json:"-"`
+	Name     *string `json:"name"`
+	ParentId *string `json:"parent_id"`
+}
+
+type FolderRequest struct {
+	Id      string
+	OwnerId string
+}
+
+type FoldersRequest struct {
+	OwnerId string
+}
+
+type FolderResponse struct {
+	Id          string    `json:"id"`
+	ParentId    string    `json:"parent_id,omitempty"`
+	Name        string    `json:"name"`
+	CreatedDate time.Time `json:"created_date"`
+}
+
+type FoldersResponse struct {
+	Folders []FolderResponse `json:"folders"`
+}
+
+// MoveUrlRequest кладёт ссылку в папку, пустой FolderId убирает её из папки
+type MoveUrlRequest struct {
+	Id       string `json:"-"`
+	OwnerId  string `json:"-"`
+	FolderId string `json:"folder_id"`
+}
+
+type FolderStatsRequest struct {
+	Id string `form:"-"`
+	StatsRequest
+}
+
+type UrlFolderResponse struct {
+	Id       string `json:"id"`
+	FolderId string `json:"folder_id"`
+}
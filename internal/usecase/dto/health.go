@@ -0,0 +1,31 @@
+package dto
+
+import "time"
+
+type BrokenLinksRequest struct {
+	OwnerId string `form:"-"`
+	Limit   int    `form:"limit"`
+	Page    int    `form:"page"`
+}
+
+// UrlHealthResponse - результат последней проверки страницы назначения
+type UrlHealthResponse struct {
+	Status    int       `json:"status"`
+	LatencyMs int64     `json:"latency_ms"`
+	FinalUrl  string    `json:"final_url,omitempty"`
+	Error     string    `json:"error,omitempty"`
+	Broken    bool      `json:"broken"`
+	CheckedAt time.Time `json:"checked_at"`
+}
+
+type BrokenLinkResponse struct {
+	Id          string            `json:"id"`
+	OriginalUrl string            `json:"original_url"`
+	Title       string            `json:"title,omitempty"`
+	Health      UrlHealthResponse `json:"health"`
+}
+
+type BrokenLinksResponse struct {
+	Data  []BrokenLinkResponse `json:"data"`
+	Total uint64               `json:"total"`
+}
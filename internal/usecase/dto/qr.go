@@ -0,0 +1,25 @@
+package dto
+
+type UrlQrRequest struct {
+	Id      string `form:"-"`
+	OwnerId string `form:"-"`
+	BaseUrl string `form:"-"`
+	// Format - png или svg
+	Format string `form:"format"`
+	// Size - сторона изображения в пикселях
+	Size int `form:"size"`
+	// Level - уровень коррекции ошибок: L, M, Q или H
+	Level string `form:"level"`
+	// Margin - ширина поля в модулях, без параметра используется поле по умолчанию
+	Margin     *int   `form:"margin"`
+	Foreground string `form:"fg"`
+	Background string `form:"bg"`
+	// Logo размещает в центре кода логотип из настроек сервиса
+	Logo bool `form:"logo"`
+}
+
+type UrlQrResponse struct {
+	ContentType string
+	Body        []byte
+	ETag        string
+}
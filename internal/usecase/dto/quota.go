@@ -0,0 +1,25 @@
+package dto
+
+type UsageRequest struct {
+	OwnerId string
+}
+
+type QuotaLimits struct {
+	MaxLinks         uint64 `json:"max_links"`
+	MaxCustomAliases uint64 `json:"max_custom_aliases"`
+	MaxMonthlyClicks uint64 `json:"max_monthly_clicks"`
+	AllowCustomId    bool   `json:"allow_custom_id"`
+}
+
+type QuotaUsage struct {
+	Links         uint64 `json:"links"`
+	CustomAliases uint64 `json:"custom_aliases"`
+	MonthlyClicks uint64 `json:"monthly_clicks"`
+}
+
+type UsageResponse struct {
+	OwnerId string      `json:"owner_id"`
+	Period  string      `json:"period"`
+	Limits  QuotaLimits `json:"limits"`
+	Usage   QuotaUsage  `json:"usage"`
+}
@@ -0,0 +1,35 @@
+package dto
+
+import "time"
+
+type RuleCondition struct {
+	Devices    []string   `json:"devices,omitempty"`
+	Os         []string   `json:"os,omitempty"`
+	Languages  []string   `json:"languages,omitempty"`
+	Countries  []string   `json:"countries,omitempty"`
+	NotBefore  *time.Time `json:"not_before,omitempty"`
+	NotAfter   *time.Time `json:"not_after,omitempty"`
+	QueryParam string     `json:"query_param,omitempty"`
+	QueryValue string     `json:"query_value,omitempty"`
+}
+
+type UrlRule struct {
+	Condition RuleCondition `json:"condition"`
+	TargetUrl string        `json:"target_url"`
+}
+
+type UrlRulesRequest struct {
+	Id      string
+	OwnerId string
+}
+
+type ReplaceUrlRulesRequest struct {
+	Id      string    `json:"-"`
+	OwnerId string    `json:"-"`
+	Rules   []UrlRule `json:"rules"`
+}
+
+type UrlRulesResponse struct {
+	Id    string    `json:"id"`
+	Rules []UrlRule `json:"rules"`
+}
@@ -0,0 +1,38 @@
+package dto
+
+import "time"
+
+type StatsRequest struct {
+	OwnerId string `form:"-"`
+	// From и To - границы периода включительно, в формате 2006-01-02
+	From *time.Time `form:"from" time_format:"2006-01-02"`
+	To   *time.Time `form:"to" time_format:"2006-01-02"`
+	// Utm - фильтр по меткам кампании ссылок
+	Utm
+}
+
+type UrlStatsRequest struct {
+	Id string `form:"-"`
+	StatsRequest
+}
+
+type StatsBucket struct {
+	Value  string `json:"value"`
+	Clicks uint64 `json:"clicks"`
+}
+
+type StatsResponse struct {
+	Id string `json:"id,omitempty"`
+	// Links - число ссылок в папке вместе с вложенными папками
+	Links     *uint64       `json:"links,omitempty"`
+	Clicks    uint64        `json:"clicks"`
+	Countries []StatsBucket `json:"countries"`
+	Regions   []StatsBucket `json:"regions"`
+	Asns      []StatsBucket `json:"asns"`
+	Browsers  []StatsBucket `json:"browsers"`
+	// OperatingSystems - разбивка по семействам ОС
+	OperatingSystems []StatsBucket `json:"os"`
+	Devices          []StatsBucket `json:"devices"`
+	// Sources - разбивка по меткам источника, переходы без метки имеют пустое значение
+	Sources []StatsBucket `json:"sources"`
+}
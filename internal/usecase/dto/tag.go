@@ -0,0 +1,37 @@
+package dto
+
+type UpdateUrlDetailsRequest struct {
+	Id      string `json:"-"`
+	OwnerId string `json:"-"`
+	// Незаданные поля не меняются, пустой список меток снимает все метки
+	Title *string   `json:"title"`
+	Notes *string   `json:"notes"`
+	Tags  *[]string `json:"tags"`
+}
+
+type UrlDetailsResponse struct {
+	Id    string   `json:"id"`
+	Title string   `json:"title"`
+	Notes string   `json:"notes"`
+	Tags  []string `json:"tags"`
+}
+
+type TagsRequest struct {
+	OwnerId string
+}
+
+type TagResponse struct {
+	Name  string `json:"name"`
+	Links uint64 `json:"links"`
+}
+
+type TagsResponse struct {
+	Tags []TagResponse `json:"tags"`
+}
+
+// RenameTagRequest переименовывает метку From в To, если To уже есть - сливает их
+type RenameTagRequest struct {
+	OwnerId string `json:"-"`
+	From    string `json:"from" binding:"required"`
+	To      string `json:"to" binding:"required"`
+}
@@ -0,0 +1,64 @@
+package dto
+
+import "time"
+
+type ExportUrlsRequest struct {
+	// Format - csv или jsonl
+	Format  string `form:"format"`
+	OwnerId string `form:"owner_id"`
+	Domain  string `form:"domain"`
+	// From и To - границы даты создания включительно, в формате 2006-01-02
+	From *time.Time `form:"from" time_format:"2006-01-02"`
+	To   *time.Time `form:"to" time_format:"2006-01-02"`
+	Utm
+	BaseUrl string `form:"-"`
+}
+
+// ExportedUrl - строка выгрузки ссылок. Хэш пароля не выгружается.
+type ExportedUrl struct {
+	Id           string    `json:"id"`
+	ShortUrl     string    `json:"short_url"`
+	OriginalUrl  string    `json:"original_url"`
+	Domain       string    `json:"domain"`
+	OwnerId      string    `json:"owner_id"`
+	ClickCount   uint64    `json:"click_count"`
+	CreatedDate  time.Time `json:"created_date"`
+	CustomId     bool      `json:"custom_id"`
+	Protected    bool      `json:"protected"`
+	Interstitial bool      `json:"interstitial"`
+	RedirectType string    `json:"redirect_type"`
+	QueryMode    string    `json:"query_mode"`
+	ForwardPath  bool      `json:"forward_path"`
+	Utm
+}
+
+type ImportUrlsRequest struct {
+	// OwnerId - владелец загружаемых ссылок
+	OwnerId string
+	// Mapping сопоставляет столбцы файла полям ссылки, столбцы без сопоставления
+	// используются по своему имени
+	Mapping map[string]string
+	// OnDuplicate - что делать с уже существующими ссылками: skip, overwrite или fail
+	OnDuplicate string
+	// DryRun - только проверить файл и посчитать изменения
+	DryRun bool
+	// Progress вызывается по мере сохранения с числом обработанных и всех сохраняемых ссылок
+	Progress func(done, total int)
+}
+
+type ImportRowError struct {
+	Line  int    `json:"line"`
+	Error string `json:"error"`
+}
+
+type ImportUrlsResponse struct {
+	DryRun  bool `json:"dry_run"`
+	Total   int  `json:"total"`
+	Created int  `json:"created"`
+	Updated int  `json:"updated"`
+	Skipped int  `json:"skipped"`
+	Failed  int  `json:"failed"`
+	// Aborted - в режиме fail найдены существующие ссылки, ничего не сохранено
+	Aborted bool             `json:"aborted"`
+	Errors  []ImportRowError `json:"errors"`
+}
@@ -3,8 +3,50 @@ package dto
 import "time"
 
 type PaginationRequest struct {
-	Limit int `json:"limit"`
-	Page  int `json:"page"`
+	Limit int `form:"limit" json:"limit"`
+	// Page - номер страницы для постраничного режима со смещением, без него список читается по курсору
+	Page int `form:"page" json:"page"`
+	// Cursor - непрозрачный курсор из next_cursor или prev_cursor предыдущего ответа
+	Cursor string `form:"cursor" json:"cursor"`
+	// Total - посчитать общее число ссылок под фильтром
+	Total   bool   `form:"total" json:"total"`
+	BaseUrl string `form:"-" json:"-"`
+	UrlListFilter
+	// Utm - фильтр по меткам кампании
+	Utm
+}
+
+// UrlListFilter - условия отбора и порядок списка ссылок
+type UrlListFilter struct {
+	// OwnerId - владелец из API-ключа запроса, пустой для анонимного запроса. Из параметров не читается.
+	OwnerId string `form:"-" json:"owner_id,omitempty"`
+	Domain  string `form:"domain" json:"domain,omitempty"`
+	// From и To - границы даты создания включительно, в формате 2006-01-02
+	From *time.Time `form:"from" time_format:"2006-01-02" json:"from,omitempty"`
+	To   *time.Time `form:"to" time_format:"2006-01-02" json:"to,omitempty"`
+	// Status - public или protected
+	Status    string  `form:"status" json:"status,omitempty"`
+	MinClicks *uint64 `form:"min_clicks" json:"min_clicks,omitempty"`
+	MaxClicks *uint64 `form:"max_clicks" json:"max_clicks,omitempty"`
+	// Search - подстрока адреса перехода, id или названия
+	Search string `form:"q" json:"q,omitempty"`
+	// Tags - у ссылки должны быть все указанные метки
+	Tags []string `form:"tag" json:"tags,omitempty"`
+	// FolderId - ссылки из этой папки и вложенных в неё папок
+	FolderId string `form:"folder_id" json:"folder_id,omitempty"`
+	// Broken - только ссылки, страница назначения которых при последней проверке не ответила или вернула ошибку
+	Broken bool `form:"broken" json:"broken,omitempty"`
+	// Sort - created_date или click_count, минус впереди означает убывание, например -click_count
+	Sort string `form:"sort" json:"sort,omitempty"`
+}
+
+// Utm - метки кампании ссылки
+type Utm struct {
+	UtmSource   string `form:"utm_source" json:"utm_source,omitempty"`
+	UtmMedium   string `form:"utm_medium" json:"utm_medium,omitempty"`
+	UtmCampaign string `form:"utm_campaign" json:"utm_campaign,omitempty"`
+	UtmTerm     string `form:"utm_term" json:"utm_term,omitempty"`
+	UtmContent  string `form:"utm_content" json:"utm_content,omitempty"`
 }
 
 type UrlInfoResponse struct {
@@ -13,24 +55,151 @@ type UrlInfoResponse struct {
 	ShortUrl    string    `json:"short_url"`
 	CountClick  uint64    `json:"count_click"`
 	CreatedDate time.Time `json:"created_date"`
+	Domain      string    `json:"domain,omitempty"`
+	Protected   bool      `json:"protected"`
+	// ResolvedUrl - конечный адрес, если адрес назначения сам был короткой ссылкой
+	ResolvedUrl string `json:"resolved_url,omitempty"`
+	// Interstitial - перед переходом на внешний домен показывается промежуточная страница
+	Interstitial bool   `json:"interstitial"`
+	RedirectType string `json:"redirect_type,omitempty"`
+	QueryMode    string `json:"query_mode,omitempty"`
+	ForwardPath  bool   `json:"forward_path"`
+	Utm
+	Title string   `json:"title,omitempty"`
+	Notes string   `json:"notes,omitempty"`
+	Tags  []string `json:"tags"`
+	// FolderId - папка ссылки, пустая для ссылок вне папок
+	FolderId string `json:"folder_id,omitempty"`
+	// Metadata - сведения со страницы назначения, нет пока они не загружены
+	Metadata *UrlMetadataResponse `json:"metadata,omitempty"`
+	// Health - результат последней проверки страницы назначения, нет пока ссылка не проверялась
+	Health *UrlHealthResponse `json:"health,omitempty"`
+}
+
+type UrlMetadataResponse struct {
+	Title       string `json:"title,omitempty"`
+	Description string `json:"description,omitempty"`
+	Image       string `json:"image,omitempty"`
+	SiteName    string `json:"site_name,omitempty"`
+	Favicon     string `json:"favicon,omitempty"`
+}
+
+type UrlListResponse struct {
+	Data  []UrlInfoResponse `json:"data"`
+	Total *uint64           `json:"total,omitempty"`
+	// NextCursor и PrevCursor ведут к соседним окнам списка
+	NextCursor string `json:"next_cursor,omitempty"`
+	PrevCursor string `json:"prev_cursor,omitempty"`
+	// NextPage и PrevPage - соседние страницы в режиме со смещением
+	NextPage int `json:"-"`
+	PrevPage int `json:"-"`
+	// Next и Prev - готовые адреса соседних окон
+	Next string `json:"next,omitempty"`
+	Prev string `json:"prev,omitempty"`
+}
+
+type UrlPreviewRequest struct {
+	Id       string
+	Host     string
+	Unlocked bool
+	BaseUrl  string
+}
+
+type UrlPreviewResponse struct {
+	Id          string    `json:"id"`
+	ShortUrl    string    `json:"short_url"`
+	Destination string    `json:"destination"`
+	CountClick  uint64    `json:"count_click"`
+	CreatedDate time.Time `json:"created_date"`
+	Safety      string    `json:"safety"`
+}
+
+type UrlClickResponse struct {
+	Url          string
+	RedirectType string
+	// Variant - вариант эксперимента, выбранный для перехода
+	Variant string
+	// StickyVariant - вариант нужно запомнить в cookie посетителя
+	StickyVariant bool
+	// Private - перенаправление нельзя хранить в общих кэшах: доступ к ссылке ограничен
+	// или адрес перехода зависит от запроса и посетителя
+	Private bool
 }
 
 type UrlClickRequest struct {
-	Id string
+	Id   string
+	Host string
+	// Unlocked - клиент уже ввёл пароль защищённой ссылки
+	Unlocked bool
+	// Confirmed - клиент подтвердил переход на промежуточной странице
+	Confirmed bool
+	// Path - продолжение пути после идентификатора, например "/guide/intro"
+	Path string
+	// Query - параметры запроса, которые можно перенести в адрес перехода
+	Query map[string][]string
+	// UserAgent, AcceptLanguage и ClientIp используются правилами перенаправления
+	UserAgent      string
+	AcceptLanguage string
+	ClientIp       string
+	// Variant - вариант эксперимента, ранее выданный посетителю
+	Variant string
+	// Source - источник перехода из метки короткой ссылки, например qr
+	Source string
+}
+
+type UnlockUrlRequest struct {
+	Id       string
+	Host     string
+	Password string
+	// ClientIp ограничивает число попыток с одного адреса
+	ClientIp string
 }
 
 type CreateShortUrlRequest struct {
-	Url string `form:"url" binding:"required"`
-	Id  string `form:"id"`
+	Url          string `form:"url" binding:"required"`
+	Id           string `form:"id"`
+	Domain       string `form:"domain"`
+	Password     string `form:"password"`
+	Interstitial bool   `form:"interstitial"`
+	RedirectType string `form:"redirect_type" json:"redirect_type"`
+	QueryMode    string `form:"query_mode" json:"query_mode"`
+	ForwardPath  bool   `form:"forward_path" json:"forward_path"`
+	Utm
+	// Dedup - reuse, new или owner: можно ли выдать существующую ссылку на тот же адрес
+	Dedup string `form:"dedup" json:"dedup"`
 }
 
 type CreateShortUrlUseCaseRequest struct {
-	Url string `json:"url"`
+	Url          string `json:"url"`
+	Domain       string `json:"domain"`
+	Password     string `json:"-"`
+	Interstitial bool   `json:"interstitial"`
+	RedirectType string `json:"redirect_type"`
+	QueryMode    string `json:"query_mode"`
+	ForwardPath  bool   `json:"forward_path"`
+	Utm
+	Dedup   string `json:"dedup"`
+	OwnerId string `json:"-"`
+	BaseUrl string `json:"-"`
+	// ClientIp ограничивает число анонимных ссылок с одного адреса
+	ClientIp string `json:"-"`
 }
 
 type CreateShortUrlWithCustomIdRequest struct {
-	Url string `json:"url"`
-	Id  string `json:"id"`
+	Url          string `json:"url"`
+	Id           string `json:"id"`
+	Domain       string `json:"domain"`
+	Password     string `json:"-"`
+	Interstitial bool   `json:"interstitial"`
+	RedirectType string `json:"redirect_type"`
+	QueryMode    string `json:"query_mode"`
+	ForwardPath  bool   `json:"forward_path"`
+	Utm
+	Dedup   string `json:"dedup"`
+	OwnerId string `json:"-"`
+	BaseUrl string `json:"-"`
+	// ClientIp ограничивает число анонимных ссылок с одного адреса
+	ClientIp string `json:"-"`
 }
 
 type CreateShortUrlResponse struct {
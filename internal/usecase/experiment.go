@@ -0,0 +1,185 @@
+package usecase
+
+import (
+	"context"
+	"hash/fnv"
+	"leenwood/yandex-http/config"
+	"leenwood/yandex-http/internal/domain/experiment"
+	experimentRepository "leenwood/yandex-http/internal/domain/experiment/postgresRepository"
+	"leenwood/yandex-http/internal/domain/url"
+	"leenwood/yandex-http/internal/usecase/dto"
+	"math/rand/v2"
+)
+
+type ExperimentUseCaseInterface interface {
+	GetExperiment(request dto.ExperimentRequest) (dto.ExperimentResponse, error)
+	CreateExperiment(request dto.CreateExperimentRequest) (dto.ExperimentResponse, error)
+	UpdateExperiment(request dto.UpdateExperimentRequest) (dto.ExperimentResponse, error)
+	PromoteVariant(request dto.PromoteVariantRequest) (dto.ExperimentResponse, error)
+}
+
+type ExperimentUseCase struct {
+	r        url.RepositoryInterface
+	variants experiment.RepositoryInterface
+}
+
+func NewExperimentUseCase(ctx context.Context, config config.Config, urls url.RepositoryInterface) (*ExperimentUseCase, error) {
+	variants, err := experimentRepository.NewRepository(ctx, config.Database)
+	if err != nil {
+		return nil, err
+	}
+	return &ExperimentUseCase{r: urls, variants: variants}, nil
+}
+
+func (es *ExperimentUseCase) GetExperiment(request dto.ExperimentRequest) (dto.ExperimentResponse, error) {
+	u, variants, err := es.findExperiment(request.Id, request.OwnerId)
+	if err != nil {
+		return dto.ExperimentResponse{}, err
+	}
+	return transformToExperimentResponse(u, variants), nil
+}
+
+// CreateExperiment запускает эксперимент на ссылке, у которой его ещё нет
+func (es *ExperimentUseCase) CreateExperiment(request dto.CreateExperimentRequest) (dto.ExperimentResponse, error) {
+	u, err := findOwnedUrl(es.r, request.Id, request.OwnerId)
+	if err != nil {
+		return dto.ExperimentResponse{}, err
+	}
+	if u.SplitMode != url.SplitNone {
+		return dto.ExperimentResponse{}, experiment.ErrExists
+	}
+
+	mode, err := url.ParseSplitMode(request.SplitMode)
+	if err != nil {
+		return dto.ExperimentResponse{}, err
+	}
+
+	variants := make([]*experiment.Variant, 0, len(request.Variants))
+	for _, v := range request.Variants {
+		variants = append(variants, &experiment.Variant{UrlId: u.Id, Name: v.Name, TargetUrl: v.TargetUrl, Weight: v.Weight})
+	}
+	if err = experiment.Validate(variants); err != nil {
+		return dto.ExperimentResponse{}, err
+	}
+
+	if err = es.variants.Save(u.Id, variants); err != nil {
+		return dto.ExperimentResponse{}, err
+	}
+
+	u.SplitMode = mode
+	if _, err = es.r.Update(u); err != nil {
+		return dto.ExperimentResponse{}, err
+	}
+	return transformToExperimentResponse(u, variants), nil
+}
+
+// UpdateExperiment меняет веса вариантов и способ распределения, счётчики переходов сохраняются
+func (es *ExperimentUseCase) UpdateExperiment(request dto.UpdateExperimentRequest) (dto.ExperimentResponse, error) {
+	u, variants, err := es.findExperiment(request.Id, request.OwnerId)
+	if err != nil {
+		return dto.ExperimentResponse{}, err
+	}
+
+	mode := u.SplitMode
+	if request.SplitMode != "" {
+		if mode, err = url.ParseSplitMode(request.SplitMode); err != nil {
+			return dto.ExperimentResponse{}, err
+		}
+	}
+
+	for name, weight := range request.Weights {
+		variant := experiment.Find(variants, name)
+		if variant == nil {
+			return dto.ExperimentResponse{}, experiment.ErrVariantNotFound
+		}
+		variant.Weight = weight
+	}
+	if err = experiment.ValidateWeights(variants); err != nil {
+		return dto.ExperimentResponse{}, err
+	}
+
+	if err = es.variants.UpdateWeights(u.Id, variants); err != nil {
+		return dto.ExperimentResponse{}, err
+	}
+
+	if mode != u.SplitMode {
+		u.SplitMode = mode
+		if _, err = es.r.Update(u); err != nil {
+			return dto.ExperimentResponse{}, err
+		}
+	}
+	return transformToExperimentResponse(u, variants), nil
+}
+
+// PromoteVariant завершает эксперимент: адрес победившего варианта становится адресом ссылки.
+// В ответе возвращаются итоговые счётчики вариантов.
+func (es *ExperimentUseCase) PromoteVariant(request dto.PromoteVariantRequest) (dto.ExperimentResponse, error) {
+	u, variants, err := es.findExperiment(request.Id, request.OwnerId)
+	if err != nil {
+		return dto.ExperimentResponse{}, err
+	}
+
+	winner := experiment.Find(variants, request.Variant)
+	if winner == nil {
+		return dto.ExperimentResponse{}, experiment.ErrVariantNotFound
+	}
+
+	u.OriginalUrl = winner.TargetUrl
+	u.ResolvedUrl = ""
+	// Ссылка ведёт на другой адрес и больше не выдаётся вместо новых ссылок на прежний
+	u.DedupKey = ""
+	u.SplitMode = url.SplitNone
+	if _, err = es.r.Update(u); err != nil {
+		return dto.ExperimentResponse{}, err
+	}
+
+	if err = es.variants.DeleteByUrl(u.Id); err != nil {
+		return dto.ExperimentResponse{}, err
+	}
+	return transformToExperimentResponse(u, variants), nil
+}
+
+func (es *ExperimentUseCase) findExperiment(id, ownerId string) (*url.Url, []*experiment.Variant, error) {
+	u, err := findOwnedUrl(es.r, id, ownerId)
+	if err != nil {
+		return nil, nil, err
+	}
+	if u.SplitMode == url.SplitNone {
+		return nil, nil, experiment.ErrNotFound
+	}
+
+	variants, err := es.variants.FindByUrl(u.Id)
+	if err != nil {
+		return nil, nil, err
+	}
+	return u, variants, nil
+}
+
+// pickVariant выбирает вариант эксперимента для перехода согласно способу распределения ссылки
+func pickVariant(u *url.Url, variants []*experiment.Variant, request dto.UrlClickRequest) *experiment.Variant {
+	switch u.SplitMode {
+	case url.SplitCookie:
+		// Посетитель сохраняет вариант, пока тот участвует в эксперименте
+		if variant := experiment.Find(variants, request.Variant); variant != nil && variant.Weight > 0 {
+			return variant
+		}
+	case url.SplitFingerprint:
+		hash := fnv.New64a()
+		hash.Write([]byte(u.Id + "|" + request.ClientIp + "|" + request.UserAgent))
+		return experiment.Pick(variants, hash.Sum64())
+	}
+	return experiment.Pick(variants, rand.Uint64())
+}
+
+func transformToExperimentResponse(u *url.Url, variants []*experiment.Variant) dto.ExperimentResponse {
+	response := dto.ExperimentResponse{Id: u.Id, SplitMode: string(u.SplitMode), Variants: []dto.ExperimentVariant{}}
+	for _, v := range variants {
+		response.Variants = append(response.Variants, dto.ExperimentVariant{
+			Name:       v.Name,
+			TargetUrl:  v.TargetUrl,
+			Weight:     v.Weight,
+			ClickCount: v.ClickCount,
+		})
+	}
+	return response
+}
@@ -0,0 +1,172 @@
+package usecase
+
+import (
+	"leenwood/yandex-http/internal/domain/experiment"
+	experimentMocks "leenwood/yandex-http/internal/domain/experiment/mocks"
+	"leenwood/yandex-http/internal/domain/url"
+	"leenwood/yandex-http/internal/domain/url/mocks"
+	"leenwood/yandex-http/internal/usecase/dto"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("A/B experiments", func() {
+	var (
+		ctrl         *gomock.Controller
+		mockRepo     *mocks.MockRepositoryInterface
+		mockVariants *experimentMocks.MockRepositoryInterface
+		variants     []*experiment.Variant
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		mockRepo = mocks.NewMockRepositoryInterface(ctrl)
+		mockVariants = experimentMocks.NewMockRepositoryInterface(ctrl)
+		variants = []*experiment.Variant{
+			{UrlId: "ab", Name: "a", TargetUrl: "https://example.com/a", Weight: 1},
+			{UrlId: "ab", Name: "b", TargetUrl: "https://example.com/b", Weight: 3, ClickCount: 7},
+		}
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	Describe("Pick", func() {
+		It("should split rolls proportionally to weights", func() {
+			Expect(experiment.Pick(variants, 0).Name).To(Equal("a"))
+			Expect(experiment.Pick(variants, 1).Name).To(Equal("b"))
+			Expect(experiment.Pick(variants, 4).Name).To(Equal("a"))
+			Expect(experiment.Pick([]*experiment.Variant{{Name: "a"}}, 1)).To(BeNil())
+		})
+	})
+
+	Describe("ClickUrl", func() {
+		var urlUseCase UrlUseCaseInterface
+
+		BeforeEach(func() {
+			urlUseCase = &UrlUseCase{r: mockRepo, variants: mockVariants, clicks: acceptClicks(ctrl), geo: noGeoIp, ua: agents}
+		})
+
+		It("should keep the variant from the visitor cookie and count the click", func() {
+			mockUrl := &url.Url{Id: "ab", OriginalUrl: "https://example.com", SplitMode: url.SplitCookie}
+			mockRepo.EXPECT().FindById("ab").Return(mockUrl, nil)
+			mockRepo.EXPECT().IncrementClicks("ab").Return(nil)
+			mockVariants.EXPECT().FindByUrl("ab").Return(variants, nil)
+			mockVariants.EXPECT().IncrementClicks("ab", "a").Return(nil)
+
+			response, err := urlUseCase.ClickUrl(dto.UrlClickRequest{Id: "ab", Variant: "a"})
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(response.Url).To(Equal("https://example.com/a"))
+			Expect(response.Variant).To(Equal("a"))
+			Expect(response.StickyVariant).To(BeTrue())
+		})
+
+		It("should assign the same variant to the same fingerprint", func() {
+			mockUrl := &url.Url{Id: "ab", OriginalUrl: "https://example.com", SplitMode: url.SplitFingerprint}
+			mockRepo.EXPECT().FindById("ab").Return(mockUrl, nil).Times(2)
+			mockRepo.EXPECT().IncrementClicks("ab").Return(nil).Times(2)
+			mockVariants.EXPECT().FindByUrl("ab").Return(variants, nil).Times(2)
+			mockVariants.EXPECT().IncrementClicks("ab", gomock.Any()).Return(nil).Times(2)
+
+			request := dto.UrlClickRequest{Id: "ab", ClientIp: "192.0.2.1", UserAgent: "curl/8.0"}
+			first, err := urlUseCase.ClickUrl(request)
+			Expect(err).NotTo(HaveOccurred())
+			second, err := urlUseCase.ClickUrl(request)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(second.Variant).To(Equal(first.Variant))
+			Expect(second.StickyVariant).To(BeFalse())
+		})
+	})
+
+	Describe("ExperimentUseCase", func() {
+		var experimentUseCase ExperimentUseCaseInterface
+
+		BeforeEach(func() {
+			experimentUseCase = &ExperimentUseCase{r: mockRepo, variants: mockVariants}
+		})
+
+		It("should start an experiment on an owned link", func() {
+			mockRepo.EXPECT().FindById("ab").Return(&url.Url{Id: "ab", OwnerId: "acme"}, nil)
+			mockVariants.EXPECT().Save("ab", gomock.Len(2)).Return(nil)
+			mockRepo.EXPECT().Update(gomock.Any()).DoAndReturn(func(u *url.Url) (*url.Url, error) {
+				Expect(u.SplitMode).To(Equal(url.SplitRandom))
+				return u, nil
+			})
+
+			response, err := experimentUseCase.CreateExperiment(dto.CreateExperimentRequest{
+				Id:        "ab",
+				OwnerId:   "acme",
+				SplitMode: "random",
+				Variants: []dto.ExperimentVariant{
+					{Name: "a", TargetUrl: "https://example.com/a", Weight: 50},
+					{Name: "b", TargetUrl: "https://example.com/b", Weight: 50},
+				},
+			})
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(response.Variants).To(HaveLen(2))
+		})
+
+		It("should reject a single variant or duplicate names", func() {
+			mockRepo.EXPECT().FindById("ab").Return(&url.Url{Id: "ab", OwnerId: "acme"}, nil).Times(2)
+
+			_, err := experimentUseCase.CreateExperiment(dto.CreateExperimentRequest{
+				Id: "ab", OwnerId: "acme", SplitMode: "random",
+				Variants: []dto.ExperimentVariant{{Name: "a", TargetUrl: "https://example.com/a", Weight: 1}},
+			})
+			Expect(err).To(MatchError(experiment.ErrInvalid))
+
+			_, err = experimentUseCase.CreateExperiment(dto.CreateExperimentRequest{
+				Id: "ab", OwnerId: "acme", SplitMode: "random",
+				Variants: []dto.ExperimentVariant{
+					{Name: "a", TargetUrl: "https://example.com/a", Weight: 1},
+					{Name: "a", TargetUrl: "https://example.com/b", Weight: 1},
+				},
+			})
+			Expect(err).To(MatchError(experiment.ErrInvalid))
+		})
+
+		It("should adjust weights without touching click counts", func() {
+			mockRepo.EXPECT().FindById("ab").Return(&url.Url{Id: "ab", OwnerId: "acme", SplitMode: url.SplitRandom}, nil)
+			mockVariants.EXPECT().FindByUrl("ab").Return(variants, nil)
+			mockVariants.EXPECT().UpdateWeights("ab", variants).Return(nil)
+
+			response, err := experimentUseCase.UpdateExperiment(dto.UpdateExperimentRequest{
+				Id: "ab", OwnerId: "acme", Weights: map[string]int{"a": 0},
+			})
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(response.Variants[0].Weight).To(Equal(0))
+			Expect(response.Variants[1].ClickCount).To(Equal(uint64(7)))
+		})
+
+		It("should end the experiment by promoting the winner", func() {
+			mockRepo.EXPECT().FindById("ab").Return(&url.Url{Id: "ab", OwnerId: "acme", SplitMode: url.SplitRandom}, nil)
+			mockVariants.EXPECT().FindByUrl("ab").Return(variants, nil)
+			mockRepo.EXPECT().Update(gomock.Any()).DoAndReturn(func(u *url.Url) (*url.Url, error) {
+				Expect(u.OriginalUrl).To(Equal("https://example.com/b"))
+				Expect(u.SplitMode).To(Equal(url.SplitNone))
+				return u, nil
+			})
+			mockVariants.EXPECT().DeleteByUrl("ab").Return(nil)
+
+			response, err := experimentUseCase.PromoteVariant(dto.PromoteVariantRequest{Id: "ab", OwnerId: "acme", Variant: "b"})
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(response.SplitMode).To(BeEmpty())
+		})
+
+		It("should report links without an experiment", func() {
+			mockRepo.EXPECT().FindById("ab").Return(&url.Url{Id: "ab", OwnerId: "acme"}, nil)
+
+			_, err := experimentUseCase.GetExperiment(dto.ExperimentRequest{Id: "ab", OwnerId: "acme"})
+
+			Expect(err).To(MatchError(experiment.ErrNotFound))
+		})
+	})
+})
@@ -0,0 +1,175 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"leenwood/yandex-http/config"
+	"leenwood/yandex-http/internal/domain/folder"
+	folderRepository "leenwood/yandex-http/internal/domain/folder/postgresRepository"
+	"leenwood/yandex-http/internal/domain/url"
+	"leenwood/yandex-http/internal/usecase/dto"
+)
+
+type FolderUseCaseInterface interface {
+	CreateFolder(request dto.CreateFolderRequest) (dto.FolderResponse, error)
+	GetFolders(request dto.FoldersRequest) (dto.FoldersResponse, error)
+	UpdateFolder(request dto.UpdateFolderRequest) (dto.FolderResponse, error)
+	DeleteFolder(request dto.FolderRequest) error
+	MoveUrl(request dto.MoveUrlRequest) (dto.UrlFolderResponse, error)
+}
+
+type FolderUseCase struct {
+	r       url.RepositoryInterface
+	folders folder.RepositoryInterface
+}
+
+func NewFolderUseCase(ctx context.Context, config config.Config, urls url.RepositoryInterface) (*FolderUseCase, error) {
+	folders, err := folderRepository.NewRepository(ctx, config.Database)
+	if err != nil {
+		return nil, err
+	}
+	return &FolderUseCase{r: urls, folders: folders}, nil
+}
+
+func (fs *FolderUseCase) CreateFolder(request dto.CreateFolderRequest) (dto.FolderResponse, error) {
+	name, err := folder.NormalizeName(request.Name)
+	if err != nil {
+		return dto.FolderResponse{}, err
+	}
+
+	tree, err := ownerFolders(fs.folders, request.OwnerId)
+	if err != nil {
+		return dto.FolderResponse{}, err
+	}
+	if err = checkFolderPlace(tree, request.ParentId, name, "", 1); err != nil {
+		return dto.FolderResponse{}, err
+	}
+
+	model, err := fs.folders.Save(&folder.Folder{OwnerId: request.OwnerId, ParentId: request.ParentId, Name: name})
+	if err != nil {
+		return dto.FolderResponse{}, err
+	}
+	return transformToFolderResponse(model), nil
+}
+
+// GetFolders возвращает все папки владельца плоским списком, дерево строится по parent_id
+func (fs *FolderUseCase) GetFolders(request dto.FoldersRequest) (dto.FoldersResponse, error) {
+	folders, err := fs.folders.FindByOwner(request.OwnerId)
+	if err != nil {
+		return dto.FoldersResponse{}, err
+	}
+
+	response := dto.FoldersResponse{Folders: make([]dto.FolderResponse, 0, len(folders))}
+	for _, f := range folders {
+		response.Folders = append(response.Folders, transformToFolderResponse(f))
+	}
+	return response, nil
+}
+
+// UpdateFolder переименовывает папку и перемещает её вместе с вложенными папками и ссылками
+func (fs *FolderUseCase) UpdateFolder(request dto.UpdateFolderRequest) (dto.FolderResponse, error) {
+	tree, err := ownerFolders(fs.folders, request.OwnerId)
+	if err != nil {
+		return dto.FolderResponse{}, err
+	}
+	model, ok := tree[request.Id]
+	if !ok {
+		return dto.FolderResponse{}, folder.ErrNotFound
+	}
+
+	name, parentId := model.Name, model.ParentId
+	if request.Name != nil {
+		if name, err = folder.NormalizeName(*request.Name); err != nil {
+			return dto.FolderResponse{}, err
+		}
+	}
+	if request.ParentId != nil {
+		parentId = *request.ParentId
+		if tree.IsInside(parentId, model.Id) {
+			return dto.FolderResponse{}, folder.ErrInvalidMove
+		}
+	}
+	if err = checkFolderPlace(tree, parentId, name, model.Id, tree.Height(model.Id)); err != nil {
+		return dto.FolderResponse{}, err
+	}
+
+	model.Name, model.ParentId = name, parentId
+	if _, err = fs.folders.Update(model); err != nil {
+		return dto.FolderResponse{}, err
+	}
+	return transformToFolderResponse(model), nil
+}
+
+// DeleteFolder удаляет папку, её ссылки и вложенные папки переходят в родительскую папку
+func (fs *FolderUseCase) DeleteFolder(request dto.FolderRequest) error {
+	tree, err := ownerFolders(fs.folders, request.OwnerId)
+	if err != nil {
+		return err
+	}
+	model, ok := tree[request.Id]
+	if !ok {
+		return folder.ErrNotFound
+	}
+
+	for _, child := range tree.Children(model.Id) {
+		if tree.HasName(model.ParentId, child.Name, model.Id) {
+			return fmt.Errorf("%w: cannot move subfolder %q to the parent folder", folder.ErrExists, child.Name)
+		}
+	}
+	return fs.folders.Delete(model)
+}
+
+// MoveUrl кладёт ссылку владельца в его папку или убирает её из папки
+func (fs *FolderUseCase) MoveUrl(request dto.MoveUrlRequest) (dto.UrlFolderResponse, error) {
+	u, err := findOwnedUrl(fs.r, request.Id, request.OwnerId)
+	if err != nil {
+		return dto.UrlFolderResponse{}, err
+	}
+
+	if request.FolderId != "" {
+		f, err := fs.folders.FindById(request.FolderId)
+		if err != nil {
+			return dto.UrlFolderResponse{}, err
+		}
+		if f.OwnerId != request.OwnerId {
+			return dto.UrlFolderResponse{}, folder.ErrNotFound
+		}
+	}
+
+	if u.FolderId != request.FolderId {
+		u.FolderId = request.FolderId
+		if _, err = fs.r.Update(u); err != nil {
+			return dto.UrlFolderResponse{}, err
+		}
+	}
+	return dto.UrlFolderResponse{Id: u.Id, FolderId: u.FolderId}, nil
+}
+
+// ownerFolders возвращает дерево папок владельца, чужие папки в него не попадают
+func ownerFolders(folders folder.RepositoryInterface, ownerId string) (folder.Tree, error) {
+	owned, err := folders.FindByOwner(ownerId)
+	if err != nil {
+		return nil, err
+	}
+	return folder.NewTree(owned), nil
+}
+
+// checkFolderPlace проверяет, что папку высотой height с названием name можно положить в parentId
+func checkFolderPlace(tree folder.Tree, parentId, name, id string, height int) error {
+	if parentId != "" {
+		if _, ok := tree[parentId]; !ok {
+			return fmt.Errorf("parent %w", folder.ErrNotFound)
+		}
+	}
+	if tree.Depth(parentId)+height > folder.MaxDepth {
+		return fmt.Errorf("%w: at most %d levels", folder.ErrTooDeep, folder.MaxDepth)
+	}
+	if tree.HasName(parentId, name, id) {
+		return fmt.Errorf("%w: %q", folder.ErrExists, name)
+	}
+	return nil
+}
+
+func transformToFolderResponse(f *folder.Folder) dto.FolderResponse {
+	return dto.FolderResponse{Id: f.Id, ParentId: f.ParentId, Name: f.Name, CreatedDate: f.CreatedDate}
+}
@@ -0,0 +1,191 @@
+package usecase
+
+import (
+	"leenwood/yandex-http/internal/domain/click"
+	clickMocks "leenwood/yandex-http/internal/domain/click/mocks"
+	"leenwood/yandex-http/internal/domain/folder"
+	folderMocks "leenwood/yandex-http/internal/domain/folder/mocks"
+	"leenwood/yandex-http/internal/domain/url"
+	"leenwood/yandex-http/internal/domain/url/mocks"
+	"leenwood/yandex-http/internal/usecase/dto"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Folders", func() {
+	var (
+		ctrl          *gomock.Controller
+		mockRepo      *mocks.MockRepositoryInterface
+		mockFolders   *folderMocks.MockRepositoryInterface
+		folderUseCase FolderUseCaseInterface
+		// marketing/spring/emails и docs
+		owned []*folder.Folder
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		mockRepo = mocks.NewMockRepositoryInterface(ctrl)
+		mockFolders = folderMocks.NewMockRepositoryInterface(ctrl)
+		folderUseCase = &FolderUseCase{r: mockRepo, folders: mockFolders}
+		owned = []*folder.Folder{
+			{Id: "marketing", OwnerId: "acme", Name: "Marketing"},
+			{Id: "spring", OwnerId: "acme", ParentId: "marketing", Name: "Spring"},
+			{Id: "emails", OwnerId: "acme", ParentId: "spring", Name: "Emails"},
+			{Id: "docs", OwnerId: "acme", Name: "Docs"},
+		}
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	Describe("CreateFolder", func() {
+		It("should create a subfolder", func() {
+			mockFolders.EXPECT().FindByOwner("acme").Return(owned, nil)
+			mockFolders.EXPECT().Save(&folder.Folder{OwnerId: "acme", ParentId: "marketing", Name: "Summer sale"}).
+				DoAndReturn(func(f *folder.Folder) (*folder.Folder, error) {
+					f.Id = "summer"
+					return f, nil
+				})
+
+			response, err := folderUseCase.CreateFolder(dto.CreateFolderRequest{OwnerId: "acme", ParentId: "marketing", Name: " Summer   sale "})
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(response.Id).To(Equal("summer"))
+			Expect(response.Name).To(Equal("Summer sale"))
+		})
+
+		It("should reject a duplicate name and a foreign parent", func() {
+			mockFolders.EXPECT().FindByOwner("acme").Return(owned, nil).Times(2)
+
+			_, err := folderUseCase.CreateFolder(dto.CreateFolderRequest{OwnerId: "acme", ParentId: "marketing", Name: "spring"})
+			Expect(err).To(MatchError(folder.ErrExists))
+
+			_, err = folderUseCase.CreateFolder(dto.CreateFolderRequest{OwnerId: "acme", ParentId: "other", Name: "Spring"})
+			Expect(err).To(MatchError(folder.ErrNotFound))
+		})
+	})
+
+	Describe("UpdateFolder", func() {
+		It("should move a folder with its subfolders", func() {
+			parentId := "docs"
+			mockFolders.EXPECT().FindByOwner("acme").Return(owned, nil)
+			mockFolders.EXPECT().Update(gomock.Any()).DoAndReturn(func(f *folder.Folder) (*folder.Folder, error) {
+				Expect(f.Id).To(Equal("spring"))
+				Expect(f.ParentId).To(Equal("docs"))
+				return f, nil
+			})
+
+			response, err := folderUseCase.UpdateFolder(dto.UpdateFolderRequest{Id: "spring", OwnerId: "acme", ParentId: &parentId})
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(response.ParentId).To(Equal("docs"))
+		})
+
+		It("should not move a folder into its own subfolder", func() {
+			parentId := "emails"
+			mockFolders.EXPECT().FindByOwner("acme").Return(owned, nil)
+
+			_, err := folderUseCase.UpdateFolder(dto.UpdateFolderRequest{Id: "marketing", OwnerId: "acme", ParentId: &parentId})
+
+			Expect(err).To(MatchError(folder.ErrInvalidMove))
+		})
+
+		It("should limit the nesting depth", func() {
+			deep := []*folder.Folder{{Id: "f1", OwnerId: "acme", Name: "1"}}
+			for i := 2; i <= folder.MaxDepth; i++ {
+				deep = append(deep, &folder.Folder{Id: "f" + string(rune('0'+i)), OwnerId: "acme", ParentId: deep[len(deep)-1].Id, Name: "n"})
+			}
+			parentId := deep[len(deep)-1].Id
+			mockFolders.EXPECT().FindByOwner("acme").Return(append(deep, owned...), nil)
+
+			_, err := folderUseCase.UpdateFolder(dto.UpdateFolderRequest{Id: "docs", OwnerId: "acme", ParentId: &parentId})
+
+			Expect(err).To(MatchError(folder.ErrTooDeep))
+		})
+	})
+
+	Describe("DeleteFolder", func() {
+		It("should delete an owned folder", func() {
+			mockFolders.EXPECT().FindByOwner("acme").Return(owned, nil)
+			mockFolders.EXPECT().Delete(owned[1]).Return(nil)
+
+			Expect(folderUseCase.DeleteFolder(dto.FolderRequest{Id: "spring", OwnerId: "acme"})).To(Succeed())
+		})
+
+		It("should refuse when a subfolder name clashes in the parent", func() {
+			owned = append(owned, &folder.Folder{Id: "emails-top", OwnerId: "acme", ParentId: "marketing", Name: "Emails"})
+			mockFolders.EXPECT().FindByOwner("acme").Return(owned, nil)
+
+			Expect(folderUseCase.DeleteFolder(dto.FolderRequest{Id: "spring", OwnerId: "acme"})).To(MatchError(folder.ErrExists))
+		})
+	})
+
+	Describe("MoveUrl", func() {
+		It("should put an owned link into an owned folder", func() {
+			mockRepo.EXPECT().FindById("abc").Return(&url.Url{Id: "abc", OwnerId: "acme"}, nil)
+			mockFolders.EXPECT().FindById("docs").Return(owned[3], nil)
+			mockRepo.EXPECT().Update(&url.Url{Id: "abc", OwnerId: "acme", FolderId: "docs"}).Return(nil, nil)
+
+			response, err := folderUseCase.MoveUrl(dto.MoveUrlRequest{Id: "abc", OwnerId: "acme", FolderId: "docs"})
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(response).To(Equal(dto.UrlFolderResponse{Id: "abc", FolderId: "docs"}))
+		})
+
+		It("should not use folders of other owners", func() {
+			mockRepo.EXPECT().FindById("abc").Return(&url.Url{Id: "abc", OwnerId: "acme"}, nil)
+			mockFolders.EXPECT().FindById("private").Return(&folder.Folder{Id: "private", OwnerId: "other"}, nil)
+
+			_, err := folderUseCase.MoveUrl(dto.MoveUrlRequest{Id: "abc", OwnerId: "acme", FolderId: "private"})
+
+			Expect(err).To(MatchError(folder.ErrNotFound))
+		})
+	})
+
+	Describe("GetUrlList", func() {
+		It("should list links of the folder and its subfolders like the folder stats", func() {
+			urlUseCase := &UrlUseCase{r: mockRepo, folders: mockFolders, tags: noTags(ctrl)}
+			mockFolders.EXPECT().FindByOwner("acme").Return(owned, nil)
+			filter := url.Filter{OwnerId: "acme", FolderIds: []string{"marketing", "spring", "emails"}}
+			mockRepo.EXPECT().FindAll(1, 10, filter).Return([]*url.Url{{Id: "abc", OwnerId: "acme", FolderId: "spring"}}, nil)
+
+			response, err := urlUseCase.GetUrlList(dto.PaginationRequest{Page: 1, Limit: 10, UrlListFilter: dto.UrlListFilter{OwnerId: "acme", FolderId: "marketing"}})
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(response.Data).To(HaveLen(1))
+			Expect(response.Data[0].FolderId).To(Equal("spring"))
+		})
+
+		It("should not list links of another owner's folder", func() {
+			urlUseCase := &UrlUseCase{r: mockRepo, folders: mockFolders}
+			mockFolders.EXPECT().FindByOwner("other").Return(nil, nil)
+
+			_, err := urlUseCase.GetUrlList(dto.PaginationRequest{Page: 1, Limit: 10, UrlListFilter: dto.UrlListFilter{OwnerId: "other", FolderId: "marketing"}})
+
+			Expect(err).To(MatchError(folder.ErrNotFound))
+		})
+	})
+
+	Describe("GetFolderStats", func() {
+		It("should aggregate clicks of the folder and its subfolders", func() {
+			mockClicks := clickMocks.NewMockRepositoryInterface(ctrl)
+			statsUseCase := &StatsUseCase{r: mockRepo, clicks: mockClicks, folders: mockFolders}
+			mockFolders.EXPECT().FindByOwner("acme").Return(owned, nil)
+
+			filter := click.Filter{OwnerId: "acme", FolderIds: []string{"marketing", "spring", "emails"}}
+			mockRepo.EXPECT().Count(url.Filter{OwnerId: "acme", FolderIds: filter.FolderIds}).Return(uint64(4), nil)
+			mockClicks.EXPECT().Count(filter).Return(uint64(12), nil)
+			mockClicks.EXPECT().CountBy(filter, gomock.Any(), statsBucketLimit).Return(nil, nil).Times(7)
+
+			response, err := statsUseCase.GetFolderStats(dto.FolderStatsRequest{Id: "marketing", StatsRequest: dto.StatsRequest{OwnerId: "acme"}})
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(response.Id).To(Equal("marketing"))
+			Expect(*response.Links).To(Equal(uint64(4)))
+			Expect(response.Clicks).To(Equal(uint64(12)))
+		})
+	})
+})
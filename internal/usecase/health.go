@@ -0,0 +1,253 @@
+package usecase
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"leenwood/yandex-http/config"
+	"leenwood/yandex-http/internal/domain/url"
+	"leenwood/yandex-http/internal/outbound"
+	"leenwood/yandex-http/internal/usecase/dto"
+	"net/http"
+	neturl "net/url"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	// healthBatchSize - сколько ссылок читается из базы за раз во время проверки
+	healthBatchSize = 500
+	// maxHealthErrorLength - наибольшая длина сохраняемой причины недоступности
+	maxHealthErrorLength = 500
+	// healthPollInterval - как часто ищутся ссылки, которым пора на проверку
+	healthPollInterval = time.Minute
+)
+
+type HealthUseCaseInterface interface {
+	GetBrokenLinks(request dto.BrokenLinksRequest) (dto.BrokenLinksResponse, error)
+}
+
+type HealthUseCase struct {
+	r url.RepositoryInterface
+}
+
+// NewHealthUseCase создаёт отчёт о неработающих ссылках и, если проверка включена,
+// запускает её в фоне до завершения ctx
+func NewHealthUseCase(ctx context.Context, config config.Config, repository url.RepositoryInterface) (*HealthUseCase, error) {
+	if config.Health.Interval > 0 {
+		checker := NewHealthChecker(repository, outbound.NewClient(config.Fetch), config.Health)
+		go checker.Run(ctx)
+	}
+	return &HealthUseCase{r: repository}, nil
+}
+
+// GetBrokenLinks возвращает ссылки владельца, страницы которых при последней проверке не ответили или вернули ошибку
+func (hs *HealthUseCase) GetBrokenLinks(request dto.BrokenLinksRequest) (dto.BrokenLinksResponse, error) {
+	filter := url.Filter{OwnerId: request.OwnerId, Broken: true}
+	urls, err := hs.r.FindAll(max(request.Page, 1), request.Limit, filter)
+	if err != nil {
+		return dto.BrokenLinksResponse{}, err
+	}
+	total, err := hs.r.Count(filter)
+	if err != nil {
+		return dto.BrokenLinksResponse{}, err
+	}
+
+	response := dto.BrokenLinksResponse{Data: make([]dto.BrokenLinkResponse, 0, len(urls)), Total: total}
+	for _, u := range urls {
+		response.Data = append(response.Data, dto.BrokenLinkResponse{
+			Id:          u.Id,
+			OriginalUrl: u.OriginalUrl,
+			Title:       u.Title,
+			Health:      *transformToUrlHealth(u.Health),
+		})
+	}
+	return response, nil
+}
+
+func transformToUrlHealth(health url.Health) *dto.UrlHealthResponse {
+	if health.CheckedDate == nil {
+		return nil
+	}
+	return &dto.UrlHealthResponse{
+		Status:    health.Status,
+		LatencyMs: health.Latency.Milliseconds(),
+		FinalUrl:  health.FinalUrl,
+		Error:     health.Error,
+		Broken:    health.IsBroken(),
+		CheckedAt: *health.CheckedDate,
+	}
+}
+
+// HealthChecker периодически запрашивает страницы назначения и сохраняет их ответ.
+// Ограничения по времени, адресам и перенаправлениям задаёт переданный клиент.
+type HealthChecker struct {
+	r      url.RepositoryInterface
+	client *http.Client
+	cfg    config.HealthConfig
+}
+
+func NewHealthChecker(r url.RepositoryInterface, client *http.Client, cfg config.HealthConfig) *HealthChecker {
+	return &HealthChecker{r: r, client: client, cfg: cfg}
+}
+
+// Run проверяет каждую ссылку раз в cfg.Interval, пока не завершится ctx. Время проверки
+// хранится в базе, поэтому после перезапуска проверяются только ссылки, которым пора.
+func (hc *HealthChecker) Run(ctx context.Context) {
+	ticker := time.NewTicker(min(hc.cfg.Interval, healthPollInterval))
+	defer ticker.Stop()
+	for {
+		// Ошибка базы прерывает только текущий проход, следующий начнётся по расписанию
+		_ = hc.CheckDue(ctx, time.Now().Add(-hc.cfg.Interval))
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// CheckDue проверяет все ссылки, не проверявшиеся с checkedBefore. Одновременно идёт
+// не больше cfg.Concurrency запросов, а запросы к одному хосту разнесены на cfg.HostDelay.
+func (hc *HealthChecker) CheckDue(ctx context.Context, checkedBefore time.Time) error {
+	hosts := &hostSchedule{delay: hc.cfg.HostDelay, next: make(map[string]time.Time)}
+	for ctx.Err() == nil {
+		urls, err := hc.r.FindCheckDue(checkedBefore, healthBatchSize)
+		if err != nil {
+			return err
+		}
+		if len(urls) == 0 {
+			return nil
+		}
+		if err = hc.checkBatch(ctx, urls, hosts); err != nil {
+			return err
+		}
+		if len(urls) < healthBatchSize {
+			return nil
+		}
+	}
+	return ctx.Err()
+}
+
+func (hc *HealthChecker) checkBatch(ctx context.Context, urls []*url.Url, hosts *hostSchedule) error {
+	jobs := make(chan *url.Url)
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		firstErr error
+	)
+	for range max(hc.cfg.Concurrency, 1) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for u := range jobs {
+				if err := hosts.wait(ctx, hostOf(destinationUrl(u))); err != nil {
+					continue
+				}
+				if err := hc.r.UpdateHealth(u.Id, hc.Check(ctx, destinationUrl(u))); err != nil {
+					mu.Lock()
+					firstErr = cmp.Or(firstErr, err)
+					mu.Unlock()
+				}
+			}
+		}()
+	}
+
+	for _, u := range urls {
+		jobs <- u
+	}
+	close(jobs)
+	wg.Wait()
+	return firstErr
+}
+
+// Check запрашивает страницу методом HEAD. Многие сайты отвечают на HEAD ошибкой,
+// поэтому ошибочный ответ перепроверяется запросом GET.
+func (hc *HealthChecker) Check(ctx context.Context, destination string) url.Health {
+	start := time.Now()
+	response, err := hc.request(ctx, http.MethodHead, destination)
+	if err == nil && response.StatusCode >= 400 {
+		start = time.Now()
+		response, err = hc.request(ctx, http.MethodGet, destination)
+	}
+
+	checked := time.Now()
+	health := url.Health{Latency: checked.Sub(start), CheckedDate: &checked}
+	if err != nil {
+		health.Error = healthError(err)
+		return health
+	}
+	health.Status = response.StatusCode
+	health.FinalUrl = response.Request.URL.String()
+	return health
+}
+
+// request выполняет запрос и сразу закрывает тело ответа, нужны только код и итоговый адрес
+func (hc *HealthChecker) request(ctx context.Context, method, destination string) (*http.Response, error) {
+	request, err := http.NewRequestWithContext(ctx, method, destination, nil)
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Set("User-Agent", "Mozilla/5.0 (compatible; LinkChecker/1.0)")
+
+	response, err := hc.client.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	response.Body.Close()
+	return response, nil
+}
+
+// healthError убирает из ошибки клиента метод и адрес, они и так известны
+func healthError(err error) string {
+	var urlErr *neturl.Error
+	if errors.As(err, &urlErr) {
+		err = urlErr.Err
+	}
+	// Postgres не примет в TEXT неверный UTF-8, поэтому обрезаем по границе символа
+	message := strings.ToValidUTF8(err.Error(), "")
+	if len(message) > maxHealthErrorLength {
+		end := maxHealthErrorLength
+		for end > 0 && !utf8.RuneStart(message[end]) {
+			end--
+		}
+		message = message[:end]
+	}
+	return message
+}
+
+func hostOf(destination string) string {
+	if parsed, err := neturl.Parse(destination); err == nil {
+		return strings.ToLower(parsed.Hostname())
+	}
+	return ""
+}
+
+// hostSchedule разносит запросы к одному хосту по времени: каждый запрос занимает
+// ближайшее свободное время не раньше delay после предыдущего
+type hostSchedule struct {
+	mu    sync.Mutex
+	delay time.Duration
+	next  map[string]time.Time
+}
+
+func (s *hostSchedule) wait(ctx context.Context, host string) error {
+	s.mu.Lock()
+	at := s.next[host]
+	if now := time.Now(); at.Before(now) {
+		at = now
+	}
+	s.next[host] = at.Add(s.delay)
+	s.mu.Unlock()
+
+	timer := time.NewTimer(time.Until(at))
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
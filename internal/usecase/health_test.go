@@ -0,0 +1,153 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"leenwood/yandex-http/config"
+	"leenwood/yandex-http/internal/domain/url"
+	"leenwood/yandex-http/internal/domain/url/mocks"
+	"leenwood/yandex-http/internal/usecase/dto"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Destination health", func() {
+	var (
+		ctrl     *gomock.Controller
+		mockRepo *mocks.MockRepositoryInterface
+		server   *httptest.Server
+		checker  *HealthChecker
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		mockRepo = mocks.NewMockRepositoryInterface(ctrl)
+		mux := http.NewServeMux()
+		mux.HandleFunc("/ok", func(w http.ResponseWriter, r *http.Request) {})
+		mux.HandleFunc("/moved", func(w http.ResponseWriter, r *http.Request) {
+			http.Redirect(w, r, "/ok", http.StatusMovedPermanently)
+		})
+		mux.HandleFunc("/no-head", func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == http.MethodHead {
+				w.WriteHeader(http.StatusMethodNotAllowed)
+			}
+		})
+		mux.HandleFunc("/gone", func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusGone)
+		})
+		server = httptest.NewServer(mux)
+		checker = NewHealthChecker(mockRepo, server.Client(), config.HealthConfig{Concurrency: 2})
+	})
+
+	AfterEach(func() {
+		server.Close()
+		ctrl.Finish()
+	})
+
+	Describe("Check", func() {
+		It("should record the status and the address after redirects", func() {
+			health := checker.Check(context.Background(), server.URL+"/moved")
+
+			Expect(health.Status).To(Equal(http.StatusOK))
+			Expect(health.FinalUrl).To(Equal(server.URL + "/ok"))
+			Expect(health.CheckedDate).NotTo(BeNil())
+			Expect(health.IsBroken()).To(BeFalse())
+		})
+
+		It("should retry with GET when HEAD is not supported", func() {
+			health := checker.Check(context.Background(), server.URL+"/no-head")
+
+			Expect(health.Status).To(Equal(http.StatusOK))
+			Expect(health.IsBroken()).To(BeFalse())
+		})
+
+		It("should flag error responses and unreachable pages", func() {
+			gone := checker.Check(context.Background(), server.URL+"/gone")
+			Expect(gone.Status).To(Equal(http.StatusGone))
+			Expect(gone.IsBroken()).To(BeTrue())
+
+			closed := httptest.NewServer(http.NotFoundHandler())
+			closed.Close()
+			unreachable := checker.Check(context.Background(), closed.URL)
+			Expect(unreachable.Status).To(BeZero())
+			Expect(unreachable.Error).To(ContainSubstring("connection refused"))
+			Expect(unreachable.IsBroken()).To(BeTrue())
+		})
+	})
+
+	Describe("CheckDue", func() {
+		It("should check and store every due link", func() {
+			before := time.Now()
+			mockRepo.EXPECT().FindCheckDue(before, healthBatchSize).Return([]*url.Url{
+				{Id: "ok", OriginalUrl: server.URL + "/ok"},
+				{Id: "gone", OriginalUrl: server.URL + "/gone"},
+			}, nil)
+
+			var mu sync.Mutex
+			statuses := make(map[string]int)
+			mockRepo.EXPECT().UpdateHealth(gomock.Any(), gomock.Any()).DoAndReturn(func(id string, health url.Health) error {
+				mu.Lock()
+				defer mu.Unlock()
+				statuses[id] = health.Status
+				return nil
+			}).Times(2)
+
+			Expect(checker.CheckDue(context.Background(), before)).To(Succeed())
+			Expect(statuses).To(Equal(map[string]int{"ok": http.StatusOK, "gone": http.StatusGone}))
+		})
+	})
+
+	Describe("hostSchedule", func() {
+		It("should space requests to the same host", func() {
+			hosts := &hostSchedule{delay: 50 * time.Millisecond, next: make(map[string]time.Time)}
+			start := time.Now()
+
+			Expect(hosts.wait(context.Background(), "example.com")).To(Succeed())
+			Expect(hosts.wait(context.Background(), "example.org")).To(Succeed())
+			Expect(time.Since(start)).To(BeNumerically("<", 50*time.Millisecond))
+
+			Expect(hosts.wait(context.Background(), "example.com")).To(Succeed())
+			Expect(time.Since(start)).To(BeNumerically(">=", 50*time.Millisecond))
+		})
+	})
+
+	Describe("healthError", func() {
+		It("should not cut a character in half", func() {
+			message := healthError(errors.New(strings.Repeat("я", maxHealthErrorLength)))
+
+			Expect(utf8.ValidString(message)).To(BeTrue())
+			Expect(len(message)).To(Equal(maxHealthErrorLength))
+		})
+	})
+
+	Describe("GetBrokenLinks", func() {
+		It("should list broken links of the owner", func() {
+			checked := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+			filter := url.Filter{OwnerId: "acme", Broken: true}
+			mockRepo.EXPECT().FindAll(1, 20, filter).Return([]*url.Url{{
+				Id:          "abc",
+				OriginalUrl: "https://example.com/old",
+				Health:      url.Health{Status: http.StatusNotFound, Latency: 120 * time.Millisecond, CheckedDate: &checked},
+			}}, nil)
+			mockRepo.EXPECT().Count(filter).Return(uint64(1), nil)
+
+			response, err := (&HealthUseCase{r: mockRepo}).GetBrokenLinks(dto.BrokenLinksRequest{OwnerId: "acme", Limit: 20})
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(response.Total).To(Equal(uint64(1)))
+			Expect(response.Data).To(Equal([]dto.BrokenLinkResponse{{
+				Id:          "abc",
+				OriginalUrl: "https://example.com/old",
+				Health:      dto.UrlHealthResponse{Status: http.StatusNotFound, LatencyMs: 120, Broken: true, CheckedAt: checked},
+			}}))
+		})
+	})
+})
@@ -0,0 +1,86 @@
+package usecase
+
+import (
+	"context"
+	"leenwood/yandex-http/config"
+	"leenwood/yandex-http/internal/domain/url"
+	"leenwood/yandex-http/internal/metadata"
+	"time"
+)
+
+// MetadataQueueInterface принимает новые ссылки для загрузки сведений о странице назначения
+type MetadataQueueInterface interface {
+	Enqueue(u *url.Url)
+}
+
+// MetadataWorker загружает заголовок, описание и картинки страниц назначения в фоне,
+// чтобы создание ссылки не ждало чужой сайт
+type MetadataWorker struct {
+	r       url.RepositoryInterface
+	fetcher metadata.FetcherInterface
+	timeout time.Duration
+	jobs    chan metadataJob
+}
+
+type metadataJob struct {
+	id          string
+	destination string
+}
+
+// NewMetadataWorker запускает cfg.MetadataWorkers обработчиков, они останавливаются вместе с ctx
+func NewMetadataWorker(ctx context.Context, r url.RepositoryInterface, fetcher metadata.FetcherInterface, cfg config.FetchConfig) *MetadataWorker {
+	w := &MetadataWorker{
+		r:       r,
+		fetcher: fetcher,
+		timeout: cfg.Timeout,
+		jobs:    make(chan metadataJob, max(cfg.MetadataQueueSize, 0)),
+	}
+	for range cfg.MetadataWorkers {
+		go w.run(ctx)
+	}
+	return w
+}
+
+// Enqueue не блокирует создание ссылки: если очередь переполнена, ссылка остаётся без сведений
+func (w *MetadataWorker) Enqueue(u *url.Url) {
+	select {
+	case w.jobs <- metadataJob{id: u.Id, destination: destinationUrl(u)}:
+	default:
+	}
+}
+
+func (w *MetadataWorker) run(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case job := <-w.jobs:
+			// Недоступная страница не мешает работе ссылки, сведения просто остаются пустыми
+			_ = w.process(ctx, job)
+		}
+	}
+}
+
+func (w *MetadataWorker) process(ctx context.Context, job metadataJob) error {
+	ctx, cancel := context.WithTimeout(ctx, w.timeout)
+	defer cancel()
+
+	page, err := w.fetcher.Fetch(ctx, job.destination)
+	if err != nil {
+		return err
+	}
+	return w.r.UpdateMetadata(job.id, url.Metadata{
+		Title:       page.Title,
+		Description: page.Description,
+		Image:       page.Image,
+		SiteName:    page.SiteName,
+		Favicon:     page.Favicon,
+	})
+}
+
+// enqueueMetadata ставит новую ссылку в очередь загрузки сведений, если загрузка включена
+func (us *UrlUseCase) enqueueMetadata(u *url.Url) {
+	if us.metadata != nil {
+		us.metadata.Enqueue(u)
+	}
+}
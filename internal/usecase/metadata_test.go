@@ -0,0 +1,109 @@
+package usecase
+
+import (
+	"context"
+	"leenwood/yandex-http/config"
+	"leenwood/yandex-http/internal/domain/url"
+	"leenwood/yandex-http/internal/domain/url/mocks"
+	"leenwood/yandex-http/internal/metadata"
+	"leenwood/yandex-http/internal/usecase/dto"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// recordingQueue запоминает ссылки, поставленные в очередь загрузки сведений
+type recordingQueue struct {
+	ids []string
+}
+
+func (q *recordingQueue) Enqueue(u *url.Url) {
+	q.ids = append(q.ids, u.Id)
+}
+
+var _ = Describe("Destination metadata", func() {
+	var (
+		ctrl     *gomock.Controller
+		mockRepo *mocks.MockRepositoryInterface
+		server   *httptest.Server
+		fetcher  *metadata.Fetcher
+		cfg      config.FetchConfig
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		mockRepo = mocks.NewMockRepositoryInterface(ctrl)
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/post" {
+				http.NotFound(w, r)
+				return
+			}
+			w.Header().Set("Content-Type", "text/html")
+			w.Write([]byte(`<title>Spring sale</title><meta name="description" content="Everything -50%">`))
+		}))
+		fetcher = metadata.NewFetcher(server.Client(), 1<<20)
+		cfg = config.FetchConfig{Timeout: time.Second, MetadataWorkers: 1, MetadataQueueSize: 10}
+	})
+
+	AfterEach(func() {
+		server.Close()
+		ctrl.Finish()
+	})
+
+	It("should queue created links but not existing ones", func() {
+		builder, err := NewShortUrlBuilder(config.AppConfig{Hostname: "localhost", Port: "8080"})
+		Expect(err).NotTo(HaveOccurred())
+		queue := &recordingQueue{}
+		urlUseCase := &UrlUseCase{r: mockRepo, b: builder, metadata: queue}
+
+		mockRepo.EXPECT().FindByDedupKey("https://example.com/new").Return(nil, nil)
+		mockRepo.EXPECT().Save(gomock.Any()).Return(&url.Url{Id: "new", OriginalUrl: "https://example.com/new"}, nil)
+		mockRepo.EXPECT().FindByDedupKey("https://example.com/old").Return(&url.Url{Id: "old"}, nil)
+
+		_, err = urlUseCase.CreateShortUrl(dto.CreateShortUrlUseCaseRequest{Url: "https://example.com/new"})
+		Expect(err).NotTo(HaveOccurred())
+		_, err = urlUseCase.CreateShortUrl(dto.CreateShortUrlUseCaseRequest{Url: "https://example.com/old"})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(queue.ids).To(Equal([]string{"new"}))
+	})
+
+	It("should store the page metadata in the background", func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		saved := make(chan url.Metadata, 1)
+		mockRepo.EXPECT().UpdateMetadata("abc", gomock.Any()).DoAndReturn(func(_ string, m url.Metadata) error {
+			saved <- m
+			return nil
+		})
+
+		NewMetadataWorker(ctx, mockRepo, fetcher, cfg).Enqueue(&url.Url{Id: "abc", OriginalUrl: server.URL + "/post"})
+
+		var stored url.Metadata
+		Eventually(saved).Should(Receive(&stored))
+		Expect(stored.Title).To(Equal("Spring sale"))
+		Expect(stored.Description).To(Equal("Everything -50%"))
+		Expect(stored.Favicon).To(Equal(server.URL + "/favicon.ico"))
+	})
+
+	It("should leave the link untouched when the page cannot be fetched", func() {
+		worker := NewMetadataWorker(context.Background(), mockRepo, fetcher, config.FetchConfig{Timeout: time.Second})
+
+		err := worker.process(context.Background(), metadataJob{id: "abc", destination: server.URL + "/gone"})
+
+		Expect(err).To(MatchError(ContainSubstring("404")))
+	})
+
+	It("should drop links when the queue is full instead of blocking", func() {
+		worker := NewMetadataWorker(context.Background(), mockRepo, fetcher, config.FetchConfig{MetadataQueueSize: 1})
+
+		worker.Enqueue(&url.Url{Id: "first"})
+		worker.Enqueue(&url.Url{Id: "second"})
+
+		Expect(worker.jobs).To(HaveLen(1))
+	})
+})
@@ -0,0 +1,101 @@
+package usecase
+
+import (
+	"leenwood/yandex-http/internal/domain/url"
+	"leenwood/yandex-http/internal/domain/url/mocks"
+	"leenwood/yandex-http/internal/usecase/dto"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Query and path passthrough", func() {
+	var (
+		ctrl       *gomock.Controller
+		mockRepo   *mocks.MockRepositoryInterface
+		urlUseCase UrlUseCaseInterface
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		mockRepo = mocks.NewMockRepositoryInterface(ctrl)
+		urlUseCase = &UrlUseCase{r: mockRepo, clicks: acceptClicks(ctrl), geo: noGeoIp, ua: agents}
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	Describe("ClickUrl", func() {
+		It("should drop the incoming query by default", func() {
+			mockUrl := &url.Url{Id: "abc", OriginalUrl: "https://example.com/page?a=1"}
+			mockRepo.EXPECT().FindById("abc").Return(mockUrl, nil)
+			mockRepo.EXPECT().IncrementClicks("abc").Return(nil)
+
+			response, err := urlUseCase.ClickUrl(dto.UrlClickRequest{Id: "abc", Query: map[string][]string{"b": {"2"}}})
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(response.Url).To(Equal("https://example.com/page?a=1"))
+			Expect(response.Private).To(BeFalse())
+		})
+
+		It("should append the path suffix and merge the query", func() {
+			mockUrl := &url.Url{Id: "docs", OriginalUrl: "https://example.com/docs/?lang=en", ForwardPath: true, QueryMode: url.QueryMerge}
+			mockRepo.EXPECT().FindById("docs").Return(mockUrl, nil)
+			mockRepo.EXPECT().IncrementClicks("docs").Return(nil)
+
+			response, err := urlUseCase.ClickUrl(dto.UrlClickRequest{
+				Id:    "docs",
+				Path:  "/guide/intro",
+				Query: map[string][]string{"lang": {"ru"}, "utm_source": {"x"}},
+			})
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(response.Url).To(Equal("https://example.com/docs/guide/intro?lang=en&utm_source=x"))
+			Expect(response.Private).To(BeTrue())
+		})
+
+		It("should not forward the path of a link without path forwarding", func() {
+			mockUrl := &url.Url{Id: "abc", OriginalUrl: "https://example.com"}
+			mockRepo.EXPECT().FindById("abc").Return(mockUrl, nil)
+
+			_, err := urlUseCase.ClickUrl(dto.UrlClickRequest{Id: "abc", Path: "/extra"})
+
+			Expect(err).To(MatchError(url.ErrNotFound))
+		})
+	})
+
+	Describe("forwardRequest", func() {
+		It("should resolve conflicts according to the query mode", func() {
+			query := map[string][]string{"a": {"2"}}
+
+			merged, err := forwardRequest("https://example.com/?a=1", &url.Url{QueryMode: url.QueryMerge}, "", query)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(merged).To(Equal("https://example.com/?a=1"))
+
+			overridden, err := forwardRequest("https://example.com/?a=1", &url.Url{QueryMode: url.QueryOverride}, "", query)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(overridden).To(Equal("https://example.com/?a=2"))
+
+			appended, err := forwardRequest("https://example.com/?a=1", &url.Url{QueryMode: url.QueryAppend}, "", query)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(appended).To(Equal("https://example.com/?a=1&a=2"))
+		})
+
+		It("should keep the suffix under the link path", func() {
+			forwarded, err := forwardRequest("https://example.com/docs", &url.Url{ForwardPath: true}, "/../../admin/", nil)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(forwarded).To(Equal("https://example.com/docs/admin/"))
+		})
+	})
+
+	Describe("CreateShortUrl", func() {
+		It("should reject an unknown query mode", func() {
+			_, err := urlUseCase.CreateShortUrl(dto.CreateShortUrlUseCaseRequest{Url: "example.com", QueryMode: "replace"})
+
+			Expect(err).To(MatchError(url.ErrInvalidQueryMode))
+		})
+	})
+})
@@ -0,0 +1,144 @@
+package usecase
+
+import (
+	"errors"
+	"leenwood/yandex-http/config"
+	"leenwood/yandex-http/internal/domain/url"
+	"leenwood/yandex-http/internal/domain/url/mocks"
+	"leenwood/yandex-http/internal/rateLimit/memoryStore"
+	"leenwood/yandex-http/internal/usecase/dto"
+	"sync"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"golang.org/x/crypto/bcrypt"
+)
+
+var _ = Describe("Password protected links", func() {
+	var (
+		ctrl       *gomock.Controller
+		mockRepo   *mocks.MockRepositoryInterface
+		urlUseCase UrlUseCaseInterface
+		protected  *url.Url
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		mockRepo = mocks.NewMockRepositoryInterface(ctrl)
+		cfg := config.Config{
+			App:       config.AppConfig{Hostname: "localhost", Port: "8080"},
+			RateLimit: config.RateLimitConfig{Unlock: config.LimitConfig{PerMinute: 1, Burst: 2}},
+		}
+		builder, err := NewShortUrlBuilder(cfg.App)
+		Expect(err).NotTo(HaveOccurred())
+		urlUseCase = &UrlUseCase{r: mockRepo, b: builder, l: memoryStore.NewStore(), c: cfg, clicks: acceptClicks(ctrl), geo: noGeoIp, ua: agents}
+
+		hash, err := bcrypt.GenerateFromPassword([]byte("secret"), bcrypt.MinCost)
+		Expect(err).NotTo(HaveOccurred())
+		protected = &url.Url{Id: "docs", OriginalUrl: "http://example.com", PasswordHash: string(hash)}
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	Describe("CreateShortUrl", func() {
+		It("should store a bcrypt hash and skip reuse of existing links", func() {
+			mockRepo.EXPECT().Save(gomock.Any()).DoAndReturn(func(model *url.Url) (*url.Url, error) {
+				Expect(bcrypt.CompareHashAndPassword([]byte(model.PasswordHash), []byte("secret"))).To(Succeed())
+				model.Id = "docs"
+				return model, nil
+			})
+
+			request := dto.CreateShortUrlUseCaseRequest{Url: "http://example.com", Password: "secret"}
+			response, err := urlUseCase.CreateShortUrl(request)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(response.Url).To(Equal("http://localhost:8080/docs"))
+		})
+	})
+
+	Describe("ClickUrl", func() {
+		It("should require a password when the link is locked", func() {
+			mockRepo.EXPECT().FindById("docs").Return(protected, nil)
+
+			_, err := urlUseCase.ClickUrl(dto.UrlClickRequest{Id: "docs"})
+
+			Expect(err).To(MatchError(url.ErrPasswordRequired))
+		})
+
+		It("should redirect when the link is unlocked", func() {
+			mockRepo.EXPECT().FindById("docs").Return(protected, nil)
+			mockRepo.EXPECT().IncrementClicks("docs").Return(nil)
+
+			response, err := urlUseCase.ClickUrl(dto.UrlClickRequest{Id: "docs", Unlocked: true})
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(response.Url).To(Equal("http://example.com"))
+			Expect(response.Private).To(BeTrue())
+		})
+	})
+
+	Describe("UnlockUrl", func() {
+		It("should accept the correct password", func() {
+			mockRepo.EXPECT().FindById("docs").Return(protected, nil)
+
+			Expect(urlUseCase.UnlockUrl(dto.UnlockUrlRequest{Id: "docs", Password: "secret"})).To(Succeed())
+		})
+
+		It("should reject a wrong password and limit attempts", func() {
+			mockRepo.EXPECT().FindById("docs").Return(protected, nil).Times(3)
+
+			Expect(urlUseCase.UnlockUrl(dto.UnlockUrlRequest{Id: "docs", Password: "wrong"})).To(MatchError(url.ErrInvalidPassword))
+			Expect(urlUseCase.UnlockUrl(dto.UnlockUrlRequest{Id: "docs", Password: "wrong"})).To(MatchError(url.ErrInvalidPassword))
+			Expect(urlUseCase.UnlockUrl(dto.UnlockUrlRequest{Id: "docs", Password: "secret"})).To(MatchError(url.ErrTooManyAttempts))
+		})
+
+		It("should not let concurrent wrong attempts past the limit", func() {
+			mockRepo.EXPECT().FindById("docs").Return(protected, nil).Times(10)
+
+			var (
+				wg       sync.WaitGroup
+				errs     = make(chan error, 10)
+				rejected int
+			)
+			for range 10 {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					errs <- urlUseCase.UnlockUrl(dto.UnlockUrlRequest{Id: "docs", Password: "wrong"})
+				}()
+			}
+			wg.Wait()
+			close(errs)
+
+			for err := range errs {
+				if errors.Is(err, url.ErrInvalidPassword) {
+					rejected++
+				} else {
+					Expect(err).To(MatchError(url.ErrTooManyAttempts))
+				}
+			}
+			Expect(rejected).To(Equal(2))
+		})
+
+		It("should not count successful attempts", func() {
+			mockRepo.EXPECT().FindById("docs").Return(protected, nil).Times(3)
+
+			for range 3 {
+				Expect(urlUseCase.UnlockUrl(dto.UnlockUrlRequest{Id: "docs", Password: "secret"})).To(Succeed())
+			}
+		})
+
+		It("should limit attempts per client address", func() {
+			mockRepo.EXPECT().FindById("docs").Return(protected, nil).Times(3)
+
+			for range 2 {
+				err := urlUseCase.UnlockUrl(dto.UnlockUrlRequest{Id: "docs", Password: "wrong", ClientIp: "192.0.2.1"})
+				Expect(err).To(MatchError(url.ErrInvalidPassword))
+			}
+			Expect(urlUseCase.UnlockUrl(dto.UnlockUrlRequest{Id: "docs", Password: "secret", ClientIp: "192.0.2.2"})).To(Succeed())
+		})
+	})
+})
@@ -0,0 +1,108 @@
+package usecase
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"leenwood/yandex-http/config"
+	"leenwood/yandex-http/internal/domain/click"
+	"leenwood/yandex-http/internal/domain/customDomain"
+	domainRepository "leenwood/yandex-http/internal/domain/customDomain/postgresRepository"
+	"leenwood/yandex-http/internal/domain/url"
+	"leenwood/yandex-http/internal/qrCode"
+	"leenwood/yandex-http/internal/usecase/dto"
+)
+
+type QrUseCaseInterface interface {
+	GetQr(request dto.UrlQrRequest) (dto.UrlQrResponse, error)
+}
+
+type QrUseCase struct {
+	r url.RepositoryInterface
+	d customDomain.RepositoryInterface
+	b ShortUrlBuilder
+	// logo - логотип для центра кода, nil если не настроен
+	logo *qrCode.Logo
+}
+
+func NewQrUseCase(ctx context.Context, config config.Config, urls url.RepositoryInterface) (*QrUseCase, error) {
+	domains, err := domainRepository.NewRepository(ctx, config.Database)
+	if err != nil {
+		return nil, err
+	}
+	builder, err := NewShortUrlBuilder(config.App)
+	if err != nil {
+		return nil, err
+	}
+	logo, err := qrCode.LoadLogo(config.App.QrLogoPath)
+	if err != nil {
+		return nil, err
+	}
+	return &QrUseCase{r: urls, d: domains, b: builder, logo: logo}, nil
+}
+
+// GetQr рисует QR-код короткой ссылки владельца с меткой источника перехода
+func (qs *QrUseCase) GetQr(request dto.UrlQrRequest) (dto.UrlQrResponse, error) {
+	u, err := findOwnedUrl(qs.r, request.Id, request.OwnerId)
+	if err != nil {
+		return dto.UrlQrResponse{}, err
+	}
+
+	options := qrCode.Options{
+		Format:     qrCode.Format(request.Format),
+		Size:       request.Size,
+		Level:      request.Level,
+		Margin:     qrCode.DefaultMargin,
+		Foreground: request.Foreground,
+		Background: request.Background,
+	}
+	if request.Margin != nil {
+		options.Margin = *request.Margin
+	}
+	if request.Logo {
+		if qs.logo == nil {
+			return dto.UrlQrResponse{}, fmt.Errorf("%w: logo is not configured", qrCode.ErrInvalidOptions)
+		}
+		options.Logo = qs.logo.Image
+	}
+	if options, err = qrCode.Normalize(options); err != nil {
+		return dto.UrlQrResponse{}, err
+	}
+
+	var domain *customDomain.Domain
+	if u.Domain != "" {
+		if domain, err = qs.d.FindByHostname(u.Domain); err != nil {
+			return dto.UrlQrResponse{}, err
+		}
+	}
+	// Метка источника отличает в статистике переходы по QR-коду
+	content := qs.b.Build(request.BaseUrl, domain, u.Id) + "?" + click.SourceParam + "=" + click.SourceQr
+
+	body, err := qrCode.Render(content, options)
+	if err != nil {
+		return dto.UrlQrResponse{}, err
+	}
+
+	contentType := "image/png"
+	if options.Format == qrCode.FormatSvg {
+		contentType = "image/svg+xml"
+	}
+	return dto.UrlQrResponse{
+		ContentType: contentType,
+		Body:        body,
+		ETag:        qs.etag(content, options),
+	}, nil
+}
+
+// etag зависит только от содержимого, параметров и файла логотипа, поэтому совпадает у одинаковых изображений
+func (qs *QrUseCase) etag(content string, options qrCode.Options) string {
+	var logo string
+	if options.Logo != nil {
+		logo = qs.logo.Digest
+	}
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%s|%s|%d|%s|%d|%s|%s|%s",
+		content, options.Format, options.Size, options.Level, options.Margin,
+		options.Foreground, options.Background, logo)))
+	return `"` + hex.EncodeToString(sum[:16]) + `"`
+}
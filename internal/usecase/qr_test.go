@@ -0,0 +1,69 @@
+package usecase
+
+import (
+	"leenwood/yandex-http/internal/domain/customDomain"
+	domainMocks "leenwood/yandex-http/internal/domain/customDomain/mocks"
+	"leenwood/yandex-http/internal/domain/url"
+	"leenwood/yandex-http/internal/domain/url/mocks"
+	"leenwood/yandex-http/internal/qrCode"
+	"leenwood/yandex-http/internal/usecase/dto"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("QrUseCase", func() {
+	var (
+		ctrl        *gomock.Controller
+		mockRepo    *mocks.MockRepositoryInterface
+		mockDomains *domainMocks.MockRepositoryInterface
+		qrUseCase   *QrUseCase
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		mockRepo = mocks.NewMockRepositoryInterface(ctrl)
+		mockDomains = domainMocks.NewMockRepositoryInterface(ctrl)
+		qrUseCase = &QrUseCase{r: mockRepo, d: mockDomains, b: ShortUrlBuilder{defaultBaseUrl: "http://localhost:9000"}}
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	It("should encode the short url on the link domain with the qr marker", func() {
+		mockRepo.EXPECT().FindById("abc").Return(&url.Url{Id: "abc", OwnerId: "acme", Domain: "go.acme.io"}, nil).Times(2)
+		mockDomains.EXPECT().FindByHostname("go.acme.io").
+			Return(&customDomain.Domain{Hostname: "go.acme.io", Scheme: "https"}, nil).Times(2)
+
+		response, err := qrUseCase.GetQr(dto.UrlQrRequest{Id: "abc", OwnerId: "acme", Format: "svg"})
+		Expect(err).NotTo(HaveOccurred())
+
+		options, _ := qrCode.Normalize(qrCode.Options{Format: qrCode.FormatSvg, Margin: qrCode.DefaultMargin})
+		Expect(response.ContentType).To(Equal("image/svg+xml"))
+		Expect(response.ETag).To(Equal(qrUseCase.etag("https://go.acme.io/abc?src=qr", options)))
+		Expect(string(response.Body)).To(HavePrefix("<svg "))
+
+		margin := 0
+		other, err := qrUseCase.GetQr(dto.UrlQrRequest{Id: "abc", OwnerId: "acme", Format: "svg", Margin: &margin})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(other.ETag).NotTo(Equal(response.ETag))
+	})
+
+	It("should hide links of other owners", func() {
+		mockRepo.EXPECT().FindById("abc").Return(&url.Url{Id: "abc", OwnerId: "other"}, nil)
+
+		_, err := qrUseCase.GetQr(dto.UrlQrRequest{Id: "abc", OwnerId: "acme"})
+
+		Expect(err).To(MatchError(url.ErrNotFound))
+	})
+
+	It("should reject a logo when none is configured", func() {
+		mockRepo.EXPECT().FindById("abc").Return(&url.Url{Id: "abc", OwnerId: "acme"}, nil)
+
+		_, err := qrUseCase.GetQr(dto.UrlQrRequest{Id: "abc", OwnerId: "acme", Logo: true})
+
+		Expect(err).To(MatchError(qrCode.ErrInvalidOptions))
+	})
+})
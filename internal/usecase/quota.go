@@ -0,0 +1,163 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"leenwood/yandex-http/config"
+	"leenwood/yandex-http/internal/domain/quota"
+	quotaRepository "leenwood/yandex-http/internal/domain/quota/postgresRepository"
+	"leenwood/yandex-http/internal/domain/url"
+	"leenwood/yandex-http/internal/usecase/dto"
+	"time"
+)
+
+type QuotaUseCaseInterface interface {
+	GetUsage(request dto.UsageRequest) (dto.UsageResponse, error)
+}
+
+type QuotaUseCase struct {
+	p quotaPolicy
+}
+
+func NewQuotaUseCase(ctx context.Context, config config.Config, urls url.RepositoryInterface) (*QuotaUseCase, error) {
+	policy, err := newQuotaPolicy(ctx, config, urls)
+	if err != nil {
+		return nil, err
+	}
+	return &QuotaUseCase{p: policy}, nil
+}
+
+func (qs *QuotaUseCase) GetUsage(request dto.UsageRequest) (dto.UsageResponse, error) {
+	limits, err := qs.p.limits(request.OwnerId)
+	if err != nil {
+		return dto.UsageResponse{}, err
+	}
+
+	counts, err := qs.p.r.CountByOwner(request.OwnerId)
+	if err != nil {
+		return dto.UsageResponse{}, err
+	}
+
+	period := currentPeriod()
+	clicks, err := qs.p.q.FindMonthlyClicks(request.OwnerId, period)
+	if err != nil {
+		return dto.UsageResponse{}, err
+	}
+
+	return dto.UsageResponse{
+		OwnerId: request.OwnerId,
+		Period:  period,
+		Limits: dto.QuotaLimits{
+			MaxLinks:         limits.MaxLinks,
+			MaxCustomAliases: limits.MaxCustomAliases,
+			MaxMonthlyClicks: limits.MaxMonthlyClicks,
+			AllowCustomId:    limits.AllowCustomId,
+		},
+		Usage: dto.QuotaUsage{
+			Links:         counts.Links,
+			CustomAliases: counts.CustomAliases,
+			MonthlyClicks: clicks,
+		},
+	}, nil
+}
+
+// quotaPolicy применяет лимиты тарифа к владельцам ссылок.
+// Анонимные ссылки (без владельца) ограничиваются суточным лимитом на адрес клиента.
+type quotaPolicy struct {
+	r    url.RepositoryInterface
+	q    quota.RepositoryInterface
+	plan config.QuotaConfig
+}
+
+func newQuotaPolicy(ctx context.Context, config config.Config, urls url.RepositoryInterface) (quotaPolicy, error) {
+	quotas, err := quotaRepository.NewRepository(ctx, config.Database)
+	if err != nil {
+		return quotaPolicy{}, err
+	}
+	return quotaPolicy{r: urls, q: quotas, plan: config.Quota}, nil
+}
+
+// limits возвращает квоту владельца, а при её отсутствии - тариф по умолчанию
+func (p quotaPolicy) limits(ownerId string) (*quota.Quota, error) {
+	limits, err := p.q.FindByOwner(ownerId)
+	if errors.Is(err, quota.ErrNotFound) {
+		return &quota.Quota{
+			OwnerId:          ownerId,
+			MaxLinks:         p.plan.MaxLinks,
+			MaxCustomAliases: p.plan.MaxCustomAliases,
+			MaxMonthlyClicks: p.plan.MaxMonthlyClicks,
+			AllowCustomId:    p.plan.AllowCustomId,
+		}, nil
+	}
+	return limits, err
+}
+
+// createLimit возвращает лимит, в пределах которого репозиторий сохраняет новые ссылки.
+// Собственный id недоступный тарифу запрещается сразу, число ссылок считается в транзакции сохранения.
+func (p quotaPolicy) createLimit(ownerId, clientIp string, customId bool) (url.Limit, error) {
+	if ownerId == "" {
+		return url.Limit{
+			CreatorIp: clientIp,
+			Since:     time.Now().Add(-24 * time.Hour),
+			MaxLinks:  p.plan.AnonymousDailyLinks,
+		}, nil
+	}
+
+	limits, err := p.limits(ownerId)
+	if err != nil {
+		return url.Limit{}, err
+	}
+	if customId && !limits.AllowCustomId {
+		return url.Limit{}, quota.ErrCustomIdNotAllowed
+	}
+	return url.Limit{OwnerId: ownerId, MaxLinks: limits.MaxLinks, MaxCustomAliases: limits.MaxCustomAliases}, nil
+}
+
+// exceeded переводит превышение лимита репозитория в ошибку квоты
+func exceeded(err error, limit url.Limit) error {
+	var limitErr *url.LimitError
+	if !errors.As(err, &limitErr) {
+		return err
+	}
+	name := limitErr.Limit
+	if name == "links" {
+		name = "active links"
+		if limit.OwnerId == "" {
+			name = "daily anonymous links"
+		}
+	}
+	return &quota.ExceededError{Limit: name, Max: limitErr.Max}
+}
+
+// trackClick учитывает переход в месячной статистике владельца.
+// Возвращает false, если месячный лимит учитываемых переходов исчерпан.
+func (p quotaPolicy) trackClick(ownerId string) (bool, error) {
+	if ownerId == "" {
+		return true, nil
+	}
+
+	limits, err := p.limits(ownerId)
+	if err != nil {
+		return false, err
+	}
+
+	period := currentPeriod()
+	if limits.MaxMonthlyClicks > 0 {
+		clicks, err := p.q.FindMonthlyClicks(ownerId, period)
+		if err != nil {
+			return false, err
+		}
+		if clicks >= limits.MaxMonthlyClicks {
+			return false, nil
+		}
+	}
+
+	if _, err = p.q.IncrementMonthlyClicks(ownerId, period); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func currentPeriod() string {
+	return time.Now().UTC().Format("2006-01")
+}
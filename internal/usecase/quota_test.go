@@ -0,0 +1,194 @@
+package usecase
+
+import (
+	"leenwood/yandex-http/config"
+	"leenwood/yandex-http/internal/domain/quota"
+	quotaMocks "leenwood/yandex-http/internal/domain/quota/mocks"
+	"leenwood/yandex-http/internal/domain/url"
+	"leenwood/yandex-http/internal/domain/url/mocks"
+	"leenwood/yandex-http/internal/usecase/dto"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Quotas", func() {
+	var (
+		ctrl       *gomock.Controller
+		mockRepo   *mocks.MockRepositoryInterface
+		mockQuotas *quotaMocks.MockRepositoryInterface
+		urlUseCase UrlUseCaseInterface
+		plan       config.QuotaConfig
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		mockRepo = mocks.NewMockRepositoryInterface(ctrl)
+		mockQuotas = quotaMocks.NewMockRepositoryInterface(ctrl)
+		plan = config.QuotaConfig{MaxLinks: 2, MaxCustomAliases: 1, MaxMonthlyClicks: 10, AllowCustomId: true}
+		builder, err := NewShortUrlBuilder(config.AppConfig{Hostname: "localhost", Port: "8080"})
+		Expect(err).NotTo(HaveOccurred())
+		urlUseCase = &UrlUseCase{r: mockRepo, b: builder, p: quotaPolicy{r: mockRepo, q: mockQuotas, plan: plan}, clicks: acceptClicks(ctrl), geo: noGeoIp, ua: agents}
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	Describe("CreateShortUrl", func() {
+		Context("when the owner has reached the link limit", func() {
+			It("should return a quota exceeded error", func() {
+				mockRepo.EXPECT().FindByDedupKey("http://example.com/").Return(nil, nil)
+				mockQuotas.EXPECT().FindByOwner("alice").Return(nil, quota.ErrNotFound)
+				mockRepo.EXPECT().SaveWithin(gomock.Len(1), url.Limit{OwnerId: "alice", MaxLinks: 2, MaxCustomAliases: 1}).
+					Return(nil, &url.LimitError{Limit: "links", Max: 2})
+
+				request := dto.CreateShortUrlUseCaseRequest{Url: "http://example.com", OwnerId: "alice"}
+				_, err := urlUseCase.CreateShortUrl(request)
+
+				Expect(err).To(MatchError(quota.ErrExceeded))
+				Expect(err.Error()).To(ContainSubstring("active links limit is 2"))
+			})
+		})
+
+		Context("when the owner plan forbids custom ids", func() {
+			It("should return an error", func() {
+				mockQuotas.EXPECT().FindByOwner("alice").Return(&quota.Quota{OwnerId: "alice"}, nil)
+
+				request := dto.CreateShortUrlWithCustomIdRequest{Url: "http://example.com", Id: "bio", OwnerId: "alice"}
+				_, err := urlUseCase.CreateShortUrlWithCustomId(request)
+
+				Expect(err).To(MatchError(quota.ErrCustomIdNotAllowed))
+			})
+		})
+
+		Context("when the owner is within the limits", func() {
+			It("should save the link with the owner", func() {
+				mockQuotas.EXPECT().FindByOwner("alice").Return(nil, quota.ErrNotFound)
+				saved := &url.Url{Id: "bio", OriginalUrl: "http://example.com", OwnerId: "alice", CustomId: true}
+				mockRepo.EXPECT().SaveWithin(
+					[]*url.Url{{OriginalUrl: "http://example.com", Id: "bio", OwnerId: "alice", CustomId: true}},
+					url.Limit{OwnerId: "alice", MaxLinks: 2, MaxCustomAliases: 1},
+				).Return([]*url.Url{saved}, nil)
+
+				request := dto.CreateShortUrlWithCustomIdRequest{Url: "http://example.com", Id: "bio", OwnerId: "alice"}
+				response, err := urlUseCase.CreateShortUrlWithCustomId(request)
+
+				Expect(err).NotTo(HaveOccurred())
+				Expect(response.Url).To(Equal("http://localhost:8080/bio"))
+			})
+		})
+	})
+
+	Describe("CreateShortUrl without an owner", func() {
+		BeforeEach(func() {
+			plan.AnonymousDailyLinks = 3
+			urlUseCase.(*UrlUseCase).p.plan = plan
+		})
+
+		It("should limit links created from the client address in the last day", func() {
+			mockRepo.EXPECT().FindByDedupKey("http://example.com/").Return(nil, nil)
+			mockRepo.EXPECT().SaveWithin(gomock.Len(1), gomock.Any()).DoAndReturn(func(models []*url.Url, limit url.Limit) ([]*url.Url, error) {
+				Expect(models[0].CreatorIp).To(Equal("10.0.0.1"))
+				Expect(limit.CreatorIp).To(Equal("10.0.0.1"))
+				Expect(limit.OwnerId).To(BeEmpty())
+				Expect(limit.MaxLinks).To(Equal(uint64(3)))
+				Expect(limit.Since).To(BeTemporally("~", time.Now().Add(-24*time.Hour), time.Minute))
+				return nil, &url.LimitError{Limit: "links", Max: 3}
+			})
+
+			request := dto.CreateShortUrlUseCaseRequest{Url: "http://example.com", ClientIp: "10.0.0.1"}
+			_, err := urlUseCase.CreateShortUrl(request)
+
+			Expect(err).To(MatchError(quota.ErrExceeded))
+			Expect(err.Error()).To(ContainSubstring("daily anonymous links limit is 3"))
+		})
+
+		It("should not record the client address on owner links", func() {
+			mockQuotas.EXPECT().FindByOwner("alice").Return(nil, quota.ErrNotFound)
+			mockRepo.EXPECT().SaveWithin(gomock.Len(1), gomock.Any()).DoAndReturn(func(models []*url.Url, limit url.Limit) ([]*url.Url, error) {
+				Expect(models[0].CreatorIp).To(BeEmpty())
+				Expect(limit.CreatorIp).To(BeEmpty())
+				return models, nil
+			})
+
+			request := dto.CreateShortUrlWithCustomIdRequest{Url: "http://example.com", Id: "bio", OwnerId: "alice", ClientIp: "10.0.0.1"}
+			_, err := urlUseCase.CreateShortUrlWithCustomId(request)
+
+			Expect(err).NotTo(HaveOccurred())
+		})
+	})
+
+	Describe("ClickUrl", func() {
+		Context("when the monthly click limit is reached", func() {
+			It("should redirect without counting the click", func() {
+				mockUrl := &url.Url{Id: "bio", OriginalUrl: "http://example.com", OwnerId: "alice", ClickCount: 10}
+				mockRepo.EXPECT().FindById("bio").Return(mockUrl, nil)
+				mockQuotas.EXPECT().FindByOwner("alice").Return(nil, quota.ErrNotFound)
+				mockQuotas.EXPECT().FindMonthlyClicks("alice", gomock.Any()).Return(uint64(10), nil)
+
+				response, err := urlUseCase.ClickUrl(dto.UrlClickRequest{Id: "bio"})
+
+				Expect(err).NotTo(HaveOccurred())
+				Expect(response.Url).To(Equal("http://example.com"))
+				Expect(mockUrl.ClickCount).To(Equal(uint64(10)))
+			})
+		})
+
+		Context("when the monthly click limit is not reached", func() {
+			It("should count the click", func() {
+				mockUrl := &url.Url{Id: "bio", OriginalUrl: "http://example.com", OwnerId: "alice"}
+				mockRepo.EXPECT().FindById("bio").Return(mockUrl, nil)
+				mockQuotas.EXPECT().FindByOwner("alice").Return(nil, quota.ErrNotFound)
+				mockQuotas.EXPECT().FindMonthlyClicks("alice", gomock.Any()).Return(uint64(3), nil)
+				mockQuotas.EXPECT().IncrementMonthlyClicks("alice", gomock.Any()).Return(uint64(4), nil)
+				mockRepo.EXPECT().IncrementClicks("bio").Return(nil)
+
+				_, err := urlUseCase.ClickUrl(dto.UrlClickRequest{Id: "bio"})
+
+				Expect(err).NotTo(HaveOccurred())
+				Expect(mockUrl.ClickCount).To(Equal(uint64(1)))
+			})
+		})
+	})
+
+	Describe("GetUsage", func() {
+		It("should report usage against the owner limits", func() {
+			quotaUseCase := &QuotaUseCase{p: quotaPolicy{r: mockRepo, q: mockQuotas, plan: plan}}
+			mockQuotas.EXPECT().FindByOwner("alice").Return(&quota.Quota{OwnerId: "alice", MaxLinks: 50}, nil)
+			mockRepo.EXPECT().CountByOwner("alice").Return(url.Counts{Links: 7, CustomAliases: 2}, nil)
+			mockQuotas.EXPECT().FindMonthlyClicks("alice", gomock.Any()).Return(uint64(42), nil)
+
+			response, err := quotaUseCase.GetUsage(dto.UsageRequest{OwnerId: "alice"})
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(response.Limits.MaxLinks).To(Equal(uint64(50)))
+			Expect(response.Usage).To(Equal(dto.QuotaUsage{Links: 7, CustomAliases: 2, MonthlyClicks: 42}))
+		})
+	})
+})
+
+var _ = Describe("Anonymous link quota", func() {
+	var repository url.RepositoryInterface
+
+	BeforeEach(func() {
+		repository = newSqliteUrls()
+	})
+
+	It("should count anonymous links per client address when saving them", func() {
+		urlUseCase := &UrlUseCase{r: repository, p: quotaPolicy{r: repository, plan: config.QuotaConfig{AnonymousDailyLinks: 2}}}
+
+		for _, link := range []string{"https://example.com/1", "https://example.com/2"} {
+			_, err := urlUseCase.CreateShortUrl(dto.CreateShortUrlUseCaseRequest{Url: link, ClientIp: "10.0.0.1"})
+			Expect(err).NotTo(HaveOccurred())
+		}
+
+		_, err := urlUseCase.CreateShortUrl(dto.CreateShortUrlUseCaseRequest{Url: "https://example.com/3", ClientIp: "10.0.0.1"})
+		Expect(err).To(MatchError(quota.ErrExceeded))
+
+		_, err = urlUseCase.CreateShortUrl(dto.CreateShortUrlUseCaseRequest{Url: "https://example.com/3", ClientIp: "10.0.0.2"})
+		Expect(err).NotTo(HaveOccurred())
+	})
+})
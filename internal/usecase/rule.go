@@ -0,0 +1,96 @@
+package usecase
+
+import (
+	"context"
+	"leenwood/yandex-http/config"
+	"leenwood/yandex-http/internal/domain/rule"
+	ruleRepository "leenwood/yandex-http/internal/domain/rule/postgresRepository"
+	"leenwood/yandex-http/internal/domain/url"
+	"leenwood/yandex-http/internal/usecase/dto"
+)
+
+type RuleUseCaseInterface interface {
+	GetRules(request dto.UrlRulesRequest) (dto.UrlRulesResponse, error)
+	ReplaceRules(request dto.ReplaceUrlRulesRequest) (dto.UrlRulesResponse, error)
+}
+
+type RuleUseCase struct {
+	r     url.RepositoryInterface
+	rules rule.RepositoryInterface
+}
+
+func NewRuleUseCase(ctx context.Context, config config.Config, urls url.RepositoryInterface) (*RuleUseCase, error) {
+	rules, err := ruleRepository.NewRepository(ctx, config.Database)
+	if err != nil {
+		return nil, err
+	}
+	return &RuleUseCase{r: urls, rules: rules}, nil
+}
+
+func (rs *RuleUseCase) GetRules(request dto.UrlRulesRequest) (dto.UrlRulesResponse, error) {
+	u, err := findOwnedUrl(rs.r, request.Id, request.OwnerId)
+	if err != nil {
+		return dto.UrlRulesResponse{}, err
+	}
+
+	rules, err := rs.rules.FindByUrl(u.Id)
+	if err != nil {
+		return dto.UrlRulesResponse{}, err
+	}
+	return transformToRulesResponse(u.Id, rules), nil
+}
+
+// ReplaceRules проверяет и сохраняет новый список правил ссылки вместо прежнего
+func (rs *RuleUseCase) ReplaceRules(request dto.ReplaceUrlRulesRequest) (dto.UrlRulesResponse, error) {
+	u, err := findOwnedUrl(rs.r, request.Id, request.OwnerId)
+	if err != nil {
+		return dto.UrlRulesResponse{}, err
+	}
+
+	rules := make([]*rule.Rule, 0, len(request.Rules))
+	for _, r := range request.Rules {
+		rules = append(rules, &rule.Rule{
+			UrlId:     u.Id,
+			Condition: rule.Condition(r.Condition),
+			TargetUrl: r.TargetUrl,
+		})
+	}
+	if err = rule.Validate(rules); err != nil {
+		return dto.UrlRulesResponse{}, err
+	}
+
+	if err = rs.rules.Replace(u.Id, rules); err != nil {
+		return dto.UrlRulesResponse{}, err
+	}
+
+	if hasRules := len(rules) > 0; u.HasRules != hasRules {
+		u.HasRules = hasRules
+		if _, err = rs.r.Update(u); err != nil {
+			return dto.UrlRulesResponse{}, err
+		}
+	}
+	return transformToRulesResponse(u.Id, rules), nil
+}
+
+// findOwnedUrl находит ссылку владельца, чужие ссылки считаются несуществующими
+func findOwnedUrl(r url.RepositoryInterface, id, ownerId string) (*url.Url, error) {
+	u, err := r.FindById(id)
+	if err != nil {
+		return nil, err
+	}
+	if ownerId == "" || u.OwnerId != ownerId {
+		return nil, url.ErrNotFound
+	}
+	return u, nil
+}
+
+func transformToRulesResponse(id string, rules []*rule.Rule) dto.UrlRulesResponse {
+	response := dto.UrlRulesResponse{Id: id, Rules: []dto.UrlRule{}}
+	for _, r := range rules {
+		response.Rules = append(response.Rules, dto.UrlRule{
+			Condition: dto.RuleCondition(r.Condition),
+			TargetUrl: r.TargetUrl,
+		})
+	}
+	return response
+}
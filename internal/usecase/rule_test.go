@@ -0,0 +1,148 @@
+package usecase
+
+import (
+	"leenwood/yandex-http/internal/domain/rule"
+	ruleMocks "leenwood/yandex-http/internal/domain/rule/mocks"
+	"leenwood/yandex-http/internal/domain/url"
+	"leenwood/yandex-http/internal/domain/url/mocks"
+	"leenwood/yandex-http/internal/geoIp"
+	geoMocks "leenwood/yandex-http/internal/geoIp/mocks"
+	"leenwood/yandex-http/internal/usecase/dto"
+	"net"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+const iphoneUserAgent = "Mozilla/5.0 (iPhone; CPU iPhone OS 17_0 like Mac OS X) AppleWebKit/605.1.15 Mobile/15E148"
+
+var _ = Describe("Routing rules", func() {
+	var (
+		ctrl      *gomock.Controller
+		mockRepo  *mocks.MockRepositoryInterface
+		mockRules *ruleMocks.MockRepositoryInterface
+		mockGeo   *geoMocks.MockResolverInterface
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		mockRepo = mocks.NewMockRepositoryInterface(ctrl)
+		mockRules = ruleMocks.NewMockRepositoryInterface(ctrl)
+		mockGeo = geoMocks.NewMockResolverInterface(ctrl)
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	Describe("ClickUrl", func() {
+		var (
+			mockUrl    *url.Url
+			urlUseCase UrlUseCaseInterface
+		)
+
+		BeforeEach(func() {
+			mockUrl = &url.Url{Id: "app", OriginalUrl: "https://example.com", HasRules: true}
+			urlUseCase = &UrlUseCase{r: mockRepo, rules: mockRules, ua: agents, geo: mockGeo, clicks: acceptClicks(ctrl)}
+			mockRepo.EXPECT().FindById("app").Return(mockUrl, nil)
+			mockRepo.EXPECT().IncrementClicks("app").Return(nil)
+			mockRules.EXPECT().FindByUrl("app").Return([]*rule.Rule{
+				{Position: 0, Condition: rule.Condition{Os: []string{"ios"}}, TargetUrl: "https://apps.apple.com/app"},
+				{Position: 1, Condition: rule.Condition{Countries: []string{"DE"}, Languages: []string{"de"}}, TargetUrl: "https://example.de"},
+			}, nil)
+		})
+
+		It("should send the client to the first matching rule", func() {
+			mockGeo.EXPECT().Lookup(gomock.Any()).Return(geoIp.Location{}, nil)
+
+			response, err := urlUseCase.ClickUrl(dto.UrlClickRequest{Id: "app", UserAgent: iphoneUserAgent})
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(response.Url).To(Equal("https://apps.apple.com/app"))
+			Expect(response.Private).To(BeTrue())
+		})
+
+		It("should match the country and the preferred language", func() {
+			mockGeo.EXPECT().Lookup(net.ParseIP("192.0.2.1")).Return(geoIp.Location{Country: "DE"}, nil)
+
+			response, err := urlUseCase.ClickUrl(dto.UrlClickRequest{
+				Id:             "app",
+				ClientIp:       "192.0.2.1",
+				AcceptLanguage: "en;q=0.5, de-AT",
+			})
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(response.Url).To(Equal("https://example.de"))
+		})
+
+		It("should fall back to the link destination", func() {
+			mockGeo.EXPECT().Lookup(gomock.Any()).Return(geoIp.Location{Country: "FR"}, nil)
+
+			response, err := urlUseCase.ClickUrl(dto.UrlClickRequest{Id: "app"})
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(response.Url).To(Equal("https://example.com"))
+			Expect(response.Private).To(BeTrue())
+		})
+	})
+
+	Describe("ReplaceRules", func() {
+		var ruleUseCase RuleUseCaseInterface
+
+		BeforeEach(func() {
+			ruleUseCase = &RuleUseCase{r: mockRepo, rules: mockRules}
+		})
+
+		It("should validate, store the rules and mark the link", func() {
+			mockUrl := &url.Url{Id: "app", OwnerId: "acme"}
+			mockRepo.EXPECT().FindById("app").Return(mockUrl, nil)
+			mockRules.EXPECT().Replace("app", gomock.Len(1)).Return(nil)
+			mockRepo.EXPECT().Update(gomock.Any()).DoAndReturn(func(u *url.Url) (*url.Url, error) {
+				Expect(u.HasRules).To(BeTrue())
+				return u, nil
+			})
+
+			response, err := ruleUseCase.ReplaceRules(dto.ReplaceUrlRulesRequest{
+				Id:      "app",
+				OwnerId: "acme",
+				Rules: []dto.UrlRule{
+					{Condition: dto.RuleCondition{Devices: []string{"Mobile"}, Countries: []string{"us"}}, TargetUrl: "https://m.example.com"},
+				},
+			})
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(response.Rules[0].Condition.Devices).To(Equal([]string{"mobile"}))
+			Expect(response.Rules[0].Condition.Countries).To(Equal([]string{"US"}))
+		})
+
+		It("should reject rules without conditions or with a relative target", func() {
+			mockRepo.EXPECT().FindById("app").Return(&url.Url{Id: "app", OwnerId: "acme"}, nil).Times(2)
+
+			_, err := ruleUseCase.ReplaceRules(dto.ReplaceUrlRulesRequest{
+				Id: "app", OwnerId: "acme", Rules: []dto.UrlRule{{TargetUrl: "https://example.com"}},
+			})
+			Expect(err).To(MatchError(rule.ErrInvalidRule))
+
+			_, err = ruleUseCase.ReplaceRules(dto.ReplaceUrlRulesRequest{
+				Id: "app", OwnerId: "acme", Rules: []dto.UrlRule{{Condition: dto.RuleCondition{Os: []string{"ios"}}, TargetUrl: "/app"}},
+			})
+			Expect(err).To(MatchError(rule.ErrInvalidRule))
+		})
+
+		It("should hide links of other owners", func() {
+			mockRepo.EXPECT().FindById("app").Return(&url.Url{Id: "app", OwnerId: "other"}, nil)
+
+			_, err := ruleUseCase.GetRules(dto.UrlRulesRequest{Id: "app", OwnerId: "acme"})
+
+			Expect(err).To(MatchError(url.ErrNotFound))
+		})
+	})
+
+	Describe("preferredLanguage", func() {
+		It("should pick the tag with the highest weight", func() {
+			Expect(preferredLanguage("fr;q=0.3, en-US;q=0.9, *;q=1")).To(Equal("en-us"))
+			Expect(preferredLanguage("")).To(Equal(""))
+		})
+	})
+})
@@ -0,0 +1,68 @@
+package usecase
+
+import (
+	"fmt"
+	"leenwood/yandex-http/config"
+	"leenwood/yandex-http/internal/domain/customDomain"
+	neturl "net/url"
+	"strings"
+)
+
+// ShortUrlBuilder собирает публичный адрес короткой ссылки.
+// Приоритет базового адреса: брендированный домен ссылки, PUBLIC_BASE_URL,
+// адрес из доверенных заголовков X-Forwarded-*, затем HOSTNAME и PORT.
+type ShortUrlBuilder struct {
+	publicBaseUrl  string
+	defaultBaseUrl string
+}
+
+func NewShortUrlBuilder(cfg config.AppConfig) (ShortUrlBuilder, error) {
+	builder := ShortUrlBuilder{
+		defaultBaseUrl: fmt.Sprintf("http://%s:%s", cfg.Hostname, cfg.Port),
+	}
+	if cfg.PublicBaseUrl == "" {
+		return builder, nil
+	}
+
+	base, err := neturl.Parse(cfg.PublicBaseUrl)
+	if err != nil {
+		return builder, fmt.Errorf("invalid PUBLIC_BASE_URL: %w", err)
+	}
+	if (base.Scheme != "http" && base.Scheme != "https") || base.Host == "" {
+		return builder, fmt.Errorf("invalid PUBLIC_BASE_URL: expected absolute http(s) URL, got %q", cfg.PublicBaseUrl)
+	}
+	builder.publicBaseUrl = strings.TrimRight(base.String(), "/")
+	return builder, nil
+}
+
+// Build возвращает короткую ссылку для id. requestBaseUrl - адрес, восстановленный
+// из заголовков доверенного прокси, может быть пустым.
+func (b ShortUrlBuilder) Build(requestBaseUrl string, domain *customDomain.Domain, id string) string {
+	return b.BaseUrl(requestBaseUrl, domain) + "/" + neturl.PathEscape(id)
+}
+
+// BaseUrl возвращает базовый адрес без завершающего слэша
+func (b ShortUrlBuilder) BaseUrl(requestBaseUrl string, domain *customDomain.Domain) string {
+	switch {
+	case domain != nil:
+		return fmt.Sprintf("%s://%s", domain.Scheme, domain.Hostname)
+	case b.publicBaseUrl != "":
+		return b.publicBaseUrl
+	case requestBaseUrl != "":
+		return strings.TrimRight(requestBaseUrl, "/")
+	default:
+		return b.defaultBaseUrl
+	}
+}
+
+// ownBaseUrls возвращает базовые адреса коротких ссылок домена по умолчанию, по которым
+// сервис может быть доступен для этого запроса
+func (b ShortUrlBuilder) ownBaseUrls(requestBaseUrl string) []string {
+	bases := []string{b.defaultBaseUrl}
+	for _, base := range []string{b.publicBaseUrl, strings.TrimRight(requestBaseUrl, "/")} {
+		if base != "" {
+			bases = append(bases, base)
+		}
+	}
+	return bases
+}
@@ -0,0 +1,62 @@
+package usecase
+
+import (
+	"leenwood/yandex-http/config"
+	"leenwood/yandex-http/internal/domain/customDomain"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ShortUrlBuilder", func() {
+	appConfig := config.AppConfig{Hostname: "host.docker.internal", Port: "9000"}
+
+	Context("when nothing else is configured", func() {
+		It("should fall back to HOSTNAME and PORT with a scheme", func() {
+			builder, err := NewShortUrlBuilder(appConfig)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(builder.Build("", nil, "abc")).To(Equal("http://host.docker.internal:9000/abc"))
+		})
+	})
+
+	Context("when a forwarded base URL is known", func() {
+		It("should use it instead of HOSTNAME and PORT", func() {
+			builder, err := NewShortUrlBuilder(appConfig)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(builder.Build("https://sho.rt/links/", nil, "abc")).To(Equal("https://sho.rt/links/abc"))
+		})
+	})
+
+	Context("when PUBLIC_BASE_URL is configured", func() {
+		It("should take precedence over forwarded headers and keep the path prefix", func() {
+			cfg := appConfig
+			cfg.PublicBaseUrl = "https://sho.rt/s/"
+			builder, err := NewShortUrlBuilder(cfg)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(builder.Build("http://proxy.local", nil, "abc")).To(Equal("https://sho.rt/s/abc"))
+		})
+
+		It("should reject a relative URL", func() {
+			cfg := appConfig
+			cfg.PublicBaseUrl = "sho.rt/s"
+			_, err := NewShortUrlBuilder(cfg)
+
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Context("when the link belongs to a custom domain", func() {
+		It("should use the domain scheme and hostname", func() {
+			cfg := appConfig
+			cfg.PublicBaseUrl = "https://sho.rt"
+			builder, err := NewShortUrlBuilder(cfg)
+			Expect(err).NotTo(HaveOccurred())
+
+			domain := &customDomain.Domain{Hostname: "go.acme.io", Scheme: "https"}
+			Expect(builder.Build("", domain, "abc")).To(Equal("https://go.acme.io/abc"))
+		})
+	})
+})
@@ -0,0 +1,141 @@
+package usecase
+
+import (
+	"context"
+	"leenwood/yandex-http/config"
+	"leenwood/yandex-http/internal/domain/click"
+	clickRepository "leenwood/yandex-http/internal/domain/click/postgresRepository"
+	"leenwood/yandex-http/internal/domain/folder"
+	folderRepository "leenwood/yandex-http/internal/domain/folder/postgresRepository"
+	"leenwood/yandex-http/internal/domain/url"
+	"leenwood/yandex-http/internal/usecase/dto"
+)
+
+// statsBucketLimit - сколько самых частых значений возвращается в каждой разбивке
+const statsBucketLimit = 50
+
+type StatsUseCaseInterface interface {
+	GetStats(request dto.StatsRequest) (dto.StatsResponse, error)
+	GetUrlStats(request dto.UrlStatsRequest) (dto.StatsResponse, error)
+	GetFolderStats(request dto.FolderStatsRequest) (dto.StatsResponse, error)
+}
+
+type StatsUseCase struct {
+	r       url.RepositoryInterface
+	clicks  click.RepositoryInterface
+	folders folder.RepositoryInterface
+}
+
+func NewStatsUseCase(ctx context.Context, config config.Config, urls url.RepositoryInterface) (*StatsUseCase, error) {
+	clicks, err := clickRepository.NewRepository(ctx, config.Database)
+	if err != nil {
+		return nil, err
+	}
+	folders, err := folderRepository.NewRepository(ctx, config.Database)
+	if err != nil {
+		return nil, err
+	}
+	return &StatsUseCase{r: urls, clicks: clicks, folders: folders}, nil
+}
+
+// GetStats возвращает статистику переходов по всем ссылкам владельца
+func (ss *StatsUseCase) GetStats(request dto.StatsRequest) (dto.StatsResponse, error) {
+	filter, err := newClickFilter(request)
+	if err != nil {
+		return dto.StatsResponse{}, err
+	}
+	return ss.stats(filter)
+}
+
+// GetUrlStats возвращает статистику переходов по ссылке владельца
+func (ss *StatsUseCase) GetUrlStats(request dto.UrlStatsRequest) (dto.StatsResponse, error) {
+	u, err := findOwnedUrl(ss.r, request.Id, request.OwnerId)
+	if err != nil {
+		return dto.StatsResponse{}, err
+	}
+
+	filter, err := newClickFilter(request.StatsRequest)
+	if err != nil {
+		return dto.StatsResponse{}, err
+	}
+	filter.UrlId = u.Id
+
+	response, err := ss.stats(filter)
+	response.Id = u.Id
+	return response, err
+}
+
+// GetFolderStats возвращает статистику переходов по ссылкам папки и всех вложенных в неё папок
+func (ss *StatsUseCase) GetFolderStats(request dto.FolderStatsRequest) (dto.StatsResponse, error) {
+	tree, err := ownerFolders(ss.folders, request.OwnerId)
+	if err != nil {
+		return dto.StatsResponse{}, err
+	}
+	if _, ok := tree[request.Id]; !ok {
+		return dto.StatsResponse{}, folder.ErrNotFound
+	}
+
+	filter, err := newClickFilter(request.StatsRequest)
+	if err != nil {
+		return dto.StatsResponse{}, err
+	}
+	filter.FolderIds = tree.Subtree(request.Id)
+
+	links, err := ss.r.Count(url.Filter{OwnerId: request.OwnerId, FolderIds: filter.FolderIds})
+	if err != nil {
+		return dto.StatsResponse{}, err
+	}
+
+	response, err := ss.stats(filter)
+	response.Id = request.Id
+	response.Links = &links
+	return response, err
+}
+
+func (ss *StatsUseCase) stats(filter click.Filter) (dto.StatsResponse, error) {
+	total, err := ss.clicks.Count(filter)
+	if err != nil {
+		return dto.StatsResponse{}, err
+	}
+
+	response := dto.StatsResponse{Clicks: total}
+	for dimension, target := range map[click.Dimension]*[]dto.StatsBucket{
+		click.DimensionCountry: &response.Countries,
+		click.DimensionRegion:  &response.Regions,
+		click.DimensionAsn:     &response.Asns,
+		click.DimensionBrowser: &response.Browsers,
+		click.DimensionOs:      &response.OperatingSystems,
+		click.DimensionDevice:  &response.Devices,
+		click.DimensionSource:  &response.Sources,
+	} {
+		buckets, err := ss.clicks.CountBy(filter, dimension, statsBucketLimit)
+		if err != nil {
+			return dto.StatsResponse{}, err
+		}
+		*target = transformToStatsBuckets(buckets)
+	}
+	return response, nil
+}
+
+// newClickFilter переводит параметры запроса в фильтр, дата окончания включается в период
+func newClickFilter(request dto.StatsRequest) (click.Filter, error) {
+	utm, err := newUtm(request.Utm)
+	if err != nil {
+		return click.Filter{}, err
+	}
+
+	filter := click.Filter{OwnerId: request.OwnerId, Utm: utm, From: request.From}
+	if request.To != nil {
+		to := request.To.AddDate(0, 0, 1)
+		filter.To = &to
+	}
+	return filter, nil
+}
+
+func transformToStatsBuckets(buckets []click.Bucket) []dto.StatsBucket {
+	result := make([]dto.StatsBucket, 0, len(buckets))
+	for _, b := range buckets {
+		result = append(result, dto.StatsBucket{Value: b.Value, Clicks: b.Clicks})
+	}
+	return result
+}
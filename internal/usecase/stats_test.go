@@ -0,0 +1,118 @@
+package usecase
+
+import (
+	"leenwood/yandex-http/internal/domain/click"
+	clickMocks "leenwood/yandex-http/internal/domain/click/mocks"
+	"leenwood/yandex-http/internal/domain/url"
+	"leenwood/yandex-http/internal/domain/url/mocks"
+	"leenwood/yandex-http/internal/geoIp"
+	geoMocks "leenwood/yandex-http/internal/geoIp/mocks"
+	"leenwood/yandex-http/internal/usecase/dto"
+	"net"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Click statistics", func() {
+	var (
+		ctrl       *gomock.Controller
+		mockRepo   *mocks.MockRepositoryInterface
+		mockClicks *clickMocks.MockRepositoryInterface
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		mockRepo = mocks.NewMockRepositoryInterface(ctrl)
+		mockClicks = clickMocks.NewMockRepositoryInterface(ctrl)
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	Describe("ClickUrl", func() {
+		It("should record the click with the visitor location and device", func() {
+			mockGeo := geoMocks.NewMockResolverInterface(ctrl)
+			urlUseCase := &UrlUseCase{r: mockRepo, clicks: mockClicks, geo: mockGeo, ua: agents}
+			mockUrl := &url.Url{Id: "abc", OriginalUrl: "https://example.com"}
+
+			mockRepo.EXPECT().FindById("abc").Return(mockUrl, nil)
+			mockRepo.EXPECT().IncrementClicks("abc").Return(nil)
+			mockGeo.EXPECT().Lookup(net.ParseIP("192.0.2.10")).
+				Return(geoIp.Location{Country: "DE", Region: "DE-BY", Asn: 64500}, nil)
+			mockClicks.EXPECT().Save(gomock.Any()).DoAndReturn(func(c *click.Click) error {
+				Expect(c.UrlId).To(Equal("abc"))
+				Expect(c.Country).To(Equal("DE"))
+				Expect(c.Region).To(Equal("DE-BY"))
+				Expect(c.Asn).To(Equal(uint32(64500)))
+				Expect(c.Browser).To(Equal("Firefox"))
+				Expect(c.Os).To(Equal("Linux"))
+				Expect(c.Device).To(Equal("desktop"))
+				Expect(c.Source).To(Equal(click.SourceQr))
+				return nil
+			})
+
+			_, err := urlUseCase.ClickUrl(dto.UrlClickRequest{
+				Id:        "abc",
+				ClientIp:  "192.0.2.10",
+				Source:    click.SourceQr,
+				UserAgent: "Mozilla/5.0 (X11; Ubuntu; Linux x86_64; rv:121.0) Gecko/20100101 Firefox/121.0",
+			})
+
+			Expect(err).NotTo(HaveOccurred())
+		})
+	})
+
+	Describe("StatsUseCase", func() {
+		var statsUseCase StatsUseCaseInterface
+
+		BeforeEach(func() {
+			statsUseCase = &StatsUseCase{r: mockRepo, clicks: mockClicks}
+		})
+
+		It("should break link clicks down by country", func() {
+			from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+			to := time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC)
+			end := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+			filter := click.Filter{UrlId: "abc", OwnerId: "acme", From: &from, To: &end}
+
+			mockRepo.EXPECT().FindById("abc").Return(&url.Url{Id: "abc", OwnerId: "acme"}, nil)
+			mockClicks.EXPECT().Count(filter).Return(uint64(3), nil)
+			mockClicks.EXPECT().CountBy(filter, click.DimensionCountry, statsBucketLimit).
+				Return([]click.Bucket{{Value: "DE", Clicks: 2}, {Value: "US", Clicks: 1}}, nil)
+			mockClicks.EXPECT().CountBy(filter, click.DimensionRegion, statsBucketLimit).Return(nil, nil)
+			mockClicks.EXPECT().CountBy(filter, click.DimensionAsn, statsBucketLimit).Return(nil, nil)
+			mockClicks.EXPECT().CountBy(filter, click.DimensionBrowser, statsBucketLimit).Return(nil, nil)
+			mockClicks.EXPECT().CountBy(filter, click.DimensionOs, statsBucketLimit).Return(nil, nil)
+			mockClicks.EXPECT().CountBy(filter, click.DimensionDevice, statsBucketLimit).
+				Return([]click.Bucket{{Value: "mobile", Clicks: 3}}, nil)
+			mockClicks.EXPECT().CountBy(filter, click.DimensionSource, statsBucketLimit).Return(nil, nil)
+
+			response, err := statsUseCase.GetUrlStats(dto.UrlStatsRequest{
+				Id:           "abc",
+				StatsRequest: dto.StatsRequest{OwnerId: "acme", From: &from, To: &to},
+			})
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(response.Id).To(Equal("abc"))
+			Expect(response.Clicks).To(Equal(uint64(3)))
+			Expect(response.Countries).To(Equal([]dto.StatsBucket{{Value: "DE", Clicks: 2}, {Value: "US", Clicks: 1}}))
+			Expect(response.Regions).To(BeEmpty())
+			Expect(response.Devices).To(Equal([]dto.StatsBucket{{Value: "mobile", Clicks: 3}}))
+		})
+
+		It("should filter owner statistics by campaign", func() {
+			filter := click.Filter{OwnerId: "acme", Utm: url.Utm{Campaign: "spring"}}
+			mockClicks.EXPECT().Count(filter).Return(uint64(0), nil)
+			mockClicks.EXPECT().CountBy(filter, gomock.Any(), statsBucketLimit).Return(nil, nil).Times(7)
+
+			response, err := statsUseCase.GetStats(dto.StatsRequest{OwnerId: "acme", Utm: dto.Utm{UtmCampaign: "spring"}})
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(response.Countries).NotTo(BeNil())
+		})
+	})
+})
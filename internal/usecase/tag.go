@@ -0,0 +1,127 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"leenwood/yandex-http/config"
+	"leenwood/yandex-http/internal/domain/tag"
+	tagRepository "leenwood/yandex-http/internal/domain/tag/postgresRepository"
+	"leenwood/yandex-http/internal/domain/url"
+	"leenwood/yandex-http/internal/usecase/dto"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	// maxTitleLength и maxNotesLength - наибольшая длина названия и заметок ссылки в символах
+	maxTitleLength = 200
+	maxNotesLength = 2000
+)
+
+type TagUseCaseInterface interface {
+	UpdateUrlDetails(request dto.UpdateUrlDetailsRequest) (dto.UrlDetailsResponse, error)
+	GetTags(request dto.TagsRequest) (dto.TagsResponse, error)
+	RenameTag(request dto.RenameTagRequest) (dto.TagResponse, error)
+}
+
+type TagUseCase struct {
+	r    url.RepositoryInterface
+	tags tag.RepositoryInterface
+}
+
+func NewTagUseCase(ctx context.Context, config config.Config, urls url.RepositoryInterface) (*TagUseCase, error) {
+	tags, err := tagRepository.NewRepository(ctx, config.Database)
+	if err != nil {
+		return nil, err
+	}
+	return &TagUseCase{r: urls, tags: tags}, nil
+}
+
+// UpdateUrlDetails меняет название, заметки и метки ссылки владельца
+func (ts *TagUseCase) UpdateUrlDetails(request dto.UpdateUrlDetailsRequest) (dto.UrlDetailsResponse, error) {
+	u, err := findOwnedUrl(ts.r, request.Id, request.OwnerId)
+	if err != nil {
+		return dto.UrlDetailsResponse{}, err
+	}
+
+	changed := false
+	if request.Title != nil {
+		title := strings.TrimSpace(*request.Title)
+		if utf8.RuneCountInString(title) > maxTitleLength {
+			return dto.UrlDetailsResponse{}, fmt.Errorf("%w: title is longer than %d characters", url.ErrInvalidDetails, maxTitleLength)
+		}
+		changed = changed || u.Title != title
+		u.Title = title
+	}
+	if request.Notes != nil {
+		if utf8.RuneCountInString(*request.Notes) > maxNotesLength {
+			return dto.UrlDetailsResponse{}, fmt.Errorf("%w: notes are longer than %d characters", url.ErrInvalidDetails, maxNotesLength)
+		}
+		changed = changed || u.Notes != *request.Notes
+		u.Notes = *request.Notes
+	}
+
+	var tags []string
+	if request.Tags != nil {
+		if tags, err = tag.NormalizeAll(*request.Tags); err != nil {
+			return dto.UrlDetailsResponse{}, err
+		}
+	}
+
+	if changed {
+		if _, err = ts.r.Update(u); err != nil {
+			return dto.UrlDetailsResponse{}, err
+		}
+	}
+	if request.Tags != nil {
+		if err = ts.tags.Replace(u.Id, tags); err != nil {
+			return dto.UrlDetailsResponse{}, err
+		}
+	} else {
+		found, err := ts.tags.FindByUrls([]string{u.Id})
+		if err != nil {
+			return dto.UrlDetailsResponse{}, err
+		}
+		tags = found[u.Id]
+	}
+
+	if tags == nil {
+		tags = []string{}
+	}
+	return dto.UrlDetailsResponse{Id: u.Id, Title: u.Title, Notes: u.Notes, Tags: tags}, nil
+}
+
+// GetTags возвращает метки владельца, начиная с самых используемых
+func (ts *TagUseCase) GetTags(request dto.TagsRequest) (dto.TagsResponse, error) {
+	tags, err := ts.tags.FindByOwner(request.OwnerId)
+	if err != nil {
+		return dto.TagsResponse{}, err
+	}
+
+	response := dto.TagsResponse{Tags: make([]dto.TagResponse, 0, len(tags))}
+	for _, t := range tags {
+		response.Tags = append(response.Tags, dto.TagResponse{Name: t.Name, Links: t.Links})
+	}
+	return response, nil
+}
+
+// RenameTag переименовывает метку во всех ссылках владельца или сливает её с существующей
+func (ts *TagUseCase) RenameTag(request dto.RenameTagRequest) (dto.TagResponse, error) {
+	from, err := tag.Normalize(request.From)
+	if err != nil {
+		return dto.TagResponse{}, err
+	}
+	to, err := tag.Normalize(request.To)
+	if err != nil {
+		return dto.TagResponse{}, err
+	}
+	if from == to {
+		return dto.TagResponse{}, fmt.Errorf("%w: new name is the same as the old one", tag.ErrInvalidTag)
+	}
+
+	links, err := ts.tags.Rename(request.OwnerId, from, to)
+	if err != nil {
+		return dto.TagResponse{}, err
+	}
+	return dto.TagResponse{Name: to, Links: links}, nil
+}
@@ -0,0 +1,122 @@
+package usecase
+
+import (
+	"leenwood/yandex-http/internal/domain/tag"
+	tagMocks "leenwood/yandex-http/internal/domain/tag/mocks"
+	"leenwood/yandex-http/internal/domain/url"
+	"leenwood/yandex-http/internal/domain/url/mocks"
+	"leenwood/yandex-http/internal/usecase/dto"
+	"strings"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Tags", func() {
+	var (
+		ctrl       *gomock.Controller
+		mockRepo   *mocks.MockRepositoryInterface
+		mockTags   *tagMocks.MockRepositoryInterface
+		tagUseCase TagUseCaseInterface
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		mockRepo = mocks.NewMockRepositoryInterface(ctrl)
+		mockTags = tagMocks.NewMockRepositoryInterface(ctrl)
+		tagUseCase = &TagUseCase{r: mockRepo, tags: mockTags}
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	Describe("UpdateUrlDetails", func() {
+		It("should save the title and normalized tags", func() {
+			title := "  Spring sale "
+			tags := []string{"Promo", "spring  2024", "promo"}
+			mockRepo.EXPECT().FindById("abc").Return(&url.Url{Id: "abc", OwnerId: "acme"}, nil)
+			mockRepo.EXPECT().Update(gomock.Any()).DoAndReturn(func(u *url.Url) (*url.Url, error) {
+				Expect(u.Title).To(Equal("Spring sale"))
+				return u, nil
+			})
+			mockTags.EXPECT().Replace("abc", []string{"promo", "spring 2024"}).Return(nil)
+
+			response, err := tagUseCase.UpdateUrlDetails(dto.UpdateUrlDetailsRequest{Id: "abc", OwnerId: "acme", Title: &title, Tags: &tags})
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(response).To(Equal(dto.UrlDetailsResponse{Id: "abc", Title: "Spring sale", Tags: []string{"promo", "spring 2024"}}))
+		})
+
+		It("should keep the tags when they are not given", func() {
+			notes := "landing for the newsletter"
+			mockRepo.EXPECT().FindById("abc").Return(&url.Url{Id: "abc", OwnerId: "acme", Title: "Sale"}, nil)
+			mockRepo.EXPECT().Update(gomock.Any()).Return(nil, nil)
+			mockTags.EXPECT().FindByUrls([]string{"abc"}).Return(map[string][]string{"abc": {"promo"}}, nil)
+
+			response, err := tagUseCase.UpdateUrlDetails(dto.UpdateUrlDetailsRequest{Id: "abc", OwnerId: "acme", Notes: &notes})
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(response.Title).To(Equal("Sale"))
+			Expect(response.Notes).To(Equal(notes))
+			Expect(response.Tags).To(Equal([]string{"promo"}))
+		})
+
+		It("should not change links of other owners", func() {
+			mockRepo.EXPECT().FindById("abc").Return(&url.Url{Id: "abc", OwnerId: "other"}, nil)
+
+			_, err := tagUseCase.UpdateUrlDetails(dto.UpdateUrlDetailsRequest{Id: "abc", OwnerId: "acme"})
+
+			Expect(err).To(MatchError(url.ErrNotFound))
+		})
+
+		It("should reject invalid tags and overlong titles", func() {
+			title := strings.Repeat("a", maxTitleLength+1)
+			mockRepo.EXPECT().FindById("abc").Return(&url.Url{Id: "abc", OwnerId: "acme"}, nil).Times(2)
+
+			_, err := tagUseCase.UpdateUrlDetails(dto.UpdateUrlDetailsRequest{Id: "abc", OwnerId: "acme", Title: &title})
+			Expect(err).To(MatchError(url.ErrInvalidDetails))
+
+			tags := []string{"<script>"}
+			_, err = tagUseCase.UpdateUrlDetails(dto.UpdateUrlDetailsRequest{Id: "abc", OwnerId: "acme", Tags: &tags})
+			Expect(err).To(MatchError(tag.ErrInvalidTag))
+		})
+	})
+
+	Describe("GetTags", func() {
+		It("should list the owner tags with usage counts", func() {
+			mockTags.EXPECT().FindByOwner("acme").Return([]*tag.Tag{{Name: "promo", Links: 3}, {Name: "docs", Links: 1}}, nil)
+
+			response, err := tagUseCase.GetTags(dto.TagsRequest{OwnerId: "acme"})
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(response.Tags).To(Equal([]dto.TagResponse{{Name: "promo", Links: 3}, {Name: "docs", Links: 1}}))
+		})
+	})
+
+	Describe("RenameTag", func() {
+		It("should rename or merge the normalized tag", func() {
+			mockTags.EXPECT().Rename("acme", "promo", "marketing").Return(uint64(5), nil)
+
+			response, err := tagUseCase.RenameTag(dto.RenameTagRequest{OwnerId: "acme", From: "Promo", To: "Marketing"})
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(response).To(Equal(dto.TagResponse{Name: "marketing", Links: 5}))
+		})
+
+		It("should reject renaming a tag to itself", func() {
+			_, err := tagUseCase.RenameTag(dto.RenameTagRequest{OwnerId: "acme", From: "promo", To: "PROMO"})
+
+			Expect(err).To(MatchError(tag.ErrInvalidTag))
+		})
+
+		It("should report an unknown tag", func() {
+			mockTags.EXPECT().Rename("acme", "promo", "marketing").Return(uint64(0), tag.ErrNotFound)
+
+			_, err := tagUseCase.RenameTag(dto.RenameTagRequest{OwnerId: "acme", From: "promo", To: "marketing"})
+
+			Expect(err).To(MatchError(tag.ErrNotFound))
+		})
+	})
+})
@@ -0,0 +1,482 @@
+package usecase
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"leenwood/yandex-http/config"
+	"leenwood/yandex-http/internal/domain/customDomain"
+	domainRepository "leenwood/yandex-http/internal/domain/customDomain/postgresRepository"
+	"leenwood/yandex-http/internal/domain/url"
+	"leenwood/yandex-http/internal/usecase/dto"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	ExportCsv   = "csv"
+	ExportJsonl = "jsonl"
+
+	DuplicateSkip      = "skip"
+	DuplicateOverwrite = "overwrite"
+	DuplicateFail      = "fail"
+
+	// MaxImportRows - наибольшее число строк в загружаемом файле
+	MaxImportRows = 100000
+	// maxImportErrors - сколько ошибок строк попадает в отчёт, остальные только считаются
+	maxImportErrors = 1000
+)
+
+var exportColumns = []string{"id", "short_url", "original_url", "domain", "owner_id", "click_count", "created_date",
+	"custom_id", "protected", "interstitial", "redirect_type", "query_mode", "forward_path",
+	"utm_source", "utm_medium", "utm_campaign", "utm_term", "utm_content"}
+
+// importFields - поля ссылки, которые можно загрузить из файла
+var importFields = map[string]bool{
+	"url": true, "id": true, "domain": true, "password": true, "interstitial": true,
+	"redirect_type": true, "query_mode": true, "forward_path": true,
+	"utm_source": true, "utm_medium": true, "utm_campaign": true, "utm_term": true, "utm_content": true,
+}
+
+type TransferUseCaseInterface interface {
+	ExportUrls(request dto.ExportUrlsRequest, w io.Writer) error
+	ImportUrls(request dto.ImportUrlsRequest, source io.Reader) (dto.ImportUrlsResponse, error)
+}
+
+// TransferUseCase выгружает и загружает ссылки файлами для администраторов
+type TransferUseCase struct {
+	r url.RepositoryInterface
+	d customDomain.RepositoryInterface
+	b ShortUrlBuilder
+}
+
+func NewTransferUseCase(ctx context.Context, config config.Config, urls url.RepositoryInterface) (*TransferUseCase, error) {
+	domains, err := domainRepository.NewRepository(ctx, config.Database)
+	if err != nil {
+		return nil, err
+	}
+	builder, err := NewShortUrlBuilder(config.App)
+	if err != nil {
+		return nil, err
+	}
+	return &TransferUseCase{r: urls, d: domains, b: builder}, nil
+}
+
+// ExportUrls пишет подходящие под фильтр ссылки в w по мере чтения из базы
+func (ts *TransferUseCase) ExportUrls(request dto.ExportUrlsRequest, w io.Writer) error {
+	encoder, err := newExportEncoder(request.Format, w)
+	if err != nil {
+		return err
+	}
+	utm, err := newUtm(request.Utm)
+	if err != nil {
+		return err
+	}
+
+	filter := url.Filter{OwnerId: request.OwnerId, Domain: strings.ToLower(request.Domain), Utm: utm, CreatedFrom: request.From}
+	if request.To != nil {
+		to := request.To.AddDate(0, 0, 1)
+		filter.CreatedTo = &to
+	}
+
+	domains := make(map[string]*customDomain.Domain)
+	err = ts.r.ForEach(filter, func(u *url.Url) error {
+		domain, ok := domains[u.Domain]
+		if !ok && u.Domain != "" {
+			found, err := ts.d.FindByHostname(u.Domain)
+			if err != nil && !errors.Is(err, customDomain.ErrNotFound) {
+				return err
+			}
+			domain, domains[u.Domain] = found, found
+		}
+		return encoder.write(transformToExportedUrl(u, ts.b.Build(request.BaseUrl, domain, u.Id)))
+	})
+	if err != nil {
+		return err
+	}
+	return encoder.flush()
+}
+
+// importRow - проверенная строка файла и действие с ней
+type importRow struct {
+	line int
+	url  *url.Url
+	// existing - ссылка, которую строка перезаписывает
+	existing *url.Url
+}
+
+// ImportUrls загружает ссылки из CSV с заголовком. Сначала проверяется весь файл,
+// затем новые ссылки сохраняются пачками, а существующие перезаписываются по политике OnDuplicate.
+func (ts *TransferUseCase) ImportUrls(request dto.ImportUrlsRequest, source io.Reader) (dto.ImportUrlsResponse, error) {
+	response := dto.ImportUrlsResponse{DryRun: request.DryRun, Errors: []dto.ImportRowError{}}
+
+	policy := strings.ToLower(request.OnDuplicate)
+	switch policy {
+	case "":
+		policy = DuplicateSkip
+	case DuplicateSkip, DuplicateOverwrite, DuplicateFail:
+	default:
+		return response, fmt.Errorf("%w: unknown duplicate policy %q", url.ErrInvalidImport, request.OnDuplicate)
+	}
+
+	reader := csv.NewReader(source)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return response, fmt.Errorf("%w: failed to read header: %v", url.ErrInvalidImport, err)
+	}
+	columns, err := importColumns(header, request.Mapping)
+	if err != nil {
+		return response, err
+	}
+
+	var (
+		creates, updates []importRow
+		seen             = make(map[string]bool)
+		domains          = make(map[string]*customDomain.Domain)
+	)
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return response, fmt.Errorf("%w: %v", url.ErrInvalidImport, err)
+		}
+		if response.Total++; response.Total > MaxImportRows {
+			return response, fmt.Errorf("%w: at most %d rows per file", url.ErrTooManyItems, MaxImportRows)
+		}
+
+		row, err := ts.importRow(record, columns, request.OwnerId, policy, seen, domains)
+		switch {
+		case err != nil:
+			addImportError(&response, line, err)
+			if policy == DuplicateFail && (errors.Is(err, url.ErrIdExists) || errors.Is(err, url.ErrUrlExists)) {
+				response.Aborted = true
+			}
+		case row.url == nil:
+			response.Skipped++
+		case row.existing != nil:
+			row.line = line
+			updates = append(updates, row)
+		default:
+			row.line = line
+			creates = append(creates, row)
+		}
+	}
+
+	if response.Aborted {
+		return response, nil
+	}
+	if request.DryRun {
+		response.Created, response.Updated = len(creates), len(updates)
+		return response, nil
+	}
+
+	ts.saveImport(creates, updates, &response, request.Progress)
+	return response, nil
+}
+
+// importRow проверяет строку файла. Без ошибки и без ссылки строка пропускается.
+func (ts *TransferUseCase) importRow(record []string, columns map[string]int, ownerId, policy string,
+	seen map[string]bool, domains map[string]*customDomain.Domain) (importRow, error) {
+	value := func(field string) string {
+		if index, ok := columns[field]; ok && index < len(record) {
+			return strings.TrimSpace(record[index])
+		}
+		return ""
+	}
+
+	request := dto.CreateShortUrlWithCustomIdRequest{
+		Url:          value("url"),
+		Id:           importId(value("id")),
+		Domain:       value("domain"),
+		Password:     value("password"),
+		RedirectType: value("redirect_type"),
+		QueryMode:    value("query_mode"),
+		Utm: dto.Utm{
+			UtmSource:   value("utm_source"),
+			UtmMedium:   value("utm_medium"),
+			UtmCampaign: value("utm_campaign"),
+			UtmTerm:     value("utm_term"),
+			UtmContent:  value("utm_content"),
+		},
+		OwnerId: ownerId,
+	}
+	if request.Url == "" {
+		return importRow{}, fmt.Errorf("%w: url is required", url.ErrInvalidUrl)
+	}
+
+	var err error
+	if request.Interstitial, err = parseImportBool(value("interstitial")); err != nil {
+		return importRow{}, fmt.Errorf("%w: interstitial: %v", url.ErrInvalidImport, err)
+	}
+	if request.ForwardPath, err = parseImportBool(value("forward_path")); err != nil {
+		return importRow{}, fmt.Errorf("%w: forward_path: %v", url.ErrInvalidImport, err)
+	}
+
+	hostname := strings.ToLower(request.Domain)
+	if _, ok := domains[hostname]; !ok {
+		domain, err := findVerifiedDomain(ts.d, hostname)
+		if err != nil {
+			return importRow{}, err
+		}
+		domains[hostname] = domain
+	}
+
+	model, err := newUrl(request)
+	if err != nil {
+		return importRow{}, err
+	}
+
+	existing, err := ts.findImportDuplicate(request, seen)
+	if err != nil {
+		return importRow{}, err
+	}
+	if existing != nil {
+		switch {
+		case policy == DuplicateFail && request.Id != "":
+			return importRow{}, fmt.Errorf("%w: %s", url.ErrIdExists, request.Id)
+		case policy == DuplicateFail:
+			return importRow{}, fmt.Errorf("%w: %s", url.ErrUrlExists, request.Url)
+		// Ссылка без id совпадает с существующей по адресу, перезаписывать в ней нечего
+		case policy == DuplicateSkip || request.Id == "":
+			return importRow{}, nil
+		}
+	}
+
+	if model.PasswordHash, err = hashPassword(request.Password); err != nil {
+		return importRow{}, err
+	}
+	return importRow{url: model, existing: existing}, nil
+}
+
+// findImportDuplicate ищет существующую ссылку с тем же id, а для строки без id и настроек - с тем же адресом.
+// seen - id и адреса предыдущих строк файла.
+func (ts *TransferUseCase) findImportDuplicate(request dto.CreateShortUrlWithCustomIdRequest, seen map[string]bool) (*url.Url, error) {
+	if request.Id == "" {
+		if hasLinkOptions(request) {
+			return nil, nil
+		}
+		key := "url " + strings.ToLower(request.Domain) + " " + request.Url
+		if seen[key] {
+			// Такая же ссылка уже создаётся предыдущей строкой
+			return &url.Url{OriginalUrl: request.Url}, nil
+		}
+		seen[key] = true
+		return ts.r.FindByUrl(request.Url, strings.ToLower(request.Domain))
+	}
+
+	key := "id " + request.Id
+	if seen[key] {
+		return nil, fmt.Errorf("%w: %s is repeated in the file", url.ErrInvalidImport, request.Id)
+	}
+	seen[key] = true
+
+	existing, err := ts.r.FindById(request.Id)
+	if errors.Is(err, url.ErrNotFound) {
+		return nil, nil
+	}
+	return existing, err
+}
+
+func (ts *TransferUseCase) saveImport(creates, updates []importRow, response *dto.ImportUrlsResponse, progress func(done, total int)) {
+	total, done := len(creates)+len(updates), 0
+	report := func(count int) {
+		done += count
+		if progress != nil {
+			progress(done, total)
+		}
+	}
+
+	for start := 0; start < len(creates); start += bulkBatchSize {
+		batch := creates[start:min(start+bulkBatchSize, len(creates))]
+		models := make([]*url.Url, 0, len(batch))
+		for _, row := range batch {
+			models = append(models, row.url)
+		}
+
+		if _, err := ts.r.SaveMany(models); err == nil {
+			response.Created += len(batch)
+		} else {
+			// Пачка откатилась целиком, сохраняем её по одной ссылке, чтобы найти ошибочные строки
+			for _, row := range batch {
+				if !row.url.CustomId {
+					row.url.Id = ""
+				}
+				if _, err := ts.r.Save(row.url); err != nil {
+					addImportError(response, row.line, err)
+					continue
+				}
+				response.Created++
+			}
+		}
+		report(len(batch))
+	}
+
+	for start := 0; start < len(updates); start += bulkBatchSize {
+		batch := updates[start:min(start+bulkBatchSize, len(updates))]
+		for _, row := range batch {
+			if _, err := ts.r.Update(overwriteUrl(row.existing, row.url)); err != nil {
+				addImportError(response, row.line, err)
+				continue
+			}
+			response.Updated++
+		}
+		report(len(batch))
+	}
+}
+
+// overwriteUrl переносит в существующую ссылку загруженные настройки, сохраняя счётчики, правила и эксперимент
+func overwriteUrl(existing, imported *url.Url) *url.Url {
+	existing.OriginalUrl = imported.OriginalUrl
+	existing.ResolvedUrl = imported.ResolvedUrl
+	existing.DedupKey = ""
+	existing.Domain = imported.Domain
+	existing.OwnerId = imported.OwnerId
+	existing.PasswordHash = imported.PasswordHash
+	existing.Interstitial = imported.Interstitial
+	existing.RedirectType = imported.RedirectType
+	existing.QueryMode = imported.QueryMode
+	existing.ForwardPath = imported.ForwardPath
+	existing.Utm = imported.Utm
+	return existing
+}
+
+// ParseColumnMapping разбирает сопоставление столбцов вида "long_url=url,link=id"
+func ParseColumnMapping(value string) (map[string]string, error) {
+	mapping := make(map[string]string)
+	for _, pair := range strings.Split(value, ",") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+		column, field, ok := strings.Cut(pair, "=")
+		column, field = strings.TrimSpace(column), strings.TrimSpace(field)
+		if !ok || column == "" || field == "" {
+			return nil, fmt.Errorf("%w: invalid column mapping %q", url.ErrInvalidImport, pair)
+		}
+		mapping[column] = field
+	}
+	return mapping, nil
+}
+
+// importColumns возвращает номер столбца для каждого поля ссылки. Столбец original_url
+// из выгрузки по умолчанию считается адресом, неизвестные столбцы пропускаются.
+func importColumns(header []string, mapping map[string]string) (map[string]int, error) {
+	columns := make(map[string]int)
+	for index, column := range header {
+		column = strings.TrimSpace(strings.TrimPrefix(column, "\ufeff"))
+		field, ok := mapping[column]
+		if !ok {
+			field = column
+			if column == "original_url" {
+				field = "url"
+			}
+		}
+		if !importFields[field] {
+			if ok {
+				return nil, fmt.Errorf("%w: unknown field %q", url.ErrInvalidImport, field)
+			}
+			continue
+		}
+		if _, exists := columns[field]; exists {
+			return nil, fmt.Errorf("%w: several columns map to %q", url.ErrInvalidImport, field)
+		}
+		columns[field] = index
+	}
+
+	if _, ok := columns["url"]; !ok {
+		return nil, fmt.Errorf("%w: no column for url", url.ErrInvalidImport)
+	}
+	return columns, nil
+}
+
+// importId принимает id или полную короткую ссылку, например из выгрузки другого сервиса
+func importId(value string) string {
+	if !strings.Contains(value, "/") {
+		return value
+	}
+	value = strings.TrimRight(value, "/")
+	return value[strings.LastIndex(value, "/")+1:]
+}
+
+func parseImportBool(value string) (bool, error) {
+	if value == "" {
+		return false, nil
+	}
+	return strconv.ParseBool(value)
+}
+
+func addImportError(response *dto.ImportUrlsResponse, line int, err error) {
+	response.Failed++
+	if len(response.Errors) < maxImportErrors {
+		response.Errors = append(response.Errors, dto.ImportRowError{Line: line, Error: err.Error()})
+	}
+}
+
+// exportEncoder пишет строки выгрузки в выбранном формате
+type exportEncoder struct {
+	csv  *csv.Writer
+	json *json.Encoder
+}
+
+func newExportEncoder(format string, w io.Writer) (*exportEncoder, error) {
+	switch strings.ToLower(format) {
+	case "", ExportCsv:
+		writer := csv.NewWriter(w)
+		if err := writer.Write(exportColumns); err != nil {
+			return nil, err
+		}
+		return &exportEncoder{csv: writer}, nil
+	case ExportJsonl:
+		return &exportEncoder{json: json.NewEncoder(w)}, nil
+	default:
+		return nil, fmt.Errorf("%w: %q, expected csv or jsonl", url.ErrUnsupportedFormat, format)
+	}
+}
+
+func (e *exportEncoder) write(u dto.ExportedUrl) error {
+	if e.json != nil {
+		return e.json.Encode(u)
+	}
+	return e.csv.Write([]string{
+		u.Id, u.ShortUrl, u.OriginalUrl, u.Domain, u.OwnerId,
+		strconv.FormatUint(u.ClickCount, 10), u.CreatedDate.UTC().Format(time.RFC3339),
+		strconv.FormatBool(u.CustomId), strconv.FormatBool(u.Protected), strconv.FormatBool(u.Interstitial),
+		u.RedirectType, u.QueryMode, strconv.FormatBool(u.ForwardPath),
+		u.UtmSource, u.UtmMedium, u.UtmCampaign, u.UtmTerm, u.UtmContent,
+	})
+}
+
+func (e *exportEncoder) flush() error {
+	if e.csv == nil {
+		return nil
+	}
+	e.csv.Flush()
+	return e.csv.Error()
+}
+
+func transformToExportedUrl(u *url.Url, shortUrl string) dto.ExportedUrl {
+	return dto.ExportedUrl{
+		Id:           u.Id,
+		ShortUrl:     shortUrl,
+		OriginalUrl:  u.OriginalUrl,
+		Domain:       u.Domain,
+		OwnerId:      u.OwnerId,
+		ClickCount:   u.ClickCount,
+		CreatedDate:  u.CreatedDate,
+		CustomId:     u.CustomId,
+		Protected:    u.IsProtected(),
+		Interstitial: u.Interstitial,
+		RedirectType: string(u.RedirectType),
+		QueryMode:    string(u.QueryMode),
+		ForwardPath:  u.ForwardPath,
+		Utm:          transformToUtm(u.Utm),
+	}
+}
@@ -0,0 +1,206 @@
+package usecase
+
+import (
+	"bytes"
+	"errors"
+	"leenwood/yandex-http/config"
+	"leenwood/yandex-http/internal/domain/url"
+	"leenwood/yandex-http/internal/domain/url/mocks"
+	"leenwood/yandex-http/internal/usecase/dto"
+	"strings"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Link transfer", func() {
+	var (
+		ctrl            *gomock.Controller
+		mockRepo        *mocks.MockRepositoryInterface
+		transferUseCase *TransferUseCase
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		mockRepo = mocks.NewMockRepositoryInterface(ctrl)
+		builder, err := NewShortUrlBuilder(config.AppConfig{Hostname: "localhost", Port: "8080"})
+		Expect(err).NotTo(HaveOccurred())
+		transferUseCase = &TransferUseCase{r: mockRepo, b: builder}
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	Describe("ExportUrls", func() {
+		created := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+		links := []*url.Url{
+			{Id: "abc", OriginalUrl: "https://example.com/a", OwnerId: "acme", ClickCount: 3, CreatedDate: created, PasswordHash: "secret"},
+			{Id: "bio", OriginalUrl: "https://example.com/b", OwnerId: "acme", CustomId: true, CreatedDate: created},
+		}
+		forEach := func(_ url.Filter, fn func(*url.Url) error) error {
+			for _, u := range links {
+				if err := fn(u); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+
+		It("should write a CSV row per link without password hashes", func() {
+			from := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+			end := time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)
+			to := end.AddDate(0, 0, -1)
+			filter := url.Filter{OwnerId: "acme", Utm: url.Utm{Campaign: "spring"}, CreatedFrom: &from, CreatedTo: &end}
+			mockRepo.EXPECT().ForEach(filter, gomock.Any()).DoAndReturn(forEach)
+
+			var output bytes.Buffer
+			err := transferUseCase.ExportUrls(dto.ExportUrlsRequest{
+				OwnerId: "acme", From: &from, To: &to, Utm: dto.Utm{UtmCampaign: "spring"},
+			}, &output)
+
+			Expect(err).NotTo(HaveOccurred())
+			lines := strings.Split(strings.TrimSpace(output.String()), "\n")
+			Expect(lines).To(HaveLen(3))
+			Expect(lines[0]).To(HavePrefix("id,short_url,original_url,"))
+			Expect(lines[1]).To(HavePrefix("abc,http://localhost:8080/abc,https://example.com/a,,acme,3,2024-03-01T12:00:00Z,false,true,"))
+			Expect(output.String()).NotTo(ContainSubstring("secret"))
+		})
+
+		It("should write JSON Lines", func() {
+			mockRepo.EXPECT().ForEach(url.Filter{}, gomock.Any()).DoAndReturn(forEach)
+
+			var output bytes.Buffer
+			err := transferUseCase.ExportUrls(dto.ExportUrlsRequest{Format: ExportJsonl}, &output)
+
+			Expect(err).NotTo(HaveOccurred())
+			lines := strings.Split(strings.TrimSpace(output.String()), "\n")
+			Expect(lines).To(HaveLen(2))
+			Expect(lines[1]).To(ContainSubstring(`"id":"bio"`))
+			Expect(lines[1]).To(ContainSubstring(`"custom_id":true`))
+		})
+
+		It("should reject an unknown format", func() {
+			err := transferUseCase.ExportUrls(dto.ExportUrlsRequest{Format: "xml"}, &bytes.Buffer{})
+
+			Expect(err).To(MatchError(url.ErrUnsupportedFormat))
+		})
+	})
+
+	Describe("ImportUrls", func() {
+		It("should map columns and save new links in a batch", func() {
+			source := "\ufefflong_url,link,utm_campaign,notes\n" +
+				"https://example.com/a,,spring,first\n" +
+				"https://example.com/b,https://sho.rt/promo,,second\n"
+			mockRepo.EXPECT().FindById("promo").Return(nil, url.ErrNotFound)
+			mockRepo.EXPECT().SaveMany(gomock.Len(2)).DoAndReturn(func(urls []*url.Url) ([]*url.Url, error) {
+				Expect(urls[0].Utm.Campaign).To(Equal("spring"))
+				Expect(urls[0].OwnerId).To(Equal("acme"))
+				Expect(urls[1].Id).To(Equal("promo"))
+				Expect(urls[1].CustomId).To(BeTrue())
+				return urls, nil
+			})
+
+			var progress []int
+			response, err := transferUseCase.ImportUrls(dto.ImportUrlsRequest{
+				OwnerId:  "acme",
+				Mapping:  map[string]string{"long_url": "url", "link": "id"},
+				Progress: func(done, total int) { progress = append(progress, done, total) },
+			}, strings.NewReader(source))
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(response.Total).To(Equal(2))
+			Expect(response.Created).To(Equal(2))
+			Expect(response.Errors).To(BeEmpty())
+			Expect(progress).To(Equal([]int{2, 2}))
+		})
+
+		It("should skip existing links and report invalid rows", func() {
+			source := "url,id,redirect_type\n" +
+				"https://example.com/a,abc,\n" +
+				",,\n" +
+				"https://example.com/c,,999\n" +
+				"https://example.com/a,abc,\n"
+			mockRepo.EXPECT().FindById("abc").Return(&url.Url{Id: "abc"}, nil)
+
+			response, err := transferUseCase.ImportUrls(dto.ImportUrlsRequest{}, strings.NewReader(source))
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(response.Skipped).To(Equal(1))
+			Expect(response.Failed).To(Equal(3))
+			Expect(response.Errors).To(HaveLen(3))
+			Expect(response.Errors[0].Line).To(Equal(3))
+			Expect(response.Errors[2].Line).To(Equal(5))
+		})
+
+		It("should overwrite existing links and keep their counters", func() {
+			existing := &url.Url{Id: "abc", OriginalUrl: "https://example.com/old", ClickCount: 7, HasRules: true}
+			mockRepo.EXPECT().FindById("abc").Return(existing, nil)
+			mockRepo.EXPECT().Update(gomock.Any()).DoAndReturn(func(u *url.Url) (*url.Url, error) {
+				Expect(u.OriginalUrl).To(Equal("https://example.com/new"))
+				Expect(u.ClickCount).To(Equal(uint64(7)))
+				Expect(u.HasRules).To(BeTrue())
+				return u, nil
+			})
+
+			response, err := transferUseCase.ImportUrls(dto.ImportUrlsRequest{OnDuplicate: DuplicateOverwrite},
+				strings.NewReader("url,id\nhttps://example.com/new,abc\n"))
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(response.Updated).To(Equal(1))
+		})
+
+		It("should abort without saving when a duplicate is found in fail mode", func() {
+			mockRepo.EXPECT().FindById("abc").Return(&url.Url{Id: "abc"}, nil)
+			mockRepo.EXPECT().FindByUrl("https://example.com/b", "").Return(nil, nil)
+
+			response, err := transferUseCase.ImportUrls(dto.ImportUrlsRequest{OnDuplicate: DuplicateFail},
+				strings.NewReader("url,id\nhttps://example.com/a,abc\nhttps://example.com/b,\n"))
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(response.Aborted).To(BeTrue())
+			Expect(response.Created).To(BeZero())
+			Expect(response.Errors[0].Line).To(Equal(2))
+		})
+
+		It("should only count changes in dry run", func() {
+			mockRepo.EXPECT().FindByUrl("https://example.com/a", "").Return(nil, nil)
+
+			response, err := transferUseCase.ImportUrls(dto.ImportUrlsRequest{DryRun: true},
+				strings.NewReader("original_url\nhttps://example.com/a\n"))
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(response.DryRun).To(BeTrue())
+			Expect(response.Created).To(Equal(1))
+		})
+
+		It("should save a failed batch link by link", func() {
+			mockRepo.EXPECT().FindById("abc").Return(nil, url.ErrNotFound)
+			mockRepo.EXPECT().FindById("bio").Return(nil, url.ErrNotFound)
+			mockRepo.EXPECT().SaveMany(gomock.Len(2)).Return(nil, errors.New("batch failed"))
+			mockRepo.EXPECT().Save(gomock.Any()).Return(nil, url.ErrIdExists)
+			mockRepo.EXPECT().Save(gomock.Any()).DoAndReturn(func(u *url.Url) (*url.Url, error) { return u, nil })
+
+			response, err := transferUseCase.ImportUrls(dto.ImportUrlsRequest{},
+				strings.NewReader("url,id\nhttps://example.com/a,abc\nhttps://example.com/b,bio\n"))
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(response.Created).To(Equal(1))
+			Expect(response.Failed).To(Equal(1))
+		})
+
+		It("should reject a file without a url column", func() {
+			_, err := transferUseCase.ImportUrls(dto.ImportUrlsRequest{}, strings.NewReader("link\nabc\n"))
+
+			Expect(err).To(MatchError(url.ErrInvalidImport))
+		})
+
+		It("should reject an unknown duplicate policy", func() {
+			_, err := transferUseCase.ImportUrls(dto.ImportUrlsRequest{OnDuplicate: "merge"}, strings.NewReader("url\n"))
+
+			Expect(err).To(MatchError(url.ErrInvalidImport))
+		})
+	})
+})
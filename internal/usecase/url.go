@@ -2,131 +2,766 @@ package usecase
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"leenwood/yandex-http/config"
+	"leenwood/yandex-http/internal/domain/click"
+	clickRepository "leenwood/yandex-http/internal/domain/click/postgresRepository"
+	"leenwood/yandex-http/internal/domain/customDomain"
+	domainRepository "leenwood/yandex-http/internal/domain/customDomain/postgresRepository"
+	"leenwood/yandex-http/internal/domain/experiment"
+	experimentRepository "leenwood/yandex-http/internal/domain/experiment/postgresRepository"
+	"leenwood/yandex-http/internal/domain/folder"
+	folderRepository "leenwood/yandex-http/internal/domain/folder/postgresRepository"
+	"leenwood/yandex-http/internal/domain/rule"
+	ruleRepository "leenwood/yandex-http/internal/domain/rule/postgresRepository"
+	"leenwood/yandex-http/internal/domain/tag"
+	tagRepository "leenwood/yandex-http/internal/domain/tag/postgresRepository"
 	"leenwood/yandex-http/internal/domain/url"
-	"leenwood/yandex-http/internal/domain/url/postgresRepository"
+	"leenwood/yandex-http/internal/domain/url/cachedRepository"
+	"leenwood/yandex-http/internal/geoIp"
+	"leenwood/yandex-http/internal/metadata"
+	"leenwood/yandex-http/internal/outbound"
+	"leenwood/yandex-http/internal/rateLimit"
 	"leenwood/yandex-http/internal/usecase/dto"
+	"leenwood/yandex-http/internal/userAgent"
+	"net"
 	"strings"
+
+	"golang.org/x/crypto/bcrypt"
 )
 
 type UrlUseCaseInterface interface {
 	CreateShortUrl(request dto.CreateShortUrlUseCaseRequest) (dto.CreateShortUrlResponse, error)
 	CreateShortUrlWithCustomId(request dto.CreateShortUrlWithCustomIdRequest) (dto.CreateShortUrlResponse, error)
-	GetUrlList(pagination dto.PaginationRequest) ([]dto.UrlInfoResponse, error)
-	ClickUrl(request dto.UrlClickRequest) (string, error)
+	CreateShortUrls(request dto.BulkCreateRequest) (dto.BulkCreateResponse, error)
+	GetUrlList(pagination dto.PaginationRequest) (dto.UrlListResponse, error)
+	ClickUrl(request dto.UrlClickRequest) (dto.UrlClickResponse, error)
+	UnlockUrl(request dto.UnlockUrlRequest) error
+	PreviewUrl(request dto.UrlPreviewRequest) (dto.UrlPreviewResponse, error)
+	// GetCacheStats возвращает счётчики кэша ссылок, общего для всех сценариев процесса
+	GetCacheStats() dto.CacheStatsResponse
 }
 
 type UrlUseCase struct {
 	r url.RepositoryInterface
+	d customDomain.RepositoryInterface
+	b ShortUrlBuilder
+	p quotaPolicy
+	l rateLimit.StoreInterface
 	c config.Config
+	// clicks хранит учтённые переходы для статистики
+	clicks click.RepositoryInterface
+	// ua и geo определяют устройство и местоположение посетителя для правил и статистики
+	ua  userAgent.ParserInterface
+	geo geoIp.ResolverInterface
+	// rules нужен только для ссылок с правилами перенаправления
+	rules rule.RepositoryInterface
+	// variants нужен только для ссылок с экспериментом
+	variants experiment.RepositoryInterface
+	// tags - метки ссылок для списка
+	tags tag.RepositoryInterface
+	// folders - папки владельца для списка ссылок папки вместе с вложенными
+	folders folder.RepositoryInterface
+	// metadata - очередь загрузки сведений о страницах новых ссылок, nil если загрузка отключена
+	metadata MetadataQueueInterface
+	// chains раскрывает адреса назначения, которые сами являются короткими ссылками, nil если не раскрываются
+	chains *destinationResolver
+	// cache - кэш ссылок по id, через который читает r, nil если кэш отключён
+	cache *cachedRepository.Cache
 }
 
-func NewUrlUseCase(ctx context.Context, config config.Config) (*UrlUseCase, error) {
-	repository, err := postgresRepository.NewRepository(ctx, config.Database)
+// NewUrlUseCase создаёт сценарии ссылок поверх общего репозитория ссылок repository и его кэша urlCache из NewUrlRepository
+func NewUrlUseCase(ctx context.Context, config config.Config, limiter rateLimit.StoreInterface, repository url.RepositoryInterface, urlCache *cachedRepository.Cache) (*UrlUseCase, error) {
+	domains, err := domainRepository.NewRepository(ctx, config.Database)
+	if err != nil {
+		return nil, err
+	}
+	builder, err := NewShortUrlBuilder(config.App)
+	if err != nil {
+		return nil, err
+	}
+	if _, err = url.ParseRedirectType(config.App.DefaultRedirectType); err != nil {
+		return nil, fmt.Errorf("invalid DEFAULT_REDIRECT_TYPE: %w", err)
+	}
+	policy, err := newQuotaPolicy(ctx, config, repository)
+	if err != nil {
+		return nil, err
+	}
+	rules, err := ruleRepository.NewRepository(ctx, config.Database)
+	if err != nil {
+		return nil, err
+	}
+	geo, err := geoIp.NewResolver(config.GeoIp)
+	if err != nil {
+		return nil, err
+	}
+	agents, err := userAgent.NewParser(config.App.UserAgentCacheSize)
+	if err != nil {
+		return nil, err
+	}
+	variants, err := experimentRepository.NewRepository(ctx, config.Database)
+	if err != nil {
+		return nil, err
+	}
+	clicks, err := clickRepository.NewRepository(ctx, config.Database)
+	if err != nil {
+		return nil, err
+	}
+	tags, err := tagRepository.NewRepository(ctx, config.Database)
+	if err != nil {
+		return nil, err
+	}
+	folders, err := folderRepository.NewRepository(ctx, config.Database)
 	if err != nil {
 		return nil, err
 	}
-	return &UrlUseCase{r: repository, c: config}, nil
+	client := outbound.NewClient(config.Fetch)
+	chains, err := newDestinationResolver(ctx, repository, domains, builder, config.Chain, client)
+	if err != nil {
+		return nil, err
+	}
+	useCase := &UrlUseCase{
+		r:        repository,
+		d:        domains,
+		b:        builder,
+		p:        policy,
+		l:        limiter,
+		c:        config,
+		clicks:   clicks,
+		rules:    rules,
+		ua:       agents,
+		geo:      geo,
+		variants: variants,
+		tags:     tags,
+		folders:  folders,
+		chains:   chains,
+		cache:    urlCache,
+	}
+	if config.Fetch.MetadataWorkers > 0 {
+		fetcher := metadata.NewFetcher(client, config.Fetch.MaxBodySize)
+		useCase.metadata = NewMetadataWorker(ctx, repository, fetcher, config.Fetch)
+	}
+	return useCase, nil
 }
 
 func (us *UrlUseCase) CreateShortUrl(request dto.CreateShortUrlUseCaseRequest) (dto.CreateShortUrlResponse, error) {
+	return us.createShortUrl(dto.CreateShortUrlWithCustomIdRequest{
+		Url:          request.Url,
+		Domain:       request.Domain,
+		Password:     request.Password,
+		Interstitial: request.Interstitial,
+		RedirectType: request.RedirectType,
+		QueryMode:    request.QueryMode,
+		ForwardPath:  request.ForwardPath,
+		Utm:          request.Utm,
+		Dedup:        request.Dedup,
+		OwnerId:      request.OwnerId,
+		BaseUrl:      request.BaseUrl,
+		ClientIp:     request.ClientIp,
+	})
+}
+
+func (us *UrlUseCase) CreateShortUrlWithCustomId(request dto.CreateShortUrlWithCustomIdRequest) (dto.CreateShortUrlResponse, error) {
+	return us.createShortUrl(request)
+}
+
+func (us *UrlUseCase) createShortUrl(request dto.CreateShortUrlWithCustomIdRequest) (dto.CreateShortUrlResponse, error) {
 	model := dto.CreateShortUrlResponse{}
-	// Проверяем, существует ли уже запись с таким OriginalUrl
-	existingUrl, err := us.r.FindByUrl(request.Url)
 
+	domain, err := findVerifiedDomain(us.d, strings.ToLower(request.Domain))
 	if err != nil {
 		return model, err
 	}
 
-	if existingUrl != nil {
-		returnUrl := fmt.Sprintf("%s:%s/%s", us.c.App.Hostname, us.c.App.Port, existingUrl.Id)
-		model.Url = returnUrl
-		model.ClickCount = existingUrl.ClickCount
-		return model, nil
-	}
-	result, err := us.r.Save(request.Url, "")
-
+	prepared, err := prepareUrl(us.r, request)
 	if err != nil {
 		return model, err
 	}
 
-	returnUrl := fmt.Sprintf("%s:%s/%s", us.c.App.Hostname, us.c.App.Port, result.Id)
-	model.Url = returnUrl
+	result := prepared.existing
+	if result == nil {
+		if err = us.resolveDestination(prepared.url, request.BaseUrl); err != nil {
+			return model, err
+		}
+		limit, err := us.p.createLimit(request.OwnerId, request.ClientIp, prepared.url.CustomId)
+		if err != nil {
+			return model, err
+		}
+
+		result, err = us.saveOrReuse(prepared.url, limit)
+		if err != nil {
+			return model, err
+		}
+	}
+
+	model.Url = us.b.Build(request.BaseUrl, domain, result.Id)
 	model.ClickCount = result.ClickCount
 	return model, nil
 }
 
-func (us *UrlUseCase) CreateShortUrlWithCustomId(request dto.CreateShortUrlWithCustomIdRequest) (dto.CreateShortUrlResponse, error) {
-	model := dto.CreateShortUrlResponse{}
-	// Проверяем, существует ли уже запись с таким OriginalUrl
-	existingUrl, err := us.r.FindByUrl(request.Url)
+// saveOrReuse сохраняет ссылку, а если такую же ссылку успел создать параллельный запрос - возвращает её
+func (us *UrlUseCase) saveOrReuse(model *url.Url, limit url.Limit) (*url.Url, error) {
+	result, err := us.saveWithin(model, limit)
+	if errors.Is(err, url.ErrUrlExists) {
+		if result, err = us.r.FindByDedupKey(model.DedupKey); err == nil && result == nil {
+			err = url.ErrUrlExists
+		}
+		return result, err
+	}
+	if err != nil {
+		return nil, err
+	}
+	us.enqueueMetadata(result)
+	return result, nil
+}
 
+// saveWithin сохраняет ссылку, проверяя лимит в той же транзакции
+func (us *UrlUseCase) saveWithin(model *url.Url, limit url.Limit) (*url.Url, error) {
+	if !limit.Enabled() {
+		return us.r.Save(model)
+	}
+	saved, err := us.saveManyWithin([]*url.Url{model}, limit)
 	if err != nil {
-		return model, err
+		return nil, err
 	}
+	return saved[0], nil
+}
 
-	if existingUrl != nil {
-		returnUrl := fmt.Sprintf("%s:%s/%s", us.c.App.Hostname, us.c.App.Port, existingUrl.Id)
-		model.Url = returnUrl
-		model.ClickCount = existingUrl.ClickCount
-		return model, nil
+// saveManyWithin сохраняет ссылки одной транзакцией, проверяя лимит в ней же
+func (us *UrlUseCase) saveManyWithin(models []*url.Url, limit url.Limit) ([]*url.Url, error) {
+	if !limit.Enabled() {
+		return us.r.SaveMany(models)
 	}
-	result, err := us.r.Save(request.Url, request.Id)
+	saved, err := us.r.SaveWithin(models, limit)
+	return saved, exceeded(err, limit)
+}
+
+// preparedUrl - проверенный запрос на создание: новая ссылка или уже существующая такая же ссылка
+type preparedUrl struct {
+	url      *url.Url
+	existing *url.Url
+}
 
+// prepareUrl проверяет запрос и собирает ссылку, не сохраняя её. Домен запроса должен быть уже проверен.
+func prepareUrl(r url.RepositoryInterface, request dto.CreateShortUrlWithCustomIdRequest) (preparedUrl, error) {
+	model, err := newUrl(request)
 	if err != nil {
-		return model, err
+		return preparedUrl{}, err
 	}
 
-	returnUrl := fmt.Sprintf("%s:%s/%s", us.c.App.Hostname, us.c.App.Port, result.Id)
-	model.Url = returnUrl
-	model.ClickCount = result.ClickCount
-	return model, nil
+	policy, err := url.ParseDedupPolicy(request.Dedup)
+	if err != nil {
+		return preparedUrl{}, err
+	}
+
+	// Ссылка с собственным id или собственными настройками перехода всегда создаётся заново:
+	// нельзя выдать вместо запрошенного id другой или существующую ссылку с другими настройками
+	if request.Id == "" && !hasLinkOptions(request) {
+		model.DedupKey = url.DedupKey(policy, model.Domain, model.OwnerId, model.OriginalUrl)
+		existingUrl, err := findReusable(r, policy, model)
+		if err != nil {
+			return preparedUrl{}, err
+		}
+		if existingUrl != nil {
+			return preparedUrl{existing: existingUrl}, nil
+		}
+	}
+
+	if model.PasswordHash, err = hashPassword(request.Password); err != nil {
+		return preparedUrl{}, err
+	}
+	return preparedUrl{url: model}, nil
 }
 
-func (us *UrlUseCase) GetUrlList(pagination dto.PaginationRequest) ([]dto.UrlInfoResponse, error) {
-	urlsRepositoryInfo, err := us.r.FindAll(pagination.Page, pagination.Limit)
+// findReusable ищет существующую ссылку, которую можно выдать вместо новой.
+// При DedupOwner подходит и общая ссылка, если её создал тот же владелец.
+func findReusable(r url.RepositoryInterface, policy url.DedupPolicy, model *url.Url) (*url.Url, error) {
+	if model.DedupKey == "" {
+		return nil, nil
+	}
+	existing, err := r.FindByDedupKey(model.DedupKey)
+	if err != nil || existing != nil || policy != url.DedupOwner {
+		return existing, err
+	}
+
+	existing, err = r.FindByDedupKey(url.DedupKey(url.DedupReuse, model.Domain, model.OwnerId, model.OriginalUrl))
+	if err != nil || existing == nil || existing.OwnerId != model.OwnerId {
+		return nil, err
+	}
+	return existing, nil
+}
+
+// newUrl проверяет настройки запроса и собирает ссылку без хэша пароля
+func newUrl(request dto.CreateShortUrlWithCustomIdRequest) (*url.Url, error) {
+	redirectType, err := url.ParseRedirectType(request.RedirectType)
+	if err != nil {
+		return nil, err
+	}
+
+	queryMode, err := url.ParseQueryMode(request.QueryMode)
+	if err != nil {
+		return nil, err
+	}
+
+	utm, err := newUtm(request.Utm)
 	if err != nil {
 		return nil, err
 	}
 
-	return us.transformSliceToUrlInfo(urlsRepositoryInfo), nil
+	return &url.Url{
+		OriginalUrl:  request.Url,
+		Id:           request.Id,
+		Domain:       strings.ToLower(request.Domain),
+		OwnerId:      request.OwnerId,
+		CreatorIp:    creatorIp(request),
+		CustomId:     request.Id != "",
+		Interstitial: request.Interstitial,
+		RedirectType: redirectType,
+		QueryMode:    queryMode,
+		ForwardPath:  request.ForwardPath,
+		Utm:          utm,
+	}, nil
+}
+
+// creatorIp возвращает адрес клиента для анонимной ссылки, ссылки владельцев считаются по владельцу
+func creatorIp(request dto.CreateShortUrlWithCustomIdRequest) string {
+	if request.OwnerId != "" {
+		return ""
+	}
+	return request.ClientIp
 }
 
-func (us *UrlUseCase) ClickUrl(request dto.UrlClickRequest) (string, error) {
-	urlRepository, err := us.r.FindById(request.Id)
+// maxSearchLength - наибольшая длина строки поиска по списку
+const maxSearchLength = 200
+
+// GetUrlList читает список ссылок по курсору, а при заданном номере страницы - со смещением
+func (us *UrlUseCase) GetUrlList(pagination dto.PaginationRequest) (dto.UrlListResponse, error) {
+	response := dto.UrlListResponse{}
+	filter, err := newListFilter(pagination.UrlListFilter, pagination.Utm)
 	if err != nil {
-		return "", err
+		return response, err
+	}
+	// Как и статистика папки, список папки включает ссылки вложенных папок
+	if pagination.FolderId != "" {
+		tree, err := ownerFolders(us.folders, pagination.OwnerId)
+		if err != nil {
+			return response, err
+		}
+		if _, ok := tree[pagination.FolderId]; !ok {
+			return response, folder.ErrNotFound
+		}
+		filter.FolderIds = tree.Subtree(pagination.FolderId)
 	}
-	urlRepository.ClickCount++
 
-	urlRepository, err = us.r.Update(urlRepository)
+	var urls []*url.Url
+	if pagination.Cursor == "" && pagination.Page > 0 {
+		urls, err = us.r.FindAll(pagination.Page, pagination.Limit, filter)
+		if len(urls) == pagination.Limit {
+			response.NextPage = pagination.Page + 1
+		}
+		response.PrevPage = pagination.Page - 1
+	} else {
+		urls, err = us.findUrlPage(filter, pagination, &response)
+	}
+	if err != nil {
+		return response, err
+	}
+
+	if pagination.Total {
+		total, err := us.r.Count(filter)
+		if err != nil {
+			return response, err
+		}
+		response.Total = &total
+	}
+
+	response.Data, err = us.transformSliceToUrlInfo(urls, pagination.BaseUrl, pagination.OwnerId)
+	return response, err
+}
 
+// newListFilter проверяет условия списка и переводит их в фильтр репозитория
+func newListFilter(request dto.UrlListFilter, requestUtm dto.Utm) (url.Filter, error) {
+	utm, err := newUtm(requestUtm)
 	if err != nil {
-		return "", err
+		return url.Filter{}, err
 	}
 
-	if !strings.HasPrefix(urlRepository.OriginalUrl, "http://") && !strings.HasPrefix(urlRepository.OriginalUrl, "https://") {
-		urlRepository.OriginalUrl = "https://" + urlRepository.OriginalUrl
+	filter := url.Filter{
+		Utm:         utm,
+		OwnerId:     request.OwnerId,
+		Domain:      strings.ToLower(request.Domain),
+		CreatedFrom: request.From,
+		MinClicks:   request.MinClicks,
+		MaxClicks:   request.MaxClicks,
+		Search:      strings.TrimSpace(request.Search),
+		Broken:      request.Broken,
+	}
+	if filter.Tags, err = tag.NormalizeAll(request.Tags); err != nil {
+		return url.Filter{}, err
+	}
+	if len(filter.Tags) == 0 {
+		filter.Tags = nil
+	}
+	if request.To != nil {
+		to := request.To.AddDate(0, 0, 1)
+		filter.CreatedTo = &to
+	}
+	if filter.MinClicks != nil && filter.MaxClicks != nil && *filter.MinClicks > *filter.MaxClicks {
+		return url.Filter{}, fmt.Errorf("%w: min_clicks is greater than max_clicks", url.ErrInvalidFilter)
+	}
+	if len(filter.Search) > maxSearchLength {
+		return url.Filter{}, fmt.Errorf("%w: search is longer than %d bytes", url.ErrInvalidFilter, maxSearchLength)
 	}
 
-	return urlRepository.OriginalUrl, nil
+	switch status := url.Status(strings.ToLower(request.Status)); status {
+	case "", url.StatusPublic, url.StatusProtected:
+		filter.Status = status
+	default:
+		return url.Filter{}, fmt.Errorf("%w: unknown status %q", url.ErrInvalidFilter, request.Status)
+	}
+
+	field, desc := strings.CutPrefix(request.Sort, "-")
+	switch sort := url.SortField(strings.ToLower(field)); sort {
+	case "", url.SortCreatedDate, url.SortClickCount:
+		filter.Sort = url.Sort{Field: sort, Desc: desc}
+	default:
+		return url.Filter{}, fmt.Errorf("%w: unknown sort field %q", url.ErrInvalidFilter, field)
+	}
+	return filter, nil
 }
 
-func (us *UrlUseCase) transformSliceToUrlInfo(urls []*url.Url) []dto.UrlInfoResponse {
+// findUrlPage читает окно списка по курсору и заполняет курсоры соседних окон.
+// Из базы читается на одну ссылку больше, чтобы узнать, есть ли ссылки за окном.
+func (us *UrlUseCase) findUrlPage(filter url.Filter, pagination dto.PaginationRequest, response *dto.UrlListResponse) ([]*url.Url, error) {
+	page := url.Page{Limit: pagination.Limit + 1}
+	if pagination.Cursor != "" {
+		cursor, backward, err := decodeListCursor(pagination.Cursor, filter.Sort)
+		if err != nil {
+			return nil, err
+		}
+		page.Cursor, page.Backward = &cursor, backward
+	}
+
+	urls, err := us.r.FindPage(filter, page)
+	if err != nil {
+		return nil, err
+	}
+
+	more := len(urls) > pagination.Limit
+	if more && page.Backward {
+		urls = urls[len(urls)-pagination.Limit:]
+	} else if more {
+		urls = urls[:pagination.Limit]
+	}
+	if len(urls) == 0 {
+		return urls, nil
+	}
+
+	// С другой стороны курсора ссылки есть всегда: с них пришли к этому окну
+	if more || page.Backward {
+		response.NextCursor = encodeListCursor(urls[len(urls)-1], filter.Sort, false)
+	}
+	if (more && page.Backward) || (page.Cursor != nil && !page.Backward) {
+		response.PrevCursor = encodeListCursor(urls[0], filter.Sort, true)
+	}
+	return urls, nil
+}
+
+func (us *UrlUseCase) ClickUrl(request dto.UrlClickRequest) (dto.UrlClickResponse, error) {
+	response := dto.UrlClickResponse{}
+	urlRepository, err := us.findServedUrl(request.Id, request.Host)
+	if err != nil {
+		return response, err
+	}
+
+	// Местоположение и устройство посетителя нужны и правилам перенаправления, и статистике
+	location, err := us.geo.Lookup(net.ParseIP(request.ClientIp))
+	if err != nil {
+		return response, err
+	}
+	agent := us.ua.Parse(request.UserAgent)
+
+	destination, variant, err := us.routeDestination(urlRepository, request, agent, location)
+	if err != nil {
+		return response, err
+	}
+
+	destination, err = applyUtm(destination, urlRepository.Utm)
+	if err != nil {
+		return response, err
+	}
+
+	destination, err = forwardRequest(destination, urlRepository, request.Path, request.Query)
+	if err != nil {
+		return response, err
+	}
+
+	if urlRepository.IsProtected() && !request.Unlocked {
+		return response, url.ErrPasswordRequired
+	}
+
+	if urlRepository.Interstitial && !request.Confirmed && isExternal(destination, request.Host) {
+		return response, url.ErrInterstitialRequired
+	}
+
+	tracked, err := us.p.trackClick(urlRepository.OwnerId)
+	if err != nil {
+		return response, err
+	}
+
+	// Переходы сверх месячного лимита владельца не учитываются, но перенаправление работает
+	if tracked {
+		if err = us.r.IncrementClicks(urlRepository.Id); err != nil {
+			return response, err
+		}
+		urlRepository.ClickCount++
+
+		if err = us.recordClick(urlRepository, request, agent, location); err != nil {
+			return response, err
+		}
+
+		if variant != nil {
+			if err = us.variants.IncrementClicks(urlRepository.Id, variant.Name); err != nil {
+				return response, err
+			}
+		}
+	}
+
+	if variant != nil {
+		response.Variant = variant.Name
+		response.StickyVariant = urlRepository.SplitMode == url.SplitCookie
+	}
+	response.Url = destination
+	response.RedirectType = string(us.redirectType(urlRepository))
+	response.Private = urlRepository.IsProtected() || urlRepository.Interstitial || urlRepository.HasRules ||
+		urlRepository.QueryMode != url.QueryDrop || urlRepository.ForwardPath
+	return response, nil
+}
+
+// PreviewUrl возвращает сведения о ссылке без перехода и учёта клика
+func (us *UrlUseCase) PreviewUrl(request dto.UrlPreviewRequest) (dto.UrlPreviewResponse, error) {
+	urlRepository, err := us.findServedUrl(request.Id, request.Host)
+	if err != nil {
+		return dto.UrlPreviewResponse{}, err
+	}
+
+	// Адрес защищённой ссылки не раскрывается до ввода пароля
+	if urlRepository.IsProtected() && !request.Unlocked {
+		return dto.UrlPreviewResponse{}, url.ErrPasswordRequired
+	}
+
+	domain, err := findVerifiedDomain(us.d, urlRepository.Domain)
+	if err != nil {
+		return dto.UrlPreviewResponse{}, err
+	}
+
+	destination := destinationUrl(urlRepository)
+	return dto.UrlPreviewResponse{
+		Id:          urlRepository.Id,
+		ShortUrl:    us.b.Build(request.BaseUrl, domain, urlRepository.Id),
+		Destination: destination,
+		CountClick:  urlRepository.ClickCount,
+		CreatedDate: urlRepository.CreatedDate,
+		Safety:      safetyStatus(destination),
+	}, nil
+}
+
+// UnlockUrl проверяет пароль защищённой ссылки. Число неверных попыток ограничено для каждой ссылки и адреса клиента.
+func (us *UrlUseCase) UnlockUrl(request dto.UnlockUrlRequest) error {
+	urlRepository, err := us.findServedUrl(request.Id, request.Host)
+	if err != nil {
+		return err
+	}
+
+	if !urlRepository.IsProtected() {
+		return nil
+	}
+
+	// Попытки считаются для пары ссылка и адрес клиента. Токен берётся до проверки пароля,
+	// чтобы параллельные попытки не проходили все разом, и возвращается при верном пароле.
+	limit := rateLimit.NewLimit(us.c.RateLimit.Unlock)
+	key := "unlock:" + urlRepository.Id + ":" + request.ClientIp
+	if limit.Enabled() {
+		result, err := us.l.Take(key, limit)
+		if err != nil {
+			return err
+		}
+		if !result.Allowed {
+			return url.ErrTooManyAttempts
+		}
+	}
+
+	if err = bcrypt.CompareHashAndPassword([]byte(urlRepository.PasswordHash), []byte(request.Password)); err != nil {
+		return url.ErrInvalidPassword
+	}
+	if limit.Enabled() {
+		return us.l.Refund(key, limit)
+	}
+	return nil
+}
+
+// redirectType возвращает способ перенаправления ссылки с учётом значения по умолчанию
+func (us *UrlUseCase) redirectType(u *url.Url) url.RedirectType {
+	if u.RedirectType != "" {
+		return u.RedirectType
+	}
+	if us.c.App.DefaultRedirectType != "" {
+		return url.RedirectType(us.c.App.DefaultRedirectType)
+	}
+	return url.DefaultRedirectType
+}
+
+// findServedUrl находит ссылку, доступную на хосте запроса
+func (us *UrlUseCase) findServedUrl(id, host string) (*url.Url, error) {
+	urlRepository, err := us.r.FindById(id)
+	if err != nil {
+		return nil, err
+	}
+
+	served, err := us.isServedOnHost(urlRepository, host)
+	if err != nil {
+		return nil, err
+	}
+	if !served {
+		return nil, url.ErrNotFound
+	}
+	return urlRepository, nil
+}
+
+// findVerifiedDomain возвращает подтверждённый домен по хосту, для пустого хоста - nil
+func findVerifiedDomain(d customDomain.RepositoryInterface, hostname string) (*customDomain.Domain, error) {
+	if hostname == "" {
+		return nil, nil
+	}
+	domain, err := d.FindByHostname(hostname)
+	if err != nil {
+		return nil, err
+	}
+	if !domain.Verified {
+		return nil, customDomain.ErrNotVerified
+	}
+	return domain, nil
+}
+
+// isServedOnHost проверяет, что ссылка открыта на своём домене.
+// Ссылки брендированного домена доступны только на нём, ссылки по умолчанию -
+// на любом хосте, кроме подтверждённых брендированных доменов.
+func (us *UrlUseCase) isServedOnHost(u *url.Url, host string) (bool, error) {
+	if u.Domain != "" {
+		return strings.EqualFold(u.Domain, host), nil
+	}
+	if host == "" {
+		return true, nil
+	}
+	domain, err := us.d.FindByHostname(strings.ToLower(host))
+	if errors.Is(err, customDomain.ErrNotFound) {
+		return true, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return !domain.Verified, nil
+}
+
+// hasLinkOptions сообщает, что в запросе заданы настройки перехода
+func hasLinkOptions(request dto.CreateShortUrlWithCustomIdRequest) bool {
+	return request.Password != "" || request.Interstitial || request.RedirectType != "" ||
+		request.QueryMode != "" || request.ForwardPath || request.Utm != dto.Utm{}
+}
+
+// hashPassword возвращает bcrypt-хэш пароля, для пустого пароля - пустую строку
+func hashPassword(password string) (string, error) {
+	if password == "" {
+		return "", nil
+	}
+	if len(password) > 72 {
+		return "", url.ErrInvalidPassword
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", err
+	}
+	return string(hash), nil
+}
+
+func (us *UrlUseCase) transformSliceToUrlInfo(urls []*url.Url, baseUrl, viewerId string) ([]dto.UrlInfoResponse, error) {
 	var result []dto.UrlInfoResponse
+	if len(urls) == 0 {
+		return result, nil
+	}
+
+	ids := make([]string, 0, len(urls))
+	for _, u := range urls {
+		ids = append(ids, u.Id)
+	}
+	tags, err := us.tags.FindByUrls(ids)
+	if err != nil {
+		return nil, err
+	}
+
+	domains := make(map[string]*customDomain.Domain)
 	for i := range urls {
-		result = append(result, us.transformToUrlInfo(urls[i]))
+		hostname := urls[i].Domain
+		if _, ok := domains[hostname]; !ok && hostname != "" {
+			domain, err := us.d.FindByHostname(hostname)
+			if err != nil {
+				return nil, err
+			}
+			domains[hostname] = domain
+		}
+		info := us.transformToUrlInfo(urls[i], domains[hostname], baseUrl)
+		owned := urls[i].OwnerId != "" && urls[i].OwnerId == viewerId
+		// Адрес защищённой ссылки и его превью видит только владелец
+		if urls[i].IsProtected() && !owned {
+			info.OriginalUrl, info.ResolvedUrl, info.Metadata = "", "", nil
+		}
+		// Название, заметки, папку, метки и доступность страницы тоже видит только владелец
+		if owned {
+			info.Tags = tags[urls[i].Id]
+		} else {
+			info.Title, info.Notes, info.FolderId, info.Health = "", "", "", nil
+		}
+		if info.Tags == nil {
+			info.Tags = []string{}
+		}
+		result = append(result, info)
 	}
-	return result
+	return result, nil
 }
 
-func (us *UrlUseCase) transformToUrlInfo(repositoryUrl *url.Url) dto.UrlInfoResponse {
+func (us *UrlUseCase) transformToUrlInfo(repositoryUrl *url.Url, domain *customDomain.Domain, baseUrl string) dto.UrlInfoResponse {
 	return dto.UrlInfoResponse{
-		Id:          repositoryUrl.Id,
-		OriginalUrl: repositoryUrl.OriginalUrl,
-		ShortUrl:    fmt.Sprintf("%s:%s/%s", us.c.App.Hostname, us.c.App.Port, repositoryUrl.Id),
-		CountClick:  repositoryUrl.ClickCount,
-		CreatedDate: repositoryUrl.CreatedDate,
+		Id:           repositoryUrl.Id,
+		OriginalUrl:  repositoryUrl.OriginalUrl,
+		ResolvedUrl:  repositoryUrl.ResolvedUrl,
+		ShortUrl:     us.b.Build(baseUrl, domain, repositoryUrl.Id),
+		CountClick:   repositoryUrl.ClickCount,
+		CreatedDate:  repositoryUrl.CreatedDate,
+		Domain:       repositoryUrl.Domain,
+		Protected:    repositoryUrl.IsProtected(),
+		Interstitial: repositoryUrl.Interstitial,
+		RedirectType: string(repositoryUrl.RedirectType),
+		QueryMode:    string(repositoryUrl.QueryMode),
+		ForwardPath:  repositoryUrl.ForwardPath,
+		Utm:          transformToUtm(repositoryUrl.Utm),
+		Title:        repositoryUrl.Title,
+		Notes:        repositoryUrl.Notes,
+		FolderId:     repositoryUrl.FolderId,
+		Metadata:     transformToUrlMetadata(repositoryUrl.Metadata),
+		Health:       transformToUrlHealth(repositoryUrl.Health),
+	}
+}
+
+func transformToUrlMetadata(metadata url.Metadata) *dto.UrlMetadataResponse {
+	if metadata == (url.Metadata{}) {
+		return nil
+	}
+	return &dto.UrlMetadataResponse{
+		Title:       metadata.Title,
+		Description: metadata.Description,
+		Image:       metadata.Image,
+		SiteName:    metadata.SiteName,
+		Favicon:     metadata.Favicon,
 	}
 }
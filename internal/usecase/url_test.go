@@ -4,9 +4,15 @@ import (
 	"errors"
 	"fmt"
 	"leenwood/yandex-http/config"
+	clickMocks "leenwood/yandex-http/internal/domain/click/mocks"
+	"leenwood/yandex-http/internal/domain/customDomain"
+	domainMocks "leenwood/yandex-http/internal/domain/customDomain/mocks"
+	tagMocks "leenwood/yandex-http/internal/domain/tag/mocks"
 	"leenwood/yandex-http/internal/domain/url"
 	"leenwood/yandex-http/internal/domain/url/mocks"
+	"leenwood/yandex-http/internal/geoIp"
 	"leenwood/yandex-http/internal/usecase/dto"
+	"leenwood/yandex-http/internal/userAgent"
 	"testing"
 	"time"
 
@@ -20,24 +26,48 @@ func TestNewUrlUseCase(t *testing.T) {
 	RunSpecs(t, "Url UseCase Test Suite")
 }
 
+// noGeoIp - определение местоположения без настроенной базы
+var noGeoIp, _ = geoIp.NewResolver(config.GeoIpConfig{})
+
+// agents - разбор User-Agent по встроенным правилам
+var agents, _ = userAgent.NewParser(100)
+
+// acceptClicks возвращает хранилище переходов, принимающее любые переходы
+func acceptClicks(ctrl *gomock.Controller) *clickMocks.MockRepositoryInterface {
+	clicks := clickMocks.NewMockRepositoryInterface(ctrl)
+	clicks.EXPECT().Save(gomock.Any()).Return(nil).AnyTimes()
+	return clicks
+}
+
+// noTags возвращает хранилище меток, в котором у ссылок нет меток
+func noTags(ctrl *gomock.Controller) *tagMocks.MockRepositoryInterface {
+	tags := tagMocks.NewMockRepositoryInterface(ctrl)
+	tags.EXPECT().FindByUrls(gomock.Any()).Return(map[string][]string{}, nil).AnyTimes()
+	return tags
+}
+
 var _ = Describe("UrlUseCase", func() {
 	var (
-		ctrl       *gomock.Controller
-		mockRepo   *mocks.MockRepositoryInterface
-		cfg        config.Config
-		urlUseCase UrlUseCaseInterface
+		ctrl        *gomock.Controller
+		mockRepo    *mocks.MockRepositoryInterface
+		mockDomains *domainMocks.MockRepositoryInterface
+		cfg         config.Config
+		urlUseCase  UrlUseCaseInterface
 	)
 
 	BeforeEach(func() {
 		ctrl = gomock.NewController(GinkgoT())
 		mockRepo = mocks.NewMockRepositoryInterface(ctrl)
+		mockDomains = domainMocks.NewMockRepositoryInterface(ctrl)
 		cfg = config.Config{
 			App: config.AppConfig{
 				Hostname: "localhost",
 				Port:     "8080",
 			},
 		}
-		urlUseCase = &UrlUseCase{r: mockRepo, c: cfg}
+		builder, err := NewShortUrlBuilder(cfg.App)
+		Expect(err).NotTo(HaveOccurred())
+		urlUseCase = &UrlUseCase{r: mockRepo, d: mockDomains, b: builder, c: cfg, clicks: acceptClicks(ctrl), geo: noGeoIp, ua: agents, tags: noTags(ctrl)}
 	})
 
 	AfterEach(func() {
@@ -49,13 +79,13 @@ var _ = Describe("UrlUseCase", func() {
 			It("should return the existing short URL", func() {
 				existingUrl := &url.Url{Id: "12345", OriginalUrl: "http://example.com", ClickCount: 10}
 
-				mockRepo.EXPECT().FindByUrl("http://example.com").Return(existingUrl, nil)
+				mockRepo.EXPECT().FindByDedupKey("http://example.com/").Return(existingUrl, nil)
 
 				request := dto.CreateShortUrlUseCaseRequest{Url: "http://example.com"}
 				response, err := urlUseCase.CreateShortUrl(request)
 
 				Expect(err).NotTo(HaveOccurred())
-				expectedUrl := fmt.Sprintf("%s:%s/%s", cfg.App.Hostname, cfg.App.Port, existingUrl.Id)
+				expectedUrl := fmt.Sprintf("http://%s:%s/%s", cfg.App.Hostname, cfg.App.Port, existingUrl.Id)
 				Expect(response.Url).To(Equal(expectedUrl))
 				Expect(response.ClickCount).To(Equal(existingUrl.ClickCount))
 			})
@@ -63,15 +93,15 @@ var _ = Describe("UrlUseCase", func() {
 
 		Context("when the URL does not exist", func() {
 			It("should create a new short URL", func() {
-				mockRepo.EXPECT().FindByUrl("http://example.com").Return(nil, nil)
+				mockRepo.EXPECT().FindByDedupKey("http://example.com/").Return(nil, nil)
 				newUrl := &url.Url{Id: "67890", OriginalUrl: "http://example.com", ClickCount: 0}
-				mockRepo.EXPECT().Save("http://example.com", "").Return(newUrl, nil)
+				mockRepo.EXPECT().Save(&url.Url{OriginalUrl: "http://example.com", DedupKey: "http://example.com/"}).Return(newUrl, nil)
 
 				request := dto.CreateShortUrlUseCaseRequest{Url: "http://example.com"}
 				response, err := urlUseCase.CreateShortUrl(request)
 
 				Expect(err).NotTo(HaveOccurred())
-				expectedUrl := fmt.Sprintf("%s:%s/%s", cfg.App.Hostname, cfg.App.Port, newUrl.Id)
+				expectedUrl := fmt.Sprintf("http://%s:%s/%s", cfg.App.Hostname, cfg.App.Port, newUrl.Id)
 				Expect(response.Url).To(Equal(expectedUrl))
 				Expect(response.ClickCount).To(Equal(newUrl.ClickCount))
 			})
@@ -79,7 +109,7 @@ var _ = Describe("UrlUseCase", func() {
 
 		Context("when FindByUrl fails", func() {
 			It("should return an error", func() {
-				mockRepo.EXPECT().FindByUrl("http://example.com").Return(nil, errors.New("database error"))
+				mockRepo.EXPECT().FindByDedupKey("http://example.com/").Return(nil, errors.New("database error"))
 
 				request := dto.CreateShortUrlUseCaseRequest{Url: "http://example.com"}
 				response, err := urlUseCase.CreateShortUrl(request)
@@ -92,8 +122,8 @@ var _ = Describe("UrlUseCase", func() {
 
 		Context("when Save fails", func() {
 			It("should return an error", func() {
-				mockRepo.EXPECT().FindByUrl("http://example.com").Return(nil, nil)
-				mockRepo.EXPECT().Save("http://example.com", "").Return(nil, errors.New("save error"))
+				mockRepo.EXPECT().FindByDedupKey("http://example.com/").Return(nil, nil)
+				mockRepo.EXPECT().Save(&url.Url{OriginalUrl: "http://example.com", DedupKey: "http://example.com/"}).Return(nil, errors.New("save error"))
 
 				request := dto.CreateShortUrlUseCaseRequest{Url: "http://example.com"}
 				response, err := urlUseCase.CreateShortUrl(request)
@@ -105,24 +135,129 @@ var _ = Describe("UrlUseCase", func() {
 		})
 	})
 
+	Describe("CreateShortUrl with a redirect type", func() {
+		Context("when the redirect type is unknown", func() {
+			It("should return an error", func() {
+				request := dto.CreateShortUrlUseCaseRequest{Url: "http://example.com", RedirectType: "303"}
+				_, err := urlUseCase.CreateShortUrl(request)
+
+				Expect(err).To(MatchError(url.ErrInvalidRedirectType))
+			})
+		})
+
+		Context("when the redirect type is valid", func() {
+			It("should store it on a new link", func() {
+				newUrl := &url.Url{Id: "abc", OriginalUrl: "http://example.com", RedirectType: url.RedirectMetaRefresh}
+				mockRepo.EXPECT().Save(&url.Url{OriginalUrl: "http://example.com", RedirectType: url.RedirectMetaRefresh}).Return(newUrl, nil)
+
+				request := dto.CreateShortUrlUseCaseRequest{Url: "http://example.com", RedirectType: "meta-refresh"}
+				response, err := urlUseCase.CreateShortUrl(request)
+
+				Expect(err).NotTo(HaveOccurred())
+				Expect(response.Url).To(Equal("http://localhost:8080/abc"))
+			})
+		})
+	})
+
 	Describe("CreateShortUrlWithCustomId", func() {
 		Context("when creating a new short URL with a custom ID", func() {
 			It("should create the URL successfully", func() {
-				mockRepo.EXPECT().FindByUrl("http://example.com").Return(nil, nil)
 				newUrl := &url.Url{Id: "custom123", OriginalUrl: "http://example.com", ClickCount: 0}
-				mockRepo.EXPECT().Save("http://example.com", "custom123").Return(newUrl, nil)
+				mockRepo.EXPECT().Save(&url.Url{OriginalUrl: "http://example.com", Id: "custom123", CustomId: true}).Return(newUrl, nil)
 
 				request := dto.CreateShortUrlWithCustomIdRequest{Url: "http://example.com", Id: "custom123"}
 				response, err := urlUseCase.CreateShortUrlWithCustomId(request)
 
 				Expect(err).NotTo(HaveOccurred())
-				expectedUrl := fmt.Sprintf("%s:%s/%s", cfg.App.Hostname, cfg.App.Port, newUrl.Id)
+				expectedUrl := fmt.Sprintf("http://%s:%s/%s", cfg.App.Hostname, cfg.App.Port, newUrl.Id)
 				Expect(response.Url).To(Equal(expectedUrl))
 				Expect(response.ClickCount).To(Equal(newUrl.ClickCount))
 			})
 		})
 	})
 
+	Describe("CreateShortUrl on a custom domain", func() {
+		Context("when the domain is verified", func() {
+			It("should build the short URL from the domain scheme and hostname", func() {
+				domain := &customDomain.Domain{Hostname: "go.acme.io", Scheme: "https", Verified: true}
+				mockDomains.EXPECT().FindByHostname("go.acme.io").Return(domain, nil)
+				mockRepo.EXPECT().FindByDedupKey("go.acme.io http://example.com/").Return(nil, nil)
+				newUrl := &url.Url{Id: "abcde", OriginalUrl: "http://example.com", Domain: "go.acme.io"}
+				mockRepo.EXPECT().Save(&url.Url{OriginalUrl: "http://example.com", Domain: "go.acme.io", DedupKey: "go.acme.io http://example.com/"}).Return(newUrl, nil)
+
+				request := dto.CreateShortUrlUseCaseRequest{Url: "http://example.com", Domain: "Go.Acme.io"}
+				response, err := urlUseCase.CreateShortUrl(request)
+
+				Expect(err).NotTo(HaveOccurred())
+				Expect(response.Url).To(Equal("https://go.acme.io/abcde"))
+			})
+		})
+
+		Context("when the domain is not verified", func() {
+			It("should return an error", func() {
+				domain := &customDomain.Domain{Hostname: "go.acme.io", Scheme: "https"}
+				mockDomains.EXPECT().FindByHostname("go.acme.io").Return(domain, nil)
+
+				request := dto.CreateShortUrlUseCaseRequest{Url: "http://example.com", Domain: "go.acme.io"}
+				_, err := urlUseCase.CreateShortUrl(request)
+
+				Expect(err).To(MatchError(customDomain.ErrNotVerified))
+			})
+		})
+	})
+
+	Describe("ClickUrl with a request host", func() {
+		Context("when the link belongs to another domain", func() {
+			It("should return not found", func() {
+				mockUrl := &url.Url{Id: "abcde", OriginalUrl: "http://example.com", Domain: "go.acme.io"}
+				mockRepo.EXPECT().FindById("abcde").Return(mockUrl, nil)
+
+				_, err := urlUseCase.ClickUrl(dto.UrlClickRequest{Id: "abcde", Host: "localhost"})
+
+				Expect(err).To(MatchError(url.ErrNotFound))
+			})
+		})
+
+		Context("when a default link is opened on a custom domain", func() {
+			It("should return not found", func() {
+				mockUrl := &url.Url{Id: "abcde", OriginalUrl: "http://example.com"}
+				mockRepo.EXPECT().FindById("abcde").Return(mockUrl, nil)
+				mockDomains.EXPECT().FindByHostname("go.acme.io").Return(&customDomain.Domain{Hostname: "go.acme.io", Verified: true}, nil)
+
+				_, err := urlUseCase.ClickUrl(dto.UrlClickRequest{Id: "abcde", Host: "go.acme.io"})
+
+				Expect(err).To(MatchError(url.ErrNotFound))
+			})
+		})
+
+		Context("when a default link is opened on a domain that is not verified", func() {
+			It("should redirect", func() {
+				mockUrl := &url.Url{Id: "abcde", OriginalUrl: "http://example.com"}
+				mockRepo.EXPECT().FindById("abcde").Return(mockUrl, nil)
+				mockDomains.EXPECT().FindByHostname("go.acme.io").Return(&customDomain.Domain{Hostname: "go.acme.io"}, nil)
+				mockRepo.EXPECT().IncrementClicks("abcde").Return(nil)
+
+				response, err := urlUseCase.ClickUrl(dto.UrlClickRequest{Id: "abcde", Host: "go.acme.io"})
+
+				Expect(err).NotTo(HaveOccurred())
+				Expect(response.Url).To(Equal("http://example.com"))
+			})
+		})
+
+		Context("when the link is opened on its own domain", func() {
+			It("should redirect", func() {
+				mockUrl := &url.Url{Id: "abcde", OriginalUrl: "http://example.com", Domain: "go.acme.io"}
+				mockRepo.EXPECT().FindById("abcde").Return(mockUrl, nil)
+				mockRepo.EXPECT().IncrementClicks("abcde").Return(nil)
+
+				response, err := urlUseCase.ClickUrl(dto.UrlClickRequest{Id: "abcde", Host: "go.acme.io"})
+
+				Expect(err).NotTo(HaveOccurred())
+				Expect(response.Url).To(Equal("http://example.com"))
+			})
+		})
+	})
+
 	Describe("GetUrlList", func() {
 		Context("when URLs exist in the repository", func() {
 			It("should return the paginated list of URLs", func() {
@@ -143,20 +278,201 @@ var _ = Describe("UrlUseCase", func() {
 					},
 				}
 
-				mockRepo.EXPECT().FindAll(pagination.Page, pagination.Limit).Return(mockUrls, nil)
+				mockRepo.EXPECT().FindAll(pagination.Page, pagination.Limit, url.Filter{}).Return(mockUrls, nil)
+
+				response, err := urlUseCase.GetUrlList(pagination)
+
+				Expect(err).NotTo(HaveOccurred())
+				Expect(response.Data).To(HaveLen(2))
+				Expect(response.Data[0].OriginalUrl).To(Equal("http://example1.com"))
+				Expect(response.Data[0].ShortUrl).To(Equal(fmt.Sprintf("http://%s:%s/%s", cfg.App.Hostname, cfg.App.Port, "id1")))
+				Expect(response.Data[0].CountClick).To(Equal(*getLink[uint64](5)))
+				Expect(response.Data[0].CreatedDate).To(Equal(createdDate))
+				Expect(response.Data[1].OriginalUrl).To(Equal("http://example2.com"))
+				Expect(response.Data[1].ShortUrl).To(Equal(fmt.Sprintf("http://%s:%s/%s", cfg.App.Hostname, cfg.App.Port, "id2")))
+				Expect(response.Data[1].CountClick).To(Equal(*getLink[uint64](10)))
+				Expect(response.Data[1].CreatedDate).To(Equal(createdDate))
+				Expect(response.NextPage).To(Equal(2))
+				Expect(response.PrevPage).To(BeZero())
+			})
+		})
+
+		Context("when the list has protected links", func() {
+			var protected []*url.Url
+
+			BeforeEach(func() {
+				metadata := url.Metadata{Title: "Private docs"}
+				protected = []*url.Url{
+					{Id: "owned", OriginalUrl: "http://example.com/owned", ResolvedUrl: "http://example.com/final", PasswordHash: "hash", OwnerId: "alice", Metadata: metadata},
+					{Id: "anonymous", OriginalUrl: "http://example.com/anonymous", PasswordHash: "hash", Metadata: metadata},
+				}
+			})
+
+			It("should hide their destination from anonymous callers", func() {
+				pagination := dto.PaginationRequest{Page: 1, Limit: 10}
+				mockRepo.EXPECT().FindAll(pagination.Page, pagination.Limit, url.Filter{}).Return(protected, nil)
+
+				response, err := urlUseCase.GetUrlList(pagination)
+
+				Expect(err).NotTo(HaveOccurred())
+				Expect(response.Data).To(HaveLen(2))
+				for _, info := range response.Data {
+					Expect(info.Protected).To(BeTrue())
+					Expect(info.OriginalUrl).To(BeEmpty())
+					Expect(info.ResolvedUrl).To(BeEmpty())
+					Expect(info.Metadata).To(BeNil())
+				}
+			})
+
+			It("should show the destination to the owner", func() {
+				pagination := dto.PaginationRequest{Page: 1, Limit: 10, UrlListFilter: dto.UrlListFilter{OwnerId: "alice"}}
+				mockRepo.EXPECT().FindAll(pagination.Page, pagination.Limit, url.Filter{OwnerId: "alice"}).Return(protected[:1], nil)
+
+				response, err := urlUseCase.GetUrlList(pagination)
+
+				Expect(err).NotTo(HaveOccurred())
+				Expect(response.Data[0].OriginalUrl).To(Equal("http://example.com/owned"))
+				Expect(response.Data[0].ResolvedUrl).To(Equal("http://example.com/final"))
+				Expect(response.Data[0].Metadata).NotTo(BeNil())
+			})
+		})
+
+		Context("when the list has links of an owner", func() {
+			var owned []*url.Url
+
+			BeforeEach(func() {
+				checked := time.Now()
+				owned = []*url.Url{{
+					Id: "plan", OriginalUrl: "http://example.com/plan", OwnerId: "alice", Title: "Q3 plan", Notes: "do not share", FolderId: "private",
+					Health: url.Health{Status: 200, CheckedDate: &checked},
+				}}
+			})
+
+			It("should hide owner-only fields from anonymous callers", func() {
+				pagination := dto.PaginationRequest{Page: 1, Limit: 10}
+				mockRepo.EXPECT().FindAll(pagination.Page, pagination.Limit, url.Filter{}).Return(owned, nil)
+
+				response, err := urlUseCase.GetUrlList(pagination)
+
+				Expect(err).NotTo(HaveOccurred())
+				Expect(response.Data).To(HaveLen(1))
+				Expect(response.Data[0].OriginalUrl).To(Equal("http://example.com/plan"))
+				Expect(response.Data[0].Title).To(BeEmpty())
+				Expect(response.Data[0].Notes).To(BeEmpty())
+				Expect(response.Data[0].FolderId).To(BeEmpty())
+				Expect(response.Data[0].Health).To(BeNil())
+				Expect(response.Data[0].Tags).To(BeEmpty())
+			})
+
+			It("should show them to the owner", func() {
+				pagination := dto.PaginationRequest{Page: 1, Limit: 10, UrlListFilter: dto.UrlListFilter{OwnerId: "alice"}}
+				mockRepo.EXPECT().FindAll(pagination.Page, pagination.Limit, url.Filter{OwnerId: "alice"}).Return(owned, nil)
 
 				response, err := urlUseCase.GetUrlList(pagination)
 
 				Expect(err).NotTo(HaveOccurred())
-				Expect(response).To(HaveLen(2))
-				Expect(response[0].OriginalUrl).To(Equal("http://example1.com"))
-				Expect(response[0].ShortUrl).To(Equal(fmt.Sprintf("%s:%s/%s", cfg.App.Hostname, cfg.App.Port, "id1")))
-				Expect(response[0].CountClick).To(Equal(*getLink[uint64](5)))
-				Expect(response[0].CreatedDate).To(Equal(createdDate))
-				Expect(response[1].OriginalUrl).To(Equal("http://example2.com"))
-				Expect(response[1].ShortUrl).To(Equal(fmt.Sprintf("%s:%s/%s", cfg.App.Hostname, cfg.App.Port, "id2")))
-				Expect(response[1].CountClick).To(Equal(*getLink[uint64](10)))
-				Expect(response[1].CreatedDate).To(Equal(createdDate))
+				Expect(response.Data[0].Title).To(Equal("Q3 plan"))
+				Expect(response.Data[0].Notes).To(Equal("do not share"))
+				Expect(response.Data[0].FolderId).To(Equal("private"))
+				Expect(response.Data[0].Health).NotTo(BeNil())
+			})
+		})
+
+		Context("when the list is read by cursor", func() {
+			createdDate := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+			page := func(ids ...string) []*url.Url {
+				urls := make([]*url.Url, 0, len(ids))
+				for _, id := range ids {
+					urls = append(urls, &url.Url{Id: id, OriginalUrl: "http://example.com/" + id, CreatedDate: createdDate})
+				}
+				return urls
+			}
+
+			It("should return the first window with a next cursor and total", func() {
+				mockRepo.EXPECT().FindPage(url.Filter{}, url.Page{Limit: 3}).Return(page("a", "b", "c"), nil)
+				mockRepo.EXPECT().Count(url.Filter{}).Return(uint64(5), nil)
+
+				response, err := urlUseCase.GetUrlList(dto.PaginationRequest{Limit: 2, Total: true})
+
+				Expect(err).NotTo(HaveOccurred())
+				Expect(response.Data).To(HaveLen(2))
+				Expect(*response.Total).To(Equal(uint64(5)))
+				Expect(response.PrevCursor).To(BeEmpty())
+
+				cursor, backward, err := decodeListCursor(response.NextCursor, url.Sort{})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(cursor).To(Equal(url.Cursor{CreatedDate: createdDate, Id: "b"}))
+				Expect(backward).To(BeFalse())
+			})
+
+			It("should continue after the cursor", func() {
+				next := encodeListCursor(&url.Url{Id: "b", CreatedDate: createdDate}, url.Sort{}, false)
+				mockRepo.EXPECT().FindPage(url.Filter{}, url.Page{Cursor: &url.Cursor{CreatedDate: createdDate, Id: "b"}, Limit: 3}).
+					Return(page("c", "d"), nil)
+
+				response, err := urlUseCase.GetUrlList(dto.PaginationRequest{Limit: 2, Cursor: next})
+
+				Expect(err).NotTo(HaveOccurred())
+				Expect(response.Data).To(HaveLen(2))
+				Expect(response.NextCursor).To(BeEmpty())
+				Expect(response.PrevCursor).To(Equal(encodeListCursor(&url.Url{Id: "c", CreatedDate: createdDate}, url.Sort{}, true)))
+			})
+
+			It("should go back before the cursor", func() {
+				prev := encodeListCursor(&url.Url{Id: "d", CreatedDate: createdDate}, url.Sort{}, true)
+				mockRepo.EXPECT().FindPage(url.Filter{}, url.Page{Cursor: &url.Cursor{CreatedDate: createdDate, Id: "d"}, Backward: true, Limit: 3}).
+					Return(page("a", "b", "c"), nil)
+
+				response, err := urlUseCase.GetUrlList(dto.PaginationRequest{Limit: 2, Cursor: prev})
+
+				Expect(err).NotTo(HaveOccurred())
+				Expect(response.Data[0].Id).To(Equal("b"))
+				Expect(response.Data[1].Id).To(Equal("c"))
+				Expect(response.NextCursor).To(Equal(encodeListCursor(&url.Url{Id: "c", CreatedDate: createdDate}, url.Sort{}, false)))
+				Expect(response.PrevCursor).To(Equal(encodeListCursor(&url.Url{Id: "b", CreatedDate: createdDate}, url.Sort{}, true)))
+			})
+
+			It("should keep the sort order and filters of the list", func() {
+				minClicks := uint64(10)
+				filter := url.Filter{OwnerId: "acme", Status: url.StatusProtected, MinClicks: &minClicks, Search: "spring", Tags: []string{"promo"},
+					Sort: url.Sort{Field: url.SortClickCount, Desc: true}}
+				next := encodeListCursor(&url.Url{Id: "b", ClickCount: 42}, filter.Sort, false)
+				mockRepo.EXPECT().FindPage(filter, url.Page{Cursor: &url.Cursor{ClickCount: 42, Id: "b"}, Limit: 3}).
+					Return(page("c"), nil)
+
+				response, err := urlUseCase.GetUrlList(dto.PaginationRequest{Limit: 2, Cursor: next, UrlListFilter: dto.UrlListFilter{
+					OwnerId: "acme", Status: "protected", MinClicks: &minClicks, Search: " spring ", Tags: []string{"Promo"}, Sort: "-click_count",
+				}})
+
+				Expect(err).NotTo(HaveOccurred())
+				Expect(response.Data).To(HaveLen(1))
+			})
+
+			It("should reject a cursor of another sort order", func() {
+				next := encodeListCursor(&url.Url{Id: "b"}, url.Sort{}, false)
+
+				_, err := urlUseCase.GetUrlList(dto.PaginationRequest{Limit: 2, Cursor: next, UrlListFilter: dto.UrlListFilter{Sort: "click_count"}})
+
+				Expect(err).To(MatchError(url.ErrInvalidCursor))
+			})
+
+			It("should reject invalid filters", func() {
+				minClicks, maxClicks := uint64(10), uint64(5)
+
+				for _, filter := range []dto.UrlListFilter{
+					{Status: "archived"},
+					{Sort: "title"},
+					{MinClicks: &minClicks, MaxClicks: &maxClicks},
+				} {
+					_, err := urlUseCase.GetUrlList(dto.PaginationRequest{Limit: 2, UrlListFilter: filter})
+					Expect(err).To(MatchError(url.ErrInvalidFilter))
+				}
+			})
+
+			It("should reject a malformed cursor", func() {
+				_, err := urlUseCase.GetUrlList(dto.PaginationRequest{Limit: 2, Cursor: "not a cursor"})
+
+				Expect(err).To(MatchError(url.ErrInvalidCursor))
 			})
 		})
 
@@ -164,13 +480,13 @@ var _ = Describe("UrlUseCase", func() {
 			It("should return an error", func() {
 				pagination := dto.PaginationRequest{Page: 1, Limit: 2}
 
-				mockRepo.EXPECT().FindAll(pagination.Page, pagination.Limit).Return(nil, errors.New("repository error"))
+				mockRepo.EXPECT().FindAll(pagination.Page, pagination.Limit, url.Filter{}).Return(nil, errors.New("repository error"))
 
 				response, err := urlUseCase.GetUrlList(pagination)
 
 				Expect(err).To(HaveOccurred())
 				Expect(err.Error()).To(ContainSubstring("repository error"))
-				Expect(response).To(BeNil())
+				Expect(response.Data).To(BeNil())
 			})
 		})
 	})
@@ -179,7 +495,7 @@ var _ = Describe("UrlUseCase", func() {
 		BeforeEach(func() {
 			ctrl = gomock.NewController(GinkgoT())
 			mockRepo = mocks.NewMockRepositoryInterface(ctrl)
-			urlUseCase = &UrlUseCase{r: mockRepo}
+			urlUseCase = &UrlUseCase{r: mockRepo, clicks: acceptClicks(ctrl), geo: noGeoIp, ua: agents}
 		})
 
 		AfterEach(func() {
@@ -197,15 +513,42 @@ var _ = Describe("UrlUseCase", func() {
 				request := dto.UrlClickRequest{Id: "12345"}
 
 				mockRepo.EXPECT().FindById("12345").Return(mockUrl, nil)
-				mockRepo.EXPECT().Update(gomock.Any()).DoAndReturn(func(updatedUrl *url.Url) (*url.Url, error) {
-					Expect(updatedUrl.ClickCount).To(Equal(uint64(6)))
-					return updatedUrl, nil
-				})
+				mockRepo.EXPECT().IncrementClicks("12345").Return(nil)
 
 				response, err := urlUseCase.ClickUrl(request)
 
 				Expect(err).NotTo(HaveOccurred())
-				Expect(response).To(Equal("https://example.com"))
+				Expect(response.Url).To(Equal("https://example.com"))
+				Expect(mockUrl.ClickCount).To(Equal(uint64(6)))
+			})
+		})
+
+		Context("when the link has no own redirect type", func() {
+			It("should use the configured default", func() {
+				mockUrl := &url.Url{Id: "12345", OriginalUrl: "http://example.com"}
+				urlUseCase = &UrlUseCase{r: mockRepo, c: config.Config{App: config.AppConfig{DefaultRedirectType: "302"}}, clicks: acceptClicks(ctrl), geo: noGeoIp, ua: agents}
+
+				mockRepo.EXPECT().FindById("12345").Return(mockUrl, nil)
+				mockRepo.EXPECT().IncrementClicks("12345").Return(nil)
+
+				response, err := urlUseCase.ClickUrl(dto.UrlClickRequest{Id: "12345"})
+
+				Expect(err).NotTo(HaveOccurred())
+				Expect(response.RedirectType).To(Equal("302"))
+			})
+		})
+
+		Context("when the link has its own redirect type", func() {
+			It("should use it", func() {
+				mockUrl := &url.Url{Id: "12345", OriginalUrl: "http://example.com", RedirectType: url.RedirectPermanent}
+
+				mockRepo.EXPECT().FindById("12345").Return(mockUrl, nil)
+				mockRepo.EXPECT().IncrementClicks("12345").Return(nil)
+
+				response, err := urlUseCase.ClickUrl(dto.UrlClickRequest{Id: "12345"})
+
+				Expect(err).NotTo(HaveOccurred())
+				Expect(response.RedirectType).To(Equal("308"))
 			})
 		})
 
@@ -220,12 +563,12 @@ var _ = Describe("UrlUseCase", func() {
 				request := dto.UrlClickRequest{Id: "12345"}
 
 				mockRepo.EXPECT().FindById("12345").Return(mockUrl, nil)
-				mockRepo.EXPECT().Update(gomock.Any()).Return(mockUrl, nil)
+				mockRepo.EXPECT().IncrementClicks("12345").Return(nil)
 
 				response, err := urlUseCase.ClickUrl(request)
 
 				Expect(err).NotTo(HaveOccurred())
-				Expect(response).To(Equal("http://example.com"))
+				Expect(response.Url).To(Equal("http://example.com"))
 			})
 		})
 
@@ -239,7 +582,7 @@ var _ = Describe("UrlUseCase", func() {
 
 				Expect(err).To(HaveOccurred())
 				Expect(err.Error()).To(ContainSubstring("URL not found"))
-				Expect(response).To(BeEmpty())
+				Expect(response.Url).To(BeEmpty())
 			})
 		})
 
@@ -254,13 +597,13 @@ var _ = Describe("UrlUseCase", func() {
 				request := dto.UrlClickRequest{Id: "12345"}
 
 				mockRepo.EXPECT().FindById("12345").Return(mockUrl, nil)
-				mockRepo.EXPECT().Update(gomock.Any()).Return(nil, errors.New("update error"))
+				mockRepo.EXPECT().IncrementClicks("12345").Return(errors.New("update error"))
 
 				response, err := urlUseCase.ClickUrl(request)
 
 				Expect(err).To(HaveOccurred())
 				Expect(err.Error()).To(ContainSubstring("update error"))
-				Expect(response).To(BeEmpty())
+				Expect(response.Url).To(BeEmpty())
 			})
 		})
 	})
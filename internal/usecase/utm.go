@@ -0,0 +1,39 @@
+package usecase
+
+import (
+	"leenwood/yandex-http/internal/domain/url"
+	"leenwood/yandex-http/internal/usecase/dto"
+	neturl "net/url"
+)
+
+// newUtm проверяет метки кампании из запроса
+func newUtm(request dto.Utm) (url.Utm, error) {
+	return url.NewUtm(request.UtmSource, request.UtmMedium, request.UtmCampaign, request.UtmTerm, request.UtmContent)
+}
+
+func transformToUtm(utm url.Utm) dto.Utm {
+	return dto.Utm{
+		UtmSource:   utm.Source,
+		UtmMedium:   utm.Medium,
+		UtmCampaign: utm.Campaign,
+		UtmTerm:     utm.Term,
+		UtmContent:  utm.Content,
+	}
+}
+
+// applyUtm добавляет метки кампании к адресу перехода, заменяя одноимённые параметры адреса
+func applyUtm(destination string, utm url.Utm) (string, error) {
+	if utm.IsEmpty() {
+		return destination, nil
+	}
+	parsed, err := neturl.Parse(destination)
+	if err != nil {
+		return "", err
+	}
+	query := parsed.Query()
+	for key, values := range utm.Values() {
+		query[key] = values
+	}
+	parsed.RawQuery = query.Encode()
+	return parsed.String(), nil
+}
@@ -0,0 +1,79 @@
+package usecase
+
+import (
+	"leenwood/yandex-http/internal/domain/url"
+	"leenwood/yandex-http/internal/domain/url/mocks"
+	"leenwood/yandex-http/internal/usecase/dto"
+	"strings"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("UTM campaigns", func() {
+	var (
+		ctrl       *gomock.Controller
+		mockRepo   *mocks.MockRepositoryInterface
+		urlUseCase UrlUseCaseInterface
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		mockRepo = mocks.NewMockRepositoryInterface(ctrl)
+		urlUseCase = &UrlUseCase{r: mockRepo, clicks: acceptClicks(ctrl), geo: noGeoIp, ua: agents}
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	It("should append campaign parameters on redirect", func() {
+		mockUrl := &url.Url{
+			Id:          "abc",
+			OriginalUrl: "https://example.com/?utm_source=old&page=1",
+			Utm:         url.Utm{Source: "newsletter", Campaign: "spring"},
+		}
+		mockRepo.EXPECT().FindById("abc").Return(mockUrl, nil)
+		mockRepo.EXPECT().IncrementClicks("abc").Return(nil)
+
+		response, err := urlUseCase.ClickUrl(dto.UrlClickRequest{Id: "abc"})
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(response.Url).To(Equal("https://example.com/?page=1&utm_campaign=spring&utm_source=newsletter"))
+	})
+
+	It("should store campaign parameters apart from the destination", func() {
+		mockRepo.EXPECT().Save(gomock.Any()).DoAndReturn(func(u *url.Url) (*url.Url, error) {
+			Expect(u.OriginalUrl).To(Equal("example.com"))
+			Expect(u.Utm).To(Equal(url.Utm{Source: "x", Medium: "email"}))
+			u.Id = "abc"
+			return u, nil
+		})
+
+		_, err := urlUseCase.CreateShortUrl(dto.CreateShortUrlUseCaseRequest{
+			Url: "example.com",
+			Utm: dto.Utm{UtmSource: " x ", UtmMedium: "email"},
+		})
+
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should reject overlong values", func() {
+		_, err := urlUseCase.CreateShortUrl(dto.CreateShortUrlUseCaseRequest{
+			Url: "example.com",
+			Utm: dto.Utm{UtmCampaign: strings.Repeat("a", 256)},
+		})
+
+		Expect(err).To(MatchError(url.ErrInvalidUtm))
+	})
+
+	It("should filter the list by campaign", func() {
+		filter := url.Filter{Utm: url.Utm{Campaign: "spring"}}
+		mockRepo.EXPECT().FindAll(1, 10, filter).Return([]*url.Url{}, nil)
+
+		_, err := urlUseCase.GetUrlList(dto.PaginationRequest{Page: 1, Limit: 10, Utm: dto.Utm{UtmCampaign: "spring"}})
+
+		Expect(err).NotTo(HaveOccurred())
+	})
+})
@@ -0,0 +1,123 @@
+package userAgent
+
+import (
+	_ "embed"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+//go:embed regexes.yaml
+var bundledRules []byte
+
+// ruleSet - файл правил в формате uap-core
+type ruleSet struct {
+	UserAgentParsers []ruleSpec `yaml:"user_agent_parsers"`
+	OsParsers        []ruleSpec `yaml:"os_parsers"`
+	DeviceParsers    []ruleSpec `yaml:"device_parsers"`
+}
+
+// ruleSpec объединяет поля замен всех трёх разделов файла, каждому разделу нужны только свои
+type ruleSpec struct {
+	Regex     string `yaml:"regex"`
+	RegexFlag string `yaml:"regex_flag"`
+
+	FamilyReplacement string `yaml:"family_replacement"`
+	V1Replacement     string `yaml:"v1_replacement"`
+	V2Replacement     string `yaml:"v2_replacement"`
+
+	OsReplacement   string `yaml:"os_replacement"`
+	OsV1Replacement string `yaml:"os_v1_replacement"`
+	OsV2Replacement string `yaml:"os_v2_replacement"`
+
+	DeviceReplacement string `yaml:"device_replacement"`
+}
+
+// matcher - скомпилированное правило: семейство и две части версии с необязательными заменами
+type matcher struct {
+	re           *regexp.Regexp
+	replacements [3]string
+}
+
+// result - семейство и версия, найденные правилом
+type result struct {
+	Family  string
+	Version string
+}
+
+func parseRules(data []byte) (browsers, systems, devices []matcher, err error) {
+	var set ruleSet
+	if err = yaml.Unmarshal(data, &set); err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to parse user agent rules: %w", err)
+	}
+
+	if browsers, err = compileRules(set.UserAgentParsers, func(s ruleSpec) [3]string {
+		return [3]string{s.FamilyReplacement, s.V1Replacement, s.V2Replacement}
+	}); err != nil {
+		return nil, nil, nil, err
+	}
+	if systems, err = compileRules(set.OsParsers, func(s ruleSpec) [3]string {
+		return [3]string{s.OsReplacement, s.OsV1Replacement, s.OsV2Replacement}
+	}); err != nil {
+		return nil, nil, nil, err
+	}
+	if devices, err = compileRules(set.DeviceParsers, func(s ruleSpec) [3]string {
+		return [3]string{s.DeviceReplacement}
+	}); err != nil {
+		return nil, nil, nil, err
+	}
+	return browsers, systems, devices, nil
+}
+
+func compileRules(specs []ruleSpec, replacements func(ruleSpec) [3]string) ([]matcher, error) {
+	matchers := make([]matcher, 0, len(specs))
+	for _, spec := range specs {
+		expression := spec.Regex
+		if spec.RegexFlag == "i" {
+			expression = "(?i)" + expression
+		}
+		re, err := regexp.Compile(expression)
+		if err != nil {
+			return nil, fmt.Errorf("invalid user agent rule %q: %w", spec.Regex, err)
+		}
+		matchers = append(matchers, matcher{re: re, replacements: replacements(spec)})
+	}
+	return matchers, nil
+}
+
+// match возвращает результат первого подошедшего правила
+func match(matchers []matcher, userAgent string) (result, bool) {
+	for _, m := range matchers {
+		groups := m.re.FindStringSubmatch(userAgent)
+		if groups == nil {
+			continue
+		}
+
+		var parts [3]string
+		for i := range parts {
+			if m.replacements[i] != "" {
+				parts[i] = expand(m.replacements[i], groups)
+			} else if i+1 < len(groups) {
+				parts[i] = groups[i+1]
+			}
+		}
+
+		version := parts[1]
+		if version != "" && parts[2] != "" {
+			version += "." + parts[2]
+		}
+		return result{Family: strings.TrimSpace(parts[0]), Version: version}, true
+	}
+	return result{}, false
+}
+
+// expand подставляет в замену группы вида $1
+func expand(replacement string, groups []string) string {
+	for i := len(groups) - 1; i > 0; i-- {
+		replacement = strings.ReplaceAll(replacement, "$"+strconv.Itoa(i), groups[i])
+	}
+	return replacement
+}
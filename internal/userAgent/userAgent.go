@@ -0,0 +1,97 @@
+package userAgent
+
+import (
+	"leenwood/yandex-http/internal/cache"
+	"leenwood/yandex-http/internal/domain/rule"
+	"strings"
+)
+
+// maxUserAgentLength - длиннее заголовок обрезается, чтобы ограничить время разбора и размер кэша
+const maxUserAgentLength = 512
+
+// Client - сведения о клиенте, полученные из заголовка User-Agent
+type Client struct {
+	Browser        string
+	BrowserVersion string
+	// Os - семейство ОС, для правил перенаправления сравнивается без учёта регистра
+	Os        string
+	OsVersion string
+	// Device - тип устройства: mobile, tablet, desktop или bot
+	Device string
+}
+
+type ParserInterface interface {
+	Parse(userAgent string) Client
+}
+
+// Parser разбирает User-Agent по правилам в формате uap-core и кэширует результаты
+type Parser struct {
+	browsers []matcher
+	systems  []matcher
+	devices  []matcher
+	cache    *cache.Lru[string, Client]
+}
+
+// NewParser создаёт разборщик со встроенными правилами и кэшем на cacheSize заголовков
+func NewParser(cacheSize int) (*Parser, error) {
+	return NewParserWithRules(bundledRules, cacheSize)
+}
+
+// NewParserWithRules создаёт разборщик с правилами из файла формата uap-core
+func NewParserWithRules(rules []byte, cacheSize int) (*Parser, error) {
+	browsers, systems, devices, err := parseRules(rules)
+	if err != nil {
+		return nil, err
+	}
+	return &Parser{
+		browsers: browsers,
+		systems:  systems,
+		devices:  devices,
+		cache:    cache.NewLru[string, Client](cacheSize),
+	}, nil
+}
+
+func (p *Parser) Parse(userAgent string) Client {
+	if len(userAgent) > maxUserAgentLength {
+		userAgent = userAgent[:maxUserAgentLength]
+	}
+	if strings.TrimSpace(userAgent) == "" {
+		return Client{}
+	}
+
+	if client, ok := p.cache.Get(userAgent); ok {
+		return client
+	}
+
+	client := p.parse(userAgent)
+	p.cache.Add(userAgent, client)
+	return client
+}
+
+func (p *Parser) parse(userAgent string) Client {
+	var client Client
+	if browser, ok := match(p.browsers, userAgent); ok {
+		client.Browser, client.BrowserVersion = browser.Family, browser.Version
+	}
+	if system, ok := match(p.systems, userAgent); ok {
+		client.Os, client.OsVersion = system.Family, system.Version
+	}
+
+	device, _ := match(p.devices, userAgent)
+	client.Device = deviceClass(device.Family)
+	return client
+}
+
+// deviceClass сводит семейство устройства uap-core к типу устройства
+func deviceClass(family string) string {
+	switch family {
+	case "Spider":
+		return rule.DeviceBot
+	case "iPad", "Kindle", "Generic Tablet":
+		return rule.DeviceTablet
+	case "iPhone", "iPod", "Generic Smartphone", "Generic Feature Phone":
+		return rule.DeviceMobile
+	default:
+		return rule.DeviceDesktop
+	}
+}
@@ -0,0 +1,91 @@
+package userAgent
+
+import (
+	"leenwood/yandex-http/internal/domain/rule"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestUserAgent(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "UserAgent Test Suite")
+}
+
+var _ = Describe("Parser", func() {
+	var parser *Parser
+
+	BeforeEach(func() {
+		var err error
+		parser, err = NewParser(16)
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	DescribeTable("should parse bundled user agents",
+		func(userAgent string, expected Client) {
+			Expect(parser.Parse(userAgent)).To(Equal(expected))
+		},
+		Entry("desktop chrome",
+			"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36",
+			Client{Browser: "Chrome", BrowserVersion: "120.0", Os: "Windows", OsVersion: "10", Device: rule.DeviceDesktop}),
+		Entry("edge",
+			"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36 Edg/120.0.2210.91",
+			Client{Browser: "Edge", BrowserVersion: "120.0", Os: "Windows", OsVersion: "10", Device: rule.DeviceDesktop}),
+		Entry("iphone safari",
+			"Mozilla/5.0 (iPhone; CPU iPhone OS 17_1_2 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.1.2 Mobile/15E148 Safari/604.1",
+			Client{Browser: "Mobile Safari", BrowserVersion: "17.1", Os: "iOS", OsVersion: "17.1", Device: rule.DeviceMobile}),
+		Entry("ipad",
+			"Mozilla/5.0 (iPad; CPU OS 16_6 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) CriOS/119.0.6045.169 Mobile/15E148 Safari/604.1",
+			Client{Browser: "Chrome Mobile iOS", BrowserVersion: "119.0", Os: "iOS", OsVersion: "16.6", Device: rule.DeviceTablet}),
+		Entry("android phone",
+			"Mozilla/5.0 (Linux; Android 14; Pixel 8) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.6099.43 Mobile Safari/537.36",
+			Client{Browser: "Chrome Mobile", BrowserVersion: "120.0", Os: "Android", OsVersion: "14", Device: rule.DeviceMobile}),
+		Entry("android tablet",
+			"Mozilla/5.0 (Linux; Android 13; SM-X700) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.6099.43 Safari/537.36",
+			Client{Browser: "Chrome", BrowserVersion: "120.0", Os: "Android", OsVersion: "13", Device: rule.DeviceTablet}),
+		Entry("mac firefox",
+			"Mozilla/5.0 (Macintosh; Intel Mac OS X 10.15; rv:121.0) Gecko/20100101 Firefox/121.0",
+			Client{Browser: "Firefox", BrowserVersion: "121.0", Os: "macOS", OsVersion: "10.15", Device: rule.DeviceDesktop}),
+		Entry("crawler",
+			"Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)",
+			Client{Browser: "Googlebot", BrowserVersion: "2.1", Device: rule.DeviceBot}),
+		Entry("curl", "curl/8.4.0",
+			Client{Browser: "curl", BrowserVersion: "8.4", Device: rule.DeviceBot}),
+		Entry("unknown client", "SomeClient",
+			Client{Device: rule.DeviceDesktop}),
+		Entry("empty header", "", Client{}),
+	)
+
+	It("should reuse cached results", func() {
+		userAgent := "curl/8.4.0"
+		first := parser.Parse(userAgent)
+
+		Expect(parser.cache.Len()).To(Equal(1))
+		Expect(parser.Parse(userAgent)).To(Equal(first))
+		Expect(parser.cache.Len()).To(Equal(1))
+	})
+
+	It("should load custom rules with replacements", func() {
+		custom, err := NewParserWithRules([]byte(`
+user_agent_parsers:
+  - regex: '(Acme)Browser/(\d+)\.(\d+)'
+    family_replacement: '$1 Browser'
+device_parsers:
+  - regex: 'acme'
+    regex_flag: 'i'
+    device_replacement: 'Spider'
+`), 1)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(custom.Parse("AcmeBrowser/3.2")).To(Equal(Client{Browser: "Acme Browser", BrowserVersion: "3.2", Device: rule.DeviceBot}))
+	})
+
+	It("should reject rules unsupported by regexp", func() {
+		_, err := NewParserWithRules([]byte(`
+os_parsers:
+  - regex: 'Android(?!.*Mobile)'
+`), 1)
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,10 @@
+CREATE TABLE domains (
+    hostname TEXT PRIMARY KEY,
+    scheme TEXT NOT NULL DEFAULT 'https',
+    verification_token TEXT NOT NULL,
+    verified BOOLEAN NOT NULL DEFAULT FALSE,
+    verified_date DATETIME NULL,
+    created_date DATETIME NOT NULL
+);
+
+ALTER TABLE urls ADD COLUMN domain TEXT NOT NULL DEFAULT '';
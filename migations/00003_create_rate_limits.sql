@@ -0,0 +1,5 @@
+CREATE TABLE rate_limits (
+    key TEXT PRIMARY KEY,
+    tokens DOUBLE PRECISION NOT NULL,
+    updated_date TIMESTAMP NOT NULL
+);
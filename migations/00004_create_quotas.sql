@@ -0,0 +1,19 @@
+ALTER TABLE urls ADD COLUMN owner_id TEXT NOT NULL DEFAULT '';
+ALTER TABLE urls ADD COLUMN custom_id BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE INDEX urls_owner_id_idx ON urls (owner_id);
+
+CREATE TABLE quotas (
+    owner_id TEXT PRIMARY KEY,
+    max_links INTEGER NOT NULL DEFAULT 0,
+    max_custom_aliases INTEGER NOT NULL DEFAULT 0,
+    max_monthly_clicks INTEGER NOT NULL DEFAULT 0,
+    allow_custom_id BOOLEAN NOT NULL DEFAULT TRUE
+);
+
+CREATE TABLE monthly_clicks (
+    owner_id TEXT NOT NULL,
+    period TEXT NOT NULL,
+    clicks INTEGER NOT NULL DEFAULT 0,
+    PRIMARY KEY (owner_id, period)
+);
@@ -0,0 +1 @@
+ALTER TABLE urls ADD COLUMN redirect_type TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,2 @@
+ALTER TABLE urls ADD COLUMN query_mode TEXT NOT NULL DEFAULT '';
+ALTER TABLE urls ADD COLUMN forward_path BOOLEAN NOT NULL DEFAULT FALSE;
@@ -0,0 +1,6 @@
+ALTER TABLE urls ADD COLUMN utm_source TEXT NOT NULL DEFAULT '';
+ALTER TABLE urls ADD COLUMN utm_medium TEXT NOT NULL DEFAULT '';
+ALTER TABLE urls ADD COLUMN utm_campaign TEXT NOT NULL DEFAULT '';
+ALTER TABLE urls ADD COLUMN utm_term TEXT NOT NULL DEFAULT '';
+ALTER TABLE urls ADD COLUMN utm_content TEXT NOT NULL DEFAULT '';
+CREATE INDEX urls_utm_campaign_idx ON urls (utm_campaign);
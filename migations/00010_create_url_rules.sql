@@ -0,0 +1,9 @@
+CREATE TABLE url_rules (
+    url_id TEXT NOT NULL REFERENCES urls (id) ON DELETE CASCADE,
+    position INTEGER NOT NULL,
+    condition JSONB NOT NULL,
+    target_url TEXT NOT NULL,
+    PRIMARY KEY (url_id, position)
+);
+
+ALTER TABLE urls ADD COLUMN has_rules BOOLEAN NOT NULL DEFAULT FALSE;
@@ -0,0 +1,11 @@
+CREATE TABLE url_variants (
+    url_id TEXT NOT NULL REFERENCES urls (id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    target_url TEXT NOT NULL,
+    weight INTEGER NOT NULL,
+    click_count INTEGER NOT NULL DEFAULT 0,
+    position INTEGER NOT NULL,
+    PRIMARY KEY (url_id, name)
+);
+
+ALTER TABLE urls ADD COLUMN split_mode TEXT NOT NULL DEFAULT '';
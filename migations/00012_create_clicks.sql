@@ -0,0 +1,9 @@
+CREATE TABLE clicks (
+    url_id TEXT NOT NULL REFERENCES urls (id) ON DELETE CASCADE,
+    created_date DATETIME NOT NULL,
+    country TEXT NOT NULL DEFAULT '',
+    region TEXT NOT NULL DEFAULT '',
+    asn INTEGER NOT NULL DEFAULT 0
+);
+
+CREATE INDEX clicks_url_id_created_date_idx ON clicks (url_id, created_date);
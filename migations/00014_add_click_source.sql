@@ -0,0 +1 @@
+ALTER TABLE clicks ADD COLUMN source TEXT NOT NULL DEFAULT '';
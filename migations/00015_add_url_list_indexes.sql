@@ -0,0 +1,3 @@
+CREATE INDEX urls_created_date_id_idx ON urls (created_date, id);
+CREATE INDEX urls_click_count_id_idx ON urls (click_count, id);
+CREATE INDEX urls_owner_id_created_date_idx ON urls (owner_id, created_date, id);
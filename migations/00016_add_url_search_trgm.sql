@@ -0,0 +1,6 @@
+-- Только для Postgres: триграммные индексы ускоряют поиск ILIKE '%...%' по адресу и id.
+-- В SQLite поиск по подстроке выполняется полным просмотром, миграцию нужно пропустить.
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+CREATE INDEX urls_original_url_trgm_idx ON urls USING GIN (original_url gin_trgm_ops);
+CREATE INDEX urls_id_trgm_idx ON urls USING GIN (id gin_trgm_ops);
@@ -0,0 +1,10 @@
+ALTER TABLE urls ADD COLUMN title TEXT NOT NULL DEFAULT '';
+ALTER TABLE urls ADD COLUMN notes TEXT NOT NULL DEFAULT '';
+
+CREATE TABLE url_tags (
+    url_id TEXT NOT NULL REFERENCES urls (id) ON DELETE CASCADE,
+    tag TEXT NOT NULL,
+    PRIMARY KEY (url_id, tag)
+);
+
+CREATE INDEX url_tags_tag_idx ON url_tags (tag);
@@ -0,0 +1,2 @@
+-- Только для Postgres, как и 00016: поиск по названию ссылки.
+CREATE INDEX urls_title_trgm_idx ON urls USING GIN (title gin_trgm_ops);
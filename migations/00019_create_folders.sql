@@ -0,0 +1,12 @@
+CREATE TABLE folders (
+    id TEXT PRIMARY KEY,
+    owner_id TEXT NOT NULL,
+    parent_id TEXT NOT NULL DEFAULT '',
+    name TEXT NOT NULL,
+    created_date DATETIME NOT NULL
+);
+
+CREATE UNIQUE INDEX folders_owner_id_parent_id_name_idx ON folders (owner_id, parent_id, name);
+
+ALTER TABLE urls ADD COLUMN folder_id TEXT NOT NULL DEFAULT '';
+CREATE INDEX urls_folder_id_idx ON urls (folder_id);
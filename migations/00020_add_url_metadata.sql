@@ -0,0 +1,5 @@
+ALTER TABLE urls ADD COLUMN meta_title TEXT NOT NULL DEFAULT '';
+ALTER TABLE urls ADD COLUMN meta_description TEXT NOT NULL DEFAULT '';
+ALTER TABLE urls ADD COLUMN meta_image TEXT NOT NULL DEFAULT '';
+ALTER TABLE urls ADD COLUMN meta_site_name TEXT NOT NULL DEFAULT '';
+ALTER TABLE urls ADD COLUMN meta_favicon TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,7 @@
+ALTER TABLE urls ADD COLUMN health_status INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE urls ADD COLUMN health_latency_ms INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE urls ADD COLUMN health_final_url TEXT NOT NULL DEFAULT '';
+ALTER TABLE urls ADD COLUMN health_error TEXT NOT NULL DEFAULT '';
+ALTER TABLE urls ADD COLUMN health_checked_date DATETIME NULL;
+
+CREATE INDEX urls_health_checked_date_idx ON urls (health_checked_date);
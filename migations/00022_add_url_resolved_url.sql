@@ -0,0 +1 @@
+ALTER TABLE urls ADD COLUMN resolved_url TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,3 @@
+-- Ключ есть только у ссылок, которые можно выдать повторно; NULL не участвует в уникальности
+ALTER TABLE urls ADD COLUMN dedup_key TEXT NULL;
+CREATE UNIQUE INDEX urls_dedup_key_idx ON urls (dedup_key);
@@ -0,0 +1,6 @@
+-- Только для Postgres: корзины в таблице rate_limits хранит только postgresStore.
+-- В SQLite тип столбца времени не меняется, миграцию нужно пропустить.
+-- Время корзин хранится с часовым поясом, прежние значения считаются записанными в UTC
+ALTER TABLE rate_limits ALTER COLUMN updated_date TYPE TIMESTAMPTZ USING updated_date AT TIME ZONE 'UTC';
+-- Индекс для удаления давно не используемых корзин
+CREATE INDEX rate_limits_updated_date_idx ON rate_limits (updated_date);
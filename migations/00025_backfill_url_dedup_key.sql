@@ -0,0 +1,51 @@
+-- Только для Postgres: regexp_match, ~ и UPDATE ... FROM в SQLite недоступны, миграцию нужно пропустить.
+-- В SQLite ссылки, созданные до появления ключа, просто не выдаются повторно.
+-- Ключ повторной выдачи для ссылок, созданных до его появления, по правилам url.DedupKey с политикой reuse.
+-- Ключ получают только ссылки без своего id и без настроек перехода, из ссылок с одинаковым ключом - самая ранняя.
+-- Адреса, которые url.Normalize мог бы записать иначе (экранирование, данные пользователя в адресе), пропускаются.
+WITH addresses AS (
+    SELECT id, domain, created_date,
+           CASE WHEN position('://' IN btrim(original_url)) > 0 THEN btrim(original_url)
+                ELSE 'https://' || btrim(original_url) END AS address
+    FROM urls
+    WHERE dedup_key IS NULL
+      AND custom_id = FALSE
+      AND password_hash = '' AND interstitial = FALSE AND redirect_type = ''
+      AND query_mode = '' AND forward_path = FALSE
+      AND utm_source = '' AND utm_medium = '' AND utm_campaign = '' AND utm_term = '' AND utm_content = ''
+      AND has_rules = FALSE AND split_mode = ''
+),
+parts AS (
+    -- 1 - схема, 2 - хост с портом, 3 - порт, 4 - путь, запрос и фрагмент
+    SELECT id, domain, created_date,
+           regexp_match(address, '^([A-Za-z][A-Za-z0-9+.-]*)://([A-Za-z0-9.-]+(:[0-9]+)?)([/?#].*)?$') AS part
+    FROM addresses
+    WHERE address ~ '^[A-Za-z0-9._~:/?#!$&''()*+,;=%-]+$'
+),
+normalized AS (
+    SELECT id, domain, created_date,
+           lower(part[1]) || '://' ||
+           CASE WHEN (lower(part[1]) = 'https' AND part[3] = ':443') OR (lower(part[1]) = 'http' AND part[3] = ':80')
+                THEN lower(left(part[2], length(part[2]) - length(part[3])))
+                ELSE lower(part[2]) END ||
+           CASE WHEN coalesce(part[4], '') NOT LIKE '/%' THEN '/' ELSE '' END ||
+           coalesce(part[4], '') AS address
+    FROM parts
+    WHERE part IS NOT NULL
+),
+keys AS (
+    SELECT id, created_date,
+           CASE WHEN domain <> '' THEN domain || ' ' || address ELSE address END AS dedup_key
+    FROM normalized
+),
+earliest AS (
+    SELECT id, dedup_key,
+           row_number() OVER (PARTITION BY dedup_key ORDER BY created_date, id) AS position
+    FROM keys
+)
+UPDATE urls
+SET dedup_key = earliest.dedup_key
+FROM earliest
+WHERE urls.id = earliest.id
+  AND earliest.position = 1
+  AND NOT EXISTS (SELECT 1 FROM urls existing WHERE existing.dedup_key = earliest.dedup_key);
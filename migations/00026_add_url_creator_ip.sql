@@ -0,0 +1,3 @@
+-- Адрес создателя анонимной ссылки для квоты анонимных ссылок на адрес
+ALTER TABLE urls ADD COLUMN creator_ip TEXT NOT NULL DEFAULT '';
+CREATE INDEX urls_creator_ip_created_date_idx ON urls (creator_ip, created_date) WHERE owner_id = '';